@@ -12,6 +12,7 @@ import (
 
 	"github.com/Its-donkey/Sharpen-live/internal/alert/config"
 	uiserver "github.com/Its-donkey/Sharpen-live/internal/ui/server"
+	"github.com/Its-donkey/Sharpen-live/ui"
 )
 
 func main() {
@@ -36,11 +37,29 @@ func main() {
 	dataDir := flag.String("data", "", "directory for data files (streamers/submissions); defaults to config.json app.data")
 	configPath := flag.String("config", "config.json", "path to server configuration")
 	site := flag.String("site", "", "site key to serve (defaults to all configured sites when empty)")
+	vhost := flag.Bool("vhost", false, "serve every site from one listener, routing by Host header (also enabled by config.json virtual_hosts.enabled)")
 	twitchClientID := flag.String("twitch-client-id", "", "Twitch Client ID (used when TWITCH_CLIENT_ID is unset)")
 	twitchClientSecret := flag.String("twitch-client-secret", "", "Twitch Client Secret (used when TWITCH_CLIENT_SECRET is unset)")
 	youtubeAPIKey := flag.String("youtube-api-key", "", "YouTube API Key (used when YOUTUBE_API_KEY is unset)")
+	themeDir := flag.String("theme-dir", "", "directory of theme files (laid out like ui/) that override the embedded ui/sites and ui/static; defaults to the embedded files only")
+	dumpAssets := flag.String("dump-assets", "", "write the embedded ui/sites and ui/static tree into this directory (e.g. ui) and exit; existing files are kept")
 	flag.Parse()
 
+	if dir := strings.TrimSpace(*dumpAssets); dir != "" {
+		written, skipped, err := ui.Dump(dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "dump assets: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("wrote %d embedded files to %s (%d existing files kept)\n", written, dir, skipped)
+		return
+	}
+
+	if err := uiserver.SetThemeDir(*themeDir); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
 	ensureEnv("TWITCH_CLIENT_ID", *twitchClientID)
 	ensureEnv("TWITCH_CLIENT_SECRET", *twitchClientSecret)
 	ensureEnv("YOUTUBE_API_KEY", *youtubeAPIKey)
@@ -93,6 +112,43 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *vhost || loadedConfig.VirtualHosts.Enabled {
+		if siteRequested {
+			fmt.Fprintln(os.Stderr, "virtual-host mode serves every configured site; -site cannot be combined with it")
+			os.Exit(1)
+		}
+		if *templatesDir != "" || *assetsDir != "" || *dataDir != "" {
+			fmt.Fprintln(os.Stderr, "path overrides are not supported in virtual-host mode")
+			os.Exit(1)
+		}
+		var sites []uiserver.Options
+		for _, target := range siteTargets {
+			if target.cfg.Key == config.AlertserverKey {
+				continue
+			}
+			sites = append(sites, uiserver.Options{
+				ConfigPath:     *configPath,
+				Site:           target.cfg.Key,
+				FallbackErrors: target.errors,
+			})
+		}
+		err := uiserver.RunVirtualHosts(ctx, uiserver.VirtualHostOptions{
+			Listen:     *listen,
+			ConfigPath: *configPath,
+			Sites:      sites,
+			Fallback: &uiserver.Options{
+				ConfigPath:     *configPath,
+				Site:           config.AlertserverKey,
+				FallbackErrors: fallbackErrors,
+			},
+		})
+		if err != nil && !errors.Is(err, context.Canceled) {
+			fmt.Fprintf(os.Stderr, "server error (virtual hosts): %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if len(siteTargets) > 1 {
 		if *listen != "" || *templatesDir != "" || *assetsDir != "" || *dataDir != "" {
 			fmt.Fprintln(os.Stderr, "path/listen overrides require -site to target a single site")
@@ -31,12 +31,19 @@ const (
 type ActionRequest struct {
 	Action Action `json:"action"`
 	ID     string `json:"id"`
+	// Reason optionally explains a rejection to the submitter.
+	Reason string `json:"reason,omitempty"`
 }
 
 // ActionResult contains the final status for the processed submission.
 type ActionResult struct {
 	Status     Action                 `json:"status"`
 	Submission submissions.Submission `json:"submission"`
+	// Reason is the trimmed rejection reason, empty for approvals.
+	Reason string `json:"reason,omitempty"`
+	// Record is the roster entry an approval saved, or the shared record it
+	// was merged into; nil for rejections.
+	Record *streamers.Record `json:"record,omitempty"`
 }
 
 // SubmissionsOptions configures the SubmissionsService.
@@ -50,6 +57,8 @@ type SubmissionsOptions struct {
 	TwitchClientSecret      string
 	TwitchEventSubSecret    string
 	TwitchEventSubCallback  string
+	// OnApproved is called with the roster record after an approval is saved.
+	OnApproved func(streamers.Record)
 }
 
 // SubmissionsService encapsulates streamer submission review logic.
@@ -63,6 +72,7 @@ type SubmissionsService struct {
 	twitchClientSecret      string
 	twitchEventSubSecret    string
 	twitchEventSubCallback  string
+	onApproved              func(streamers.Record)
 }
 
 // NewSubmissionsService constructs a SubmissionsService with the provided options.
@@ -85,6 +95,7 @@ func NewSubmissionsService(opts SubmissionsOptions) *SubmissionsService {
 		twitchClientSecret:     opts.TwitchClientSecret,
 		twitchEventSubSecret:   opts.TwitchEventSubSecret,
 		twitchEventSubCallback: opts.TwitchEventSubCallback,
+		onApproved:             opts.OnApproved,
 	}
 	return svc
 }
@@ -138,7 +149,8 @@ func (s *SubmissionsService) Process(ctx context.Context, req ActionRequest) (Ac
 
 	if action == ActionApprove {
 		fmt.Printf("\n>>> ACTION IS APPROVE - Starting approval process...\n")
-		if err := s.approve(ctx, removed); err != nil {
+		record, err := s.approve(ctx, removed)
+		if err != nil {
 			fmt.Printf("\nERROR: Approval process failed: %v\n", err)
 			fmt.Printf("INFO: Re-queueing submission to submissions store...\n")
 			// requeue the submission when approval fails
@@ -150,14 +162,14 @@ func (s *SubmissionsService) Process(ctx context.Context, req ActionRequest) (Ac
 		fmt.Printf("========================================\n")
 		fmt.Printf("=== PROCESS SUBMISSION REQUEST END (success) ===\n")
 		fmt.Printf("========================================\n\n")
-		return ActionResult{Status: ActionApprove, Submission: removed}, nil
+		return ActionResult{Status: ActionApprove, Submission: removed, Record: &record}, nil
 	}
 
 	fmt.Printf("\n>>> ACTION IS REJECT - Submission rejected\n")
 	fmt.Printf("========================================\n")
 	fmt.Printf("=== PROCESS SUBMISSION REQUEST END (success) ===\n")
 	fmt.Printf("========================================\n\n")
-	return ActionResult{Status: ActionReject, Submission: removed}, nil
+	return ActionResult{Status: ActionReject, Submission: removed, Reason: strings.TrimSpace(req.Reason)}, nil
 }
 
 func (s *SubmissionsService) ensureStores() error {
@@ -173,7 +185,7 @@ func (s *SubmissionsService) ensureStores() error {
 	return nil
 }
 
-func (s *SubmissionsService) approve(ctx context.Context, submission submissions.Submission) error {
+func (s *SubmissionsService) approve(ctx context.Context, submission submissions.Submission) (streamers.Record, error) {
 	fmt.Printf("\n=== APPROVE SUBMISSION START ===\n")
 	fmt.Printf("Submission ID: %s\n", submission.ID)
 	fmt.Printf("Submission Alias: %s\n", submission.Alias)
@@ -194,7 +206,11 @@ func (s *SubmissionsService) approve(ctx context.Context, submission submissions
 	fmt.Printf("  Description: %s\n", record.Streamer.Description)
 	fmt.Printf("  Languages: %v\n", record.Streamer.Languages)
 
-	// Map platform details
+	// Resolve every channel before subscribing to anything, so a shared
+	// roster match is known before a hub lease or EventSub subscription is
+	// created for a channel that is already tracked.
+	var youtubeChannelID, youtubeURL, twitchUsername string
+	var hasYouTube bool
 	if submission.Platforms != nil {
 		fmt.Printf("\nINFO: Processing %d platform(s)...\n", len(submission.Platforms))
 		for platformKey, platformInfo := range submission.Platforms {
@@ -212,96 +228,20 @@ func (s *SubmissionsService) approve(ctx context.Context, submission submissions
 			switch {
 			case p == "youtube" || strings.Contains(p, "youtube"):
 				fmt.Printf("\n*** YouTube Platform Detected ***\n")
-				channelURL := strings.TrimSpace(platformInfo.URL)
-				channelID := strings.TrimSpace(platformInfo.ChannelID)
-
-				fmt.Printf("INFO: Processing YouTube platform for submission\n")
-				fmt.Printf("  URL: %s\n", channelURL)
-				fmt.Printf("  Channel ID from submission: %s\n", channelID)
-
-				if channelID == "" {
-					fmt.Printf("INFO: Channel ID not in submission, attempting URL extraction...\n")
-					channelID = extractYouTubeChannelID(channelURL)
-					fmt.Printf("  Channel ID from URL extraction: %s\n", channelID)
-				}
-				if channelID == "" && s.metadataService != nil {
-					fmt.Printf("INFO: Attempting to fetch channel ID via metadata service...\n")
-					if meta, err := s.metadataService.Fetch(ctx, channelURL); err == nil && meta != nil {
-						if cid := strings.TrimSpace(meta.ChannelID); cid != "" {
-							channelID = cid
-							fmt.Printf("SUCCESS: Channel ID from metadata: %s\n", channelID)
-						} else {
-							fmt.Printf("WARNING: Metadata service returned no channel ID\n")
-						}
-					} else if err != nil {
-						fmt.Printf("ERROR: Metadata fetch error: %v\n", err)
-					}
-				} else if channelID == "" && s.metadataService == nil {
-					fmt.Printf("WARNING: No metadata service available for channel ID lookup\n")
-				}
-
-				ytPlatform := &streamers.YouTubePlatform{
-					ChannelID:  channelID,
-					ChannelURL: channelURL,
-				}
-				fmt.Printf("INFO: Created initial YouTubePlatform struct\n")
-
-				if channelID != "" {
-					fmt.Printf("\nINFO: Channel ID available (%s), proceeding with WebSub setup...\n", channelID)
-					if subscribed, err := s.setupYouTubeWebSub(ctx, channelID, channelURL); err != nil {
-						fmt.Printf("\nERROR: setupYouTubeWebSub failed for channel %s: %v\n", channelID, err)
-						fmt.Printf("WARNING: Continuing with approval but WebSub subscription may not be active\n")
-					} else if subscribed != nil {
-						ytPlatform = subscribed
-						fmt.Printf("\nSUCCESS: WebSub setup completed, platform data updated\n")
-						fmt.Printf("  WebSubSubscribed: %v\n", ytPlatform.WebSubSubscribed)
-						fmt.Printf("  WebSubHubURL: %s\n", ytPlatform.WebSubHubURL)
-						fmt.Printf("  WebSubTopicURL: %s\n", ytPlatform.WebSubTopicURL)
-						fmt.Printf("  WebSubCallbackURL: %s\n", ytPlatform.WebSubCallbackURL)
-					}
-				} else {
-					fmt.Printf("\nWARNING: No channel ID available for YouTube platform\n")
-					fmt.Printf("WARNING: WebSub subscription will NOT be set up\n")
-					fmt.Printf("WARNING: The streamer will be saved but alerts may not work\n")
-				}
-				record.Platforms.YouTube = ytPlatform
-				fmt.Printf("\nINFO: YouTube platform configured in record\n")
-				fmt.Printf("  Final ChannelID: %s\n", record.Platforms.YouTube.ChannelID)
-				fmt.Printf("  Final WebSubSubscribed: %v\n", record.Platforms.YouTube.WebSubSubscribed)
+				hasYouTube = true
+				youtubeURL = strings.TrimSpace(platformInfo.URL)
+				youtubeChannelID = s.resolveYouTubeChannelID(ctx, youtubeURL, strings.TrimSpace(platformInfo.ChannelID))
 
 			case p == "twitch" || strings.Contains(p, "twitch"):
 				fmt.Printf("\n*** Twitch Platform Detected ***\n")
 				twitchURL := strings.TrimSpace(platformInfo.URL)
-				fmt.Printf("INFO: Processing Twitch platform for submission\n")
 				fmt.Printf("  URL: %s\n", twitchURL)
-
-				username := extractTwitchUsername(twitchURL)
-				fmt.Printf("  Extracted Username: %s\n", username)
-
-				if username == "" {
+				twitchUsername = extractTwitchUsername(twitchURL)
+				fmt.Printf("  Extracted Username: %s\n", twitchUsername)
+				if twitchUsername == "" {
 					fmt.Printf("WARNING: Could not extract Twitch username from URL\n")
-					break
 				}
 
-				twitchPlatform, err := s.setupTwitchEventSub(ctx, username)
-				if err != nil {
-					fmt.Printf("ERROR: setupTwitchEventSub failed: %v\n", err)
-					fmt.Printf("WARNING: Continuing with approval but EventSub may not be active\n")
-					// Use returned platform if available (preserves broadcaster ID),
-					// otherwise create minimal platform with just username
-					if twitchPlatform == nil {
-						twitchPlatform = &streamers.TwitchPlatform{
-							Username: username,
-						}
-					}
-				}
-
-				record.Platforms.Twitch = twitchPlatform
-				fmt.Printf("\nINFO: Twitch platform configured in record\n")
-				fmt.Printf("  Final Username: %s\n", record.Platforms.Twitch.Username)
-				fmt.Printf("  Final BroadcasterID: %s\n", record.Platforms.Twitch.BroadcasterID)
-				fmt.Printf("  Final EventSubSubscribed: %v\n", record.Platforms.Twitch.EventSubSubscribed)
-
 			// case p == "facebook" || strings.Contains(p, "facebook"):
 			// 	pageID := inferFacebookPageID(platformInfo.URL, platformInfo.Handle, platformInfo.Label)
 			// 	if pageID != "" && record.Platforms.Facebook == nil {
@@ -315,12 +255,112 @@ func (s *SubmissionsService) approve(ctx context.Context, submission submissions
 		fmt.Printf("\nWARNING: No platforms provided in submission\n")
 	}
 
+	// existing is set when a shared roster already tracks one of the channels.
+	existing, err := s.sharedMatch(youtubeChannelID, twitchUsername)
+	if err != nil {
+		fmt.Printf("\nERROR: %v\n", err)
+		fmt.Printf("=== APPROVE SUBMISSION END (failed) ===\n\n")
+		return streamers.Record{}, err
+	}
+	if existing != nil {
+		fmt.Printf("INFO: Submission matches %s on the shared roster; reusing its subscriptions\n", existing.Streamer.ID)
+		var conflict error
+		if hasYouTube && existing.Platforms.YouTube != nil && !strings.EqualFold(strings.TrimSpace(existing.Platforms.YouTube.ChannelID), youtubeChannelID) {
+			conflict = fmt.Errorf("%w: %s already has a different YouTube channel", ErrPlatformConflict, existing.Streamer.ID)
+		} else if twitchUsername != "" && existing.Platforms.Twitch != nil && !strings.EqualFold(strings.TrimSpace(existing.Platforms.Twitch.Username), twitchUsername) {
+			conflict = fmt.Errorf("%w: %s already has a different Twitch channel", ErrPlatformConflict, existing.Streamer.ID)
+		}
+		if conflict != nil {
+			fmt.Printf("\nERROR: %v\n", conflict)
+			fmt.Printf("=== APPROVE SUBMISSION END (failed) ===\n\n")
+			return streamers.Record{}, conflict
+		}
+	}
+
+	if hasYouTube && (existing == nil || existing.Platforms.YouTube == nil) {
+		ytPlatform := &streamers.YouTubePlatform{
+			ChannelID:  youtubeChannelID,
+			ChannelURL: youtubeURL,
+		}
+		if youtubeChannelID != "" {
+			fmt.Printf("\nINFO: Channel ID available (%s), proceeding with WebSub setup...\n", youtubeChannelID)
+			if subscribed, err := s.setupYouTubeWebSub(ctx, youtubeChannelID, youtubeURL); err != nil {
+				fmt.Printf("\nERROR: setupYouTubeWebSub failed for channel %s: %v\n", youtubeChannelID, err)
+				fmt.Printf("WARNING: Continuing with approval but WebSub subscription may not be active\n")
+			} else if subscribed != nil {
+				ytPlatform = subscribed
+				fmt.Printf("\nSUCCESS: WebSub setup completed, platform data updated\n")
+				fmt.Printf("  WebSubSubscribed: %v\n", ytPlatform.WebSubSubscribed)
+				fmt.Printf("  WebSubHubURL: %s\n", ytPlatform.WebSubHubURL)
+				fmt.Printf("  WebSubTopicURL: %s\n", ytPlatform.WebSubTopicURL)
+				fmt.Printf("  WebSubCallbackURL: %s\n", ytPlatform.WebSubCallbackURL)
+			}
+		} else {
+			fmt.Printf("\nWARNING: No channel ID available for YouTube platform\n")
+			fmt.Printf("WARNING: WebSub subscription will NOT be set up\n")
+			fmt.Printf("WARNING: The streamer will be saved but alerts may not work\n")
+		}
+		record.Platforms.YouTube = ytPlatform
+		fmt.Printf("\nINFO: YouTube platform configured in record\n")
+		fmt.Printf("  Final ChannelID: %s\n", record.Platforms.YouTube.ChannelID)
+		fmt.Printf("  Final WebSubSubscribed: %v\n", record.Platforms.YouTube.WebSubSubscribed)
+	}
+
+	if twitchUsername != "" && (existing == nil || existing.Platforms.Twitch == nil) {
+		twitchPlatform, err := s.setupTwitchEventSub(ctx, twitchUsername)
+		if err != nil {
+			fmt.Printf("ERROR: setupTwitchEventSub failed: %v\n", err)
+			fmt.Printf("WARNING: Continuing with approval but EventSub may not be active\n")
+			// Use returned platform if available (preserves broadcaster ID),
+			// otherwise create minimal platform with just username
+			if twitchPlatform == nil {
+				twitchPlatform = &streamers.TwitchPlatform{
+					Username: twitchUsername,
+				}
+			}
+		}
+
+		record.Platforms.Twitch = twitchPlatform
+		fmt.Printf("\nINFO: Twitch platform configured in record\n")
+		fmt.Printf("  Final Username: %s\n", record.Platforms.Twitch.Username)
+		fmt.Printf("  Final BroadcasterID: %s\n", record.Platforms.Twitch.BroadcasterID)
+		fmt.Printf("  Final EventSubSubscribed: %v\n", record.Platforms.Twitch.EventSubSubscribed)
+	}
+
+	if existing != nil {
+		shared := s.streamersStore.Shared()
+		if record.Platforms.YouTube != nil || record.Platforms.Twitch != nil {
+			fmt.Printf("\n--- Adding new platforms to existing streamer %s ---\n", existing.Streamer.ID)
+			if _, err := shared.AddPlatforms(existing.Streamer.ID, record.Platforms); err != nil {
+				fmt.Printf("\nERROR: Failed to add platforms to streamer record: %v\n", err)
+				fmt.Printf("=== APPROVE SUBMISSION END (failed) ===\n\n")
+				return streamers.Record{}, err
+			}
+		}
+		site := s.streamersStore.Site()
+		fmt.Printf("\n--- Adding site %s to existing streamer %s ---\n", site, existing.Streamer.ID)
+		copied, err := shared.CopyToSite(existing.Streamer.ID, site)
+		if err != nil {
+			fmt.Printf("\nERROR: Failed to add site to streamer record: %v\n", err)
+			fmt.Printf("=== APPROVE SUBMISSION END (failed) ===\n\n")
+			return streamers.Record{}, err
+		}
+		if record.Platforms.YouTube != nil && record.Platforms.YouTube.ChannelID != "" {
+			if err := s.checkAndUpdateStreamStatus(ctx, copied); err != nil {
+				fmt.Printf("WARNING: Failed to check initial stream status: %v\n", err)
+			}
+		}
+		s.approved(copied)
+		fmt.Printf("\n=== APPROVE SUBMISSION END (shared) ===\n\n")
+		return copied, nil
+	}
+
 	fmt.Printf("\n--- Saving streamer record to store ---\n")
 	saved, err := s.streamersStore.Append(record)
 	if err != nil {
 		fmt.Printf("\nERROR: Failed to save streamer record: %v\n", err)
 		fmt.Printf("=== APPROVE SUBMISSION END (failed) ===\n\n")
-		return err
+		return streamers.Record{}, err
 	}
 
 	// Verify the record was saved correctly
@@ -358,8 +398,79 @@ func (s *SubmissionsService) approve(ctx context.Context, submission submissions
 		}
 	}
 
+	s.approved(saved)
 	fmt.Printf("\n=== APPROVE SUBMISSION END (success) ===\n\n")
-	return nil
+	return saved, nil
+}
+
+// approved reports a saved approval to the OnApproved hook, if any.
+func (s *SubmissionsService) approved(record streamers.Record) {
+	if s.onApproved != nil {
+		s.onApproved(record)
+	}
+}
+
+// resolveYouTubeChannelID returns the submitted channel ID, falling back to
+// the channel URL and then the metadata service.
+func (s *SubmissionsService) resolveYouTubeChannelID(ctx context.Context, channelURL, channelID string) string {
+	fmt.Printf("INFO: Processing YouTube platform for submission\n")
+	fmt.Printf("  URL: %s\n", channelURL)
+	fmt.Printf("  Channel ID from submission: %s\n", channelID)
+
+	if channelID == "" {
+		fmt.Printf("INFO: Channel ID not in submission, attempting URL extraction...\n")
+		channelID = extractYouTubeChannelID(channelURL)
+		fmt.Printf("  Channel ID from URL extraction: %s\n", channelID)
+	}
+	if channelID == "" && s.metadataService != nil {
+		fmt.Printf("INFO: Attempting to fetch channel ID via metadata service...\n")
+		if meta, err := s.metadataService.Fetch(ctx, channelURL); err == nil && meta != nil {
+			if cid := strings.TrimSpace(meta.ChannelID); cid != "" {
+				channelID = cid
+				fmt.Printf("SUCCESS: Channel ID from metadata: %s\n", channelID)
+			} else {
+				fmt.Printf("WARNING: Metadata service returned no channel ID\n")
+			}
+		} else if err != nil {
+			fmt.Printf("ERROR: Metadata fetch error: %v\n", err)
+		}
+	} else if channelID == "" && s.metadataService == nil {
+		fmt.Printf("WARNING: No metadata service available for channel ID lookup\n")
+	}
+	return channelID
+}
+
+// sharedMatch returns the shared roster record already tracking the
+// submission's YouTube channel or Twitch user, or nil when neither is on it.
+// Channels held by two different records cannot be merged into one.
+func (s *SubmissionsService) sharedMatch(youtubeChannelID, twitchUsername string) (*streamers.Record, error) {
+	youtube, onYouTube := s.sharedRosterRecord(youtubeChannelID, "")
+	twitch, onTwitch := s.sharedRosterRecord("", twitchUsername)
+	switch {
+	case onYouTube && onTwitch && !strings.EqualFold(youtube.Streamer.ID, twitch.Streamer.ID):
+		return nil, fmt.Errorf("%w: the YouTube channel belongs to %s and the Twitch channel to %s", ErrPlatformConflict, youtube.Streamer.ID, twitch.Streamer.ID)
+	case onYouTube:
+		return &youtube, nil
+	case onTwitch:
+		return &twitch, nil
+	}
+	return nil, nil
+}
+
+// sharedRosterRecord returns the shared roster record already tracking the
+// channel, so approving a second site's submission reuses its subscriptions.
+func (s *SubmissionsService) sharedRosterRecord(youtubeChannelID, twitchUsername string) (streamers.Record, bool) {
+	if s.streamersStore.Site() == "" {
+		return streamers.Record{}, false
+	}
+	if strings.TrimSpace(youtubeChannelID) == "" && strings.TrimSpace(twitchUsername) == "" {
+		return streamers.Record{}, false
+	}
+	record, err := s.streamersStore.FindByChannel(youtubeChannelID, twitchUsername)
+	if err != nil {
+		return streamers.Record{}, false
+	}
+	return record, true
 }
 
 // setupYouTubeWebSub sets up a WebSub subscription for a YouTube channel
@@ -528,6 +639,9 @@ var (
 	ErrInvalidAction = errors.New("action must be approve or reject")
 	// ErrMissingIdentifier signals that the submission ID was omitted.
 	ErrMissingIdentifier = errors.New("submission id is required")
+	// ErrPlatformConflict signals that a submission's channels cannot be
+	// merged into the shared roster record that already tracks one of them.
+	ErrPlatformConflict = errors.New("submission conflicts with the shared roster")
 )
 
 // extractTwitchUsername extracts a Twitch username from various URL formats
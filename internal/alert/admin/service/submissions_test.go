@@ -21,13 +21,16 @@ func TestSubmissionsServiceReject(t *testing.T) {
 		SubmissionsStore: subStore,
 		StreamersStore:   streamStore,
 	})
-	result, err := svc.Process(context.Background(), ActionRequest{Action: ActionReject, ID: "sub_1"})
+	result, err := svc.Process(context.Background(), ActionRequest{Action: ActionReject, ID: "sub_1", Reason: " Not a streamer "})
 	if err != nil {
 		t.Fatalf("process reject: %v", err)
 	}
 	if result.Status != ActionReject {
 		t.Fatalf("expected reject status, got %s", result.Status)
 	}
+	if result.Reason != "Not a streamer" {
+		t.Fatalf("expected the rejection reason, got %q", result.Reason)
+	}
 	remaining, err := subStore.List()
 	if err != nil {
 		t.Fatalf("list submissions: %v", err)
@@ -72,6 +75,155 @@ func TestSubmissionsServiceApprove(t *testing.T) {
 	}
 }
 
+func TestSubmissionsServiceApproveReusesSharedChannel(t *testing.T) {
+	dir := t.TempDir()
+	shared := streamers.NewStore(filepath.Join(dir, "streamers.json"))
+	if _, err := shared.Append(streamers.Record{
+		Streamer:  streamers.Streamer{ID: "existing", Alias: "Existing"},
+		Platforms: streamers.Platforms{YouTube: &streamers.YouTubePlatform{ChannelID: "UC123", WebSubSubscribed: true}},
+		Sites:     []string{"sharpen-live"},
+	}); err != nil {
+		t.Fatalf("append existing: %v", err)
+	}
+	subStore := submissions.NewStore(filepath.Join(dir, "subs.json"))
+	pending, err := subStore.Append(submissions.Submission{
+		ID:    "sub_1",
+		Alias: "Existing Again",
+		Platforms: map[string]submissions.PlatformInfo{
+			"youtube": {URL: "https://youtube.com/channel/UC123", ChannelID: "UC123"},
+		},
+		SubmittedAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("append submission: %v", err)
+	}
+	svc := NewSubmissionsService(SubmissionsOptions{
+		SubmissionsStore: subStore,
+		StreamersStore:   shared.ForSite("synth-wave"),
+	})
+	if _, err := svc.Process(context.Background(), ActionRequest{Action: ActionApprove, ID: pending.ID}); err != nil {
+		t.Fatalf("process approval: %v", err)
+	}
+	records, err := shared.List()
+	if err != nil {
+		t.Fatalf("list streamers: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected channel to stay a single record, got %d", len(records))
+	}
+	if !records[0].VisibleOn("synth-wave") || !records[0].VisibleOn("sharpen-live") {
+		t.Fatalf("expected record on both sites, got %v", records[0].Sites)
+	}
+}
+
+func TestSubmissionsServiceApproveAddsPlatformToSharedRecord(t *testing.T) {
+	dir := t.TempDir()
+	shared := streamers.NewStore(filepath.Join(dir, "streamers.json"))
+	if _, err := shared.Append(streamers.Record{
+		Streamer:  streamers.Streamer{ID: "existing", Alias: "Existing"},
+		Platforms: streamers.Platforms{YouTube: &streamers.YouTubePlatform{ChannelID: "UC123", WebSubSubscribed: true}},
+		Sites:     []string{"sharpen-live"},
+	}); err != nil {
+		t.Fatalf("append existing: %v", err)
+	}
+	subStore := submissions.NewStore(filepath.Join(dir, "subs.json"))
+	pending, err := subStore.Append(submissions.Submission{
+		ID:    "sub_1",
+		Alias: "Existing Again",
+		Platforms: map[string]submissions.PlatformInfo{
+			"youtube": {URL: "https://youtube.com/channel/UC123", ChannelID: "UC123"},
+			"twitch":  {URL: "https://twitch.tv/existing"},
+		},
+		SubmittedAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("append submission: %v", err)
+	}
+	var approved streamers.Record
+	svc := NewSubmissionsService(SubmissionsOptions{
+		SubmissionsStore: subStore,
+		StreamersStore:   shared.ForSite("synth-wave"),
+		OnApproved:       func(record streamers.Record) { approved = record },
+	})
+	result, err := svc.Process(context.Background(), ActionRequest{Action: ActionApprove, ID: pending.ID})
+	if err != nil {
+		t.Fatalf("process approval: %v", err)
+	}
+	if result.Record == nil || result.Record.Streamer.ID != "existing" {
+		t.Fatalf("expected the result to carry the shared record, got %+v", result.Record)
+	}
+	records, err := shared.List()
+	if err != nil {
+		t.Fatalf("list streamers: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected the channels to stay a single record, got %+v", records)
+	}
+	record := records[0]
+	if !record.VisibleOn("synth-wave") || !record.VisibleOn("sharpen-live") {
+		t.Fatalf("expected record on both sites, got %v", record.Sites)
+	}
+	if record.Platforms.Twitch == nil || record.Platforms.Twitch.Username != "existing" {
+		t.Fatalf("expected the Twitch channel to be added, got %+v", record.Platforms.Twitch)
+	}
+	if !record.Platforms.YouTube.WebSubSubscribed {
+		t.Fatalf("expected the existing YouTube subscription to be kept, got %+v", record.Platforms.YouTube)
+	}
+	if approved.Streamer.ID != "existing" || approved.Platforms.Twitch == nil {
+		t.Fatalf("expected OnApproved to get the shared record, got %+v", approved)
+	}
+}
+
+func TestSubmissionsServiceApproveRejectsConflictingSharedRecords(t *testing.T) {
+	dir := t.TempDir()
+	shared := streamers.NewStore(filepath.Join(dir, "streamers.json"))
+	for _, record := range []streamers.Record{
+		{
+			Streamer:  streamers.Streamer{ID: "tuber", Alias: "Tuber"},
+			Platforms: streamers.Platforms{YouTube: &streamers.YouTubePlatform{ChannelID: "UC123"}},
+			Sites:     []string{"sharpen-live"},
+		},
+		{
+			Streamer:  streamers.Streamer{ID: "twitcher", Alias: "Twitcher"},
+			Platforms: streamers.Platforms{Twitch: &streamers.TwitchPlatform{Username: "twitcher"}},
+			Sites:     []string{"sharpen-live"},
+		},
+	} {
+		if _, err := shared.Append(record); err != nil {
+			t.Fatalf("append existing: %v", err)
+		}
+	}
+	subStore := submissions.NewStore(filepath.Join(dir, "subs.json"))
+	pending, err := subStore.Append(submissions.Submission{
+		ID:    "sub_1",
+		Alias: "Both",
+		Platforms: map[string]submissions.PlatformInfo{
+			"youtube": {URL: "https://youtube.com/channel/UC123", ChannelID: "UC123"},
+			"twitch":  {URL: "https://twitch.tv/twitcher"},
+		},
+		SubmittedAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("append submission: %v", err)
+	}
+	svc := NewSubmissionsService(SubmissionsOptions{
+		SubmissionsStore: subStore,
+		StreamersStore:   shared.ForSite("synth-wave"),
+	})
+	if _, err := svc.Process(context.Background(), ActionRequest{Action: ActionApprove, ID: pending.ID}); !errors.Is(err, ErrPlatformConflict) {
+		t.Fatalf("expected ErrPlatformConflict, got %v", err)
+	}
+	if list, _ := subStore.List(); len(list) != 1 {
+		t.Fatalf("expected submission requeued, got %d", len(list))
+	}
+	records, _ := shared.List()
+	for _, record := range records {
+		if record.VisibleOn("synth-wave") {
+			t.Fatalf("expected no record to be copied, got %+v", record)
+		}
+	}
+}
+
 func TestSubmissionsServiceDuplicateAlias(t *testing.T) {
 	dir := t.TempDir()
 	subStore := submissions.NewStore(filepath.Join(dir, "subs.json"))
@@ -16,6 +16,10 @@ const (
 	defaultAssetsDir    = "ui/sites/default-site"
 	alertserverName     = "Alertserver Admin"
 	AlertserverKey      = "alertserver"
+	defaultRosterPath   = "data/shared/streamers.json"
+	// BaseRosterKey identifies the base site in shared roster records, since
+	// its config key is empty.
+	BaseRosterKey = "base"
 )
 
 // YouTubeConfig captures the WebSub-specific defaults persisted in config files.
@@ -39,30 +43,76 @@ type TwitchConfig struct {
 	CallbackURL   string `json:"callback_url"`
 }
 
-// ServerConfig configures the HTTP listener used by alert-server.
+// ServerConfig configures the HTTP listener used by alert-server. Hosts lists
+// the Host header values routed to the site in virtual-host mode.
+// WatchMaxClients caps concurrent /streamers/watch connections per site and
+// AllowedOrigins lists the cross-origin pages allowed to open /streamers/ws.
 type ServerConfig struct {
-	Addr string `json:"addr"`
-	Port string `json:"port"`
+	Addr            string   `json:"addr"`
+	Port            string   `json:"port"`
+	Hosts           []string `json:"hosts,omitempty"`
+	WatchMaxClients int      `json:"watch_max_clients,omitempty"`
+	AllowedOrigins  []string `json:"allowed_origins,omitempty"`
+}
+
+// VirtualHostConfig enables serving every site from a single listener that
+// dispatches on the Host header. Addr/Port default to the base server block.
+type VirtualHostConfig struct {
+	Enabled bool   `json:"enabled"`
+	Addr    string `json:"addr,omitempty"`
+	Port    string `json:"port,omitempty"`
+}
+
+// RosterConfig switches every site to one shared streamers.json. Records list
+// the sites they appear on; Path defaults to data/shared/streamers.json.
+type RosterConfig struct {
+	Shared bool   `json:"shared"`
+	Path   string `json:"path,omitempty"`
 }
 
 // AppConfig configures server-rendered assets/templates and data locations.
+// Parent names the site whose templates and assets fill in any files this
+// site does not provide; the default-site theme is always the last resort.
+// Locales lists the UI languages the site is served in; DefaultLocale (or
+// the first entry) is served without a path prefix.
 type AppConfig struct {
-	Templates string `json:"templates"`
-	Assets    string `json:"assets"`
-	Data      string `json:"data"`
-	Name      string `json:"name"`
+	Templates     string   `json:"templates"`
+	Assets        string   `json:"assets"`
+	Data          string   `json:"data"`
+	Name          string   `json:"name"`
+	Parent        string   `json:"parent,omitempty"`
+	Locales       []string `json:"locales,omitempty"`
+	DefaultLocale string   `json:"default_locale,omitempty"`
+}
+
+// AnnouncementConfig posts "now live" messages to a chat channel. Type is
+// discord, slack or matrix; URL is the Discord or Slack webhook URL, or the
+// Matrix homeserver used with Room and Token. Template is a text/template
+// over the announcement, and CooldownSeconds stops a streamer being
+// announced again in the channel for that long. Rule, in the syntax of the
+// rules package, limits which streams are announced.
+type AnnouncementConfig struct {
+	Name            string `json:"name,omitempty"`
+	Type            string `json:"type"`
+	URL             string `json:"url"`
+	Room            string `json:"room,omitempty"`
+	Token           string `json:"token,omitempty"`
+	Template        string `json:"template,omitempty"`
+	CooldownSeconds int    `json:"cooldown_seconds,omitempty"`
+	Rule            string `json:"rule,omitempty"`
 }
 
 // SiteConfig captures per-site overrides for server/app settings.
 type SiteConfig struct {
 	Key            string
-	Name           string       `json:"name"`
-	Description    string       `json:"description"`
-	YouTubeEnabled *bool        `json:"youtube_enabled,omitempty"`
-	TwitchEnabled  *bool        `json:"twitch_enabled,omitempty"`
-	TwitchCallback string       `json:"twitch_callback,omitempty"`
-	Server         ServerConfig `json:"server"`
-	App            AppConfig    `json:"app"`
+	Name           string               `json:"name"`
+	Description    string               `json:"description"`
+	YouTubeEnabled *bool                `json:"youtube_enabled,omitempty"`
+	TwitchEnabled  *bool                `json:"twitch_enabled,omitempty"`
+	TwitchCallback string               `json:"twitch_callback,omitempty"`
+	Server         ServerConfig         `json:"server"`
+	App            AppConfig            `json:"app"`
+	Announcements  []AnnouncementConfig `json:"announcements,omitempty"`
 }
 
 // AdminConfig stores credentials for admin-authenticated APIs.
@@ -72,14 +122,27 @@ type AdminConfig struct {
 	TokenTTLSeconds int    `json:"token_ttl_seconds"`
 }
 
+// MailConfig configures the SMTP relay used for email alerts. Addr is the
+// relay's host:port and From the sender address; email is disabled while
+// Addr is empty. Username and Password enable SMTP AUTH PLAIN.
+type MailConfig struct {
+	Addr     string `json:"addr"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	From     string `json:"from"`
+}
+
 // Config represents the combined runtime settings parsed from config.json.
 type Config struct {
-	Server  ServerConfig
-	App     AppConfig
-	YouTube YouTubeConfig
-	Twitch  TwitchConfig
-	Admin   AdminConfig
-	Sites   map[string]SiteConfig
+	Server       ServerConfig
+	VirtualHosts VirtualHostConfig
+	Roster       RosterConfig
+	App          AppConfig
+	YouTube      YouTubeConfig
+	Twitch       TwitchConfig
+	Admin        AdminConfig
+	Mail         MailConfig
+	Sites        map[string]SiteConfig
 }
 
 type platformsFileConfig struct {
@@ -89,6 +152,8 @@ type platformsFileConfig struct {
 
 type fileConfig struct {
 	ServerBlock    *ServerConfig             `json:"server"`
+	VirtualHosts   *VirtualHostConfig        `json:"virtual_hosts,omitempty"`
+	Roster         *RosterConfig             `json:"roster,omitempty"`
 	Addr           string                    `json:"addr"`
 	Port           string                    `json:"port"`
 	AppBlock       *AppConfig                `json:"app"`
@@ -99,6 +164,7 @@ type fileConfig struct {
 	YouTubeConfig
 	AdminBlock *AdminConfig `json:"admin"`
 	AdminConfig
+	MailBlock *MailConfig `json:"mail,omitempty"`
 }
 
 type siteTwitchConfig struct {
@@ -107,13 +173,14 @@ type siteTwitchConfig struct {
 }
 
 type siteFileConfig struct {
-	Name           string            `json:"name"`
-	Description    string            `json:"description"`
-	YouTubeEnabled *bool             `json:"youtube_enabled,omitempty"`
-	YouTube        *YouTubeConfig    `json:"youtube,omitempty"`
-	Twitch         *siteTwitchConfig `json:"twitch,omitempty"`
-	Server         *ServerConfig     `json:"server"`
-	App            *AppConfig        `json:"app"`
+	Name           string               `json:"name"`
+	Description    string               `json:"description"`
+	YouTubeEnabled *bool                `json:"youtube_enabled,omitempty"`
+	YouTube        *YouTubeConfig       `json:"youtube,omitempty"`
+	Twitch         *siteTwitchConfig    `json:"twitch,omitempty"`
+	Server         *ServerConfig        `json:"server"`
+	App            *AppConfig           `json:"app"`
+	Announcements  []AnnouncementConfig `json:"announcements,omitempty"`
 }
 
 // Load reads the JSON config at the given path and returns the parsed structure.
@@ -198,9 +265,24 @@ func Load(path string) (Config, error) {
 		app.Name = alertserverName
 	}
 
+	var vhost VirtualHostConfig
+	if raw.VirtualHosts != nil {
+		vhost = *raw.VirtualHosts
+	}
+
+	var roster RosterConfig
+	if raw.Roster != nil {
+		roster = *raw.Roster
+	}
+	if roster.Shared && strings.TrimSpace(roster.Path) == "" {
+		roster.Path = defaultRosterPath
+	}
+
 	sites := map[string]SiteConfig{}
 	for key, site := range raw.Sites {
 		siteServer := server
+		// Host names identify a single site, so they are never inherited.
+		siteServer.Hosts = nil
 		if site.Server != nil {
 			if site.Server.Addr != "" {
 				siteServer.Addr = site.Server.Addr
@@ -208,10 +290,20 @@ func Load(path string) (Config, error) {
 			if site.Server.Port != "" {
 				siteServer.Port = site.Server.Port
 			}
+			if site.Server.WatchMaxClients > 0 {
+				siteServer.WatchMaxClients = site.Server.WatchMaxClients
+			}
+			if len(site.Server.AllowedOrigins) > 0 {
+				siteServer.AllowedOrigins = append([]string(nil), site.Server.AllowedOrigins...)
+			}
+			siteServer.Hosts = append([]string(nil), site.Server.Hosts...)
 		}
 
 		siteApp := app
+		// A theme parent belongs to the site that names it.
+		siteApp.Parent = ""
 		if site.App != nil {
+			siteApp.Parent = strings.TrimSpace(site.App.Parent)
 			if site.App.Templates != "" {
 				siteApp.Templates = site.App.Templates
 			}
@@ -224,6 +316,12 @@ func Load(path string) (Config, error) {
 			if site.App.Name != "" {
 				siteApp.Name = site.App.Name
 			}
+			if len(site.App.Locales) > 0 {
+				siteApp.Locales = append([]string(nil), site.App.Locales...)
+			}
+			if site.App.DefaultLocale != "" {
+				siteApp.DefaultLocale = site.App.DefaultLocale
+			}
 		}
 
 		siteName := site.Name
@@ -250,16 +348,22 @@ func Load(path string) (Config, error) {
 			TwitchCallback: twitchCallback,
 			Server:         siteServer,
 			App:            siteApp,
+			Announcements:  append([]AnnouncementConfig(nil), site.Announcements...),
 		}
 	}
 
 	cfg := Config{
-		Server:  server,
-		App:     app,
-		YouTube: yt,
-		Twitch:  twitch,
-		Admin:   admin,
-		Sites:   sites,
+		Server:       server,
+		VirtualHosts: vhost,
+		Roster:       roster,
+		App:          app,
+		YouTube:      yt,
+		Twitch:       twitch,
+		Admin:        admin,
+		Sites:        sites,
+	}
+	if raw.MailBlock != nil {
+		cfg.Mail = *raw.MailBlock
 	}
 
 	return cfg, nil
@@ -280,25 +384,49 @@ func Save(cfg Config, path string) error {
 	raw := fileConfig{
 		ServerBlock: &cfg.Server,
 		AppBlock: &AppConfig{
-			Templates: cfg.App.Templates,
-			Assets:    cfg.App.Assets,
-			Data:      cfg.App.Data,
-			Name:      cfg.App.Name,
+			Templates:     cfg.App.Templates,
+			Assets:        cfg.App.Assets,
+			Data:          cfg.App.Data,
+			Name:          cfg.App.Name,
+			Parent:        cfg.App.Parent,
+			Locales:       cfg.App.Locales,
+			DefaultLocale: cfg.App.DefaultLocale,
 		},
 		YouTubeBlock: &cfg.YouTube,
+		TwitchBlock:  &cfg.Twitch,
 		AdminBlock:   &cfg.Admin,
 		Sites:        make(map[string]siteFileConfig),
 	}
+	if cfg.VirtualHosts != (VirtualHostConfig{}) {
+		vhost := cfg.VirtualHosts
+		raw.VirtualHosts = &vhost
+	}
+	if cfg.Roster != (RosterConfig{}) {
+		roster := cfg.Roster
+		raw.Roster = &roster
+	}
+	if cfg.Mail != (MailConfig{}) {
+		mail := cfg.Mail
+		raw.MailBlock = &mail
+	}
 
 	// Convert sites
 	for key, site := range cfg.Sites {
-		raw.Sites[key] = siteFileConfig{
+		entry := siteFileConfig{
 			Name:           site.Name,
 			Description:    site.Description,
 			YouTubeEnabled: site.YouTubeEnabled,
 			Server:         &site.Server,
 			App:            &site.App,
+			Announcements:  site.Announcements,
+		}
+		if site.TwitchEnabled != nil || site.TwitchCallback != "" {
+			entry.Twitch = &siteTwitchConfig{
+				Enabled:     site.TwitchEnabled,
+				CallbackURL: site.TwitchCallback,
+			}
 		}
+		raw.Sites[key] = entry
 	}
 
 	// Marshal to JSON with indentation
@@ -367,6 +495,58 @@ func AllSites(cfg Config) []SiteConfig {
 	return sites
 }
 
+// ThemeChain returns the app configs searched for a site's templates and
+// assets: the site itself, each parent in turn, then the default-site theme.
+// Parents are site keys, with "base" naming the base site; unknown keys and
+// cycles end the chain early.
+func ThemeChain(site SiteConfig, cfg Config) []AppConfig {
+	chain := []AppConfig{site.App}
+	seen := map[string]bool{RosterSiteKey(site.Key): true}
+	parent := strings.TrimSpace(site.App.Parent)
+	for parent != "" && !seen[parent] {
+		seen[parent] = true
+		var next AppConfig
+		switch {
+		case parent == BaseRosterKey:
+			next = cfg.App
+		case strings.EqualFold(parent, AlertserverKey) || strings.EqualFold(parent, "default-site"):
+			parent = ""
+			continue
+		default:
+			resolved, ok := cfg.Sites[parent]
+			if !ok {
+				parent = ""
+				continue
+			}
+			next = resolved.App
+		}
+		chain = append(chain, next)
+		parent = strings.TrimSpace(next.Parent)
+	}
+	return append(chain, AlertserverAppConfig())
+}
+
+// SharedRosterPath returns the shared streamers.json path, or "" when each
+// site keeps its own roster.
+func SharedRosterPath(cfg Config) string {
+	if !cfg.Roster.Shared {
+		return ""
+	}
+	if path := strings.TrimSpace(cfg.Roster.Path); path != "" {
+		return path
+	}
+	return defaultRosterPath
+}
+
+// RosterSiteKey maps a site config key to the key stored in shared roster
+// records.
+func RosterSiteKey(siteKey string) string {
+	if siteKey == "" {
+		return BaseRosterKey
+	}
+	return siteKey
+}
+
 // DefaultConfig returns a configuration populated with default-site values. It
 // is primarily used when loading config.json fails and the server needs a
 // fallback site.
@@ -110,3 +110,184 @@ func TestLoadErrorsForMissingFile(t *testing.T) {
 		t.Fatalf("expected error for missing file")
 	}
 }
+
+func TestLoadSiteHostsAreNotInherited(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	data := `{
+		"server": {"addr":"0.0.0.0","port":":8080","hosts":["sharpen.live"]},
+		"virtual_hosts": {"enabled": true, "port": ":9000"},
+		"sites": {
+			"synth-wave": {"server": {"port":":8081","hosts":["synth.wave","www.synth.wave"]}},
+			"other": {}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !cfg.VirtualHosts.Enabled || cfg.VirtualHosts.Port != ":9000" {
+		t.Fatalf("virtual_hosts block not applied: %+v", cfg.VirtualHosts)
+	}
+	if len(cfg.Server.Hosts) != 1 || cfg.Server.Hosts[0] != "sharpen.live" {
+		t.Fatalf("base hosts not applied: %+v", cfg.Server.Hosts)
+	}
+	if hosts := cfg.Sites["synth-wave"].Server.Hosts; len(hosts) != 2 || hosts[0] != "synth.wave" {
+		t.Fatalf("site hosts not applied: %+v", hosts)
+	}
+	if hosts := cfg.Sites["other"].Server.Hosts; len(hosts) != 0 {
+		t.Fatalf("expected hosts not to be inherited, got %+v", hosts)
+	}
+}
+
+func TestLoadSharedRosterDefaultsPath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"roster": {"shared": true}}`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := SharedRosterPath(cfg); got != defaultRosterPath {
+		t.Fatalf("expected default roster path, got %q", got)
+	}
+	if got := SharedRosterPath(DefaultConfig()); got != "" {
+		t.Fatalf("expected no shared roster by default, got %q", got)
+	}
+	if got := RosterSiteKey(""); got != BaseRosterKey {
+		t.Fatalf("expected base roster key, got %q", got)
+	}
+}
+
+func TestSaveRoundTripsSitesAndTwitch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	data := `{
+		"platforms": {"twitch": {"client_id":"client","callback_url":"https://example.com/twitch"}},
+		"sites": {
+			"synth-wave": {"name":"synth.wave","twitch":{"callback_url":"https://synth.wave/twitch"},"server":{"port":":8081"}}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	cfg.Sites["neon"] = SiteConfig{
+		Key:    "neon",
+		Name:   "Neon",
+		Server: ServerConfig{Addr: "127.0.0.1", Port: ":8090"},
+		App:    AppConfig{Templates: "ui/sites/neon/templates", Assets: "ui/sites/neon", Data: "data/neon", Name: "Neon"},
+	}
+	if err := Save(cfg, path); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	saved, err := Load(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if saved.Twitch.ClientID != "client" || saved.Twitch.CallbackURL != "https://example.com/twitch" {
+		t.Fatalf("twitch config lost on save: %+v", saved.Twitch)
+	}
+	if got := saved.Sites["synth-wave"].TwitchCallback; got != "https://synth.wave/twitch" {
+		t.Fatalf("site twitch callback lost on save, got %q", got)
+	}
+	if got := saved.Sites["neon"]; got.Name != "Neon" || got.Server.Port != ":8090" || got.App.Data != "data/neon" {
+		t.Fatalf("new site not saved: %+v", got)
+	}
+}
+
+func TestThemeChainFollowsParents(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.App = AppConfig{Templates: "ui/sites/sharpen-live/templates", Assets: "ui/sites/sharpen-live"}
+	cfg.Sites = map[string]SiteConfig{
+		"synth-wave": {Key: "synth-wave", App: AppConfig{Templates: "ui/sites/synth-wave/templates", Parent: "base"}},
+		"neon":       {Key: "neon", App: AppConfig{Templates: "ui/sites/neon/templates", Parent: "synth-wave"}},
+		"loop-a":     {Key: "loop-a", App: AppConfig{Templates: "a", Parent: "loop-b"}},
+		"loop-b":     {Key: "loop-b", App: AppConfig{Templates: "b", Parent: "loop-a"}},
+		"orphan":     {Key: "orphan", App: AppConfig{Templates: "orphan", Parent: "missing"}},
+	}
+
+	tests := []struct {
+		site string
+		want []string
+	}{
+		{site: "neon", want: []string{"ui/sites/neon/templates", "ui/sites/synth-wave/templates", "ui/sites/sharpen-live/templates", defaultTemplatesDir}},
+		{site: "loop-a", want: []string{"a", "b", defaultTemplatesDir}},
+		{site: "orphan", want: []string{"orphan", defaultTemplatesDir}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.site, func(t *testing.T) {
+			chain := ThemeChain(cfg.Sites[tt.site], cfg)
+			if len(chain) != len(tt.want) {
+				t.Fatalf("expected %d themes, got %+v", len(tt.want), chain)
+			}
+			for i, dir := range tt.want {
+				if chain[i].Templates != dir {
+					t.Fatalf("theme %d: expected %s, got %s", i, dir, chain[i].Templates)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadDoesNotInheritThemeParent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	data := `{
+		"app": {"parent": "synth-wave"},
+		"sites": {
+			"synth-wave": {},
+			"neon": {"app": {"parent": "synth-wave"}}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := cfg.Sites["synth-wave"].App.Parent; got != "" {
+		t.Fatalf("expected no inherited parent, got %q", got)
+	}
+	if got := cfg.Sites["neon"].App.Parent; got != "synth-wave" {
+		t.Fatalf("expected site parent, got %q", got)
+	}
+}
+
+func TestLoadSiteLocalesOverrideBase(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	data := `{
+		"app": {"locales": ["en", "fr"]},
+		"sites": {
+			"plain": {},
+			"spanish": {"app": {"locales": ["es", "en"], "default_locale": "es"}}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := cfg.Sites["plain"].App.Locales; len(got) != 2 || got[1] != "fr" {
+		t.Fatalf("expected base locales to be inherited, got %v", got)
+	}
+	spanish := cfg.Sites["spanish"].App
+	if len(spanish.Locales) != 2 || spanish.Locales[0] != "es" || spanish.DefaultLocale != "es" {
+		t.Fatalf("expected site locales, got %+v", spanish)
+	}
+}
@@ -0,0 +1,188 @@
+// Package mail sends plain-text email through an SMTP relay. Callers depend
+// on the Sender interface so tests can swap in a recorder or point SMTP at
+// the mailtest stand-in.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrInvalidAddress is returned for recipients that are not a bare address.
+var ErrInvalidAddress = errors.New("invalid email address")
+
+// Message is one plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	// Headers adds fields such as List-Unsubscribe.
+	Headers map[string]string
+}
+
+// Sender delivers messages.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// ParseAddress normalises a bare email address, rejecting display names
+// and anything net/mail cannot parse.
+func ParseAddress(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	parsed, err := mail.ParseAddress(value)
+	if err != nil || parsed.Name != "" || parsed.Address != value {
+		return "", ErrInvalidAddress
+	}
+	return strings.ToLower(parsed.Address), nil
+}
+
+// SMTPOptions configures an SMTP sender.
+type SMTPOptions struct {
+	// Addr is the relay's host:port.
+	Addr string
+	// Username and Password enable AUTH PLAIN, which net/smtp only sends
+	// over TLS or to localhost.
+	Username string
+	Password string
+	// From is the sender address; FromName is shown alongside it.
+	From     string
+	FromName string
+	// TLSConfig is used for STARTTLS; it defaults to verifying Addr's host.
+	TLSConfig *tls.Config
+}
+
+// SMTP sends messages through a relay, upgrading with STARTTLS when the
+// relay offers it.
+type SMTP struct {
+	opts SMTPOptions
+	host string
+	now  func() time.Time
+}
+
+// NewSMTP returns an SMTP sender for opts.
+func NewSMTP(opts SMTPOptions) (*SMTP, error) {
+	host, _, err := net.SplitHostPort(opts.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp address %q: %w", opts.Addr, err)
+	}
+	if _, err := ParseAddress(opts.From); err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", opts.From, err)
+	}
+	return &SMTP{opts: opts, host: host, now: time.Now}, nil
+}
+
+// Send delivers msg. The context bounds the whole SMTP conversation.
+func (s *SMTP) Send(ctx context.Context, msg Message) error {
+	to, err := ParseAddress(msg.To)
+	if err != nil {
+		return err
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.opts.Addr)
+	if err != nil {
+		return fmt.Errorf("dial smtp: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp greeting: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		config := s.opts.TLSConfig
+		if config == nil {
+			config = &tls.Config{ServerName: s.host}
+		}
+		if err := client.StartTLS(config); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+	if s.opts.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.opts.Username, s.opts.Password, s.host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+	if err := client.Mail(s.opts.From); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("smtp rcpt to: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write(s.render(to, msg)); err != nil {
+		return fmt.Errorf("smtp write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	return client.Quit()
+}
+
+// render builds the RFC 5322 message with a quoted-printable UTF-8 body.
+func (s *SMTP) render(to string, msg Message) []byte {
+	from := s.opts.From
+	if name := clean(s.opts.FromName); name != "" {
+		from = (&mail.Address{Name: name, Address: s.opts.From}).String()
+	}
+	domain := s.opts.From[strings.LastIndex(s.opts.From, "@")+1:]
+	headers := map[string]string{
+		"From":                      from,
+		"To":                        to,
+		"Subject":                   mime.QEncoding.Encode("utf-8", clean(msg.Subject)),
+		"Date":                      s.now().Format(time.RFC1123Z),
+		"Message-ID":                "<" + randomHex(12) + "@" + domain + ">",
+		"MIME-Version":              "1.0",
+		"Content-Type":              `text/plain; charset="utf-8"`,
+		"Content-Transfer-Encoding": "quoted-printable",
+	}
+	for key, value := range msg.Headers {
+		headers[key] = clean(value)
+	}
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	for _, key := range keys {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, headers[key])
+	}
+	buf.WriteString("\r\n")
+	qp := quotedprintable.NewWriter(&buf)
+	// The writer emits CRLF for each line break in text mode.
+	_, _ = qp.Write([]byte(strings.ReplaceAll(msg.Text, "\r\n", "\n")))
+	_ = qp.Close()
+	return buf.Bytes()
+}
+
+// clean strips line breaks so header values cannot inject fields.
+func clean(value string) string {
+	return strings.TrimSpace(strings.NewReplacer("\r", " ", "\n", " ").Replace(value))
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,79 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/mail/mailtest"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		err  bool
+	}{
+		{in: " Viewer@Example.com ", want: "viewer@example.com"},
+		{in: "Viewer <viewer@example.com>", err: true},
+		{in: "not-an-address", err: true},
+		{in: "a@example.com\r\nBcc: b@example.com", err: true},
+	}
+	for _, tc := range tests {
+		got, err := ParseAddress(tc.in)
+		if tc.err {
+			if !errors.Is(err, ErrInvalidAddress) {
+				t.Fatalf("%q: expected ErrInvalidAddress, got %q %v", tc.in, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Fatalf("%q: got %q %v", tc.in, got, err)
+		}
+	}
+}
+
+func TestSMTPSend(t *testing.T) {
+	server := mailtest.NewServer(t)
+	sender, err := NewSMTP(SMTPOptions{Addr: server.Addr, From: "alerts@sharpen.live", FromName: "Sharpen.Live"})
+	if err != nil {
+		t.Fatalf("new smtp: %v", err)
+	}
+	sender.now = func() time.Time { return time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC) }
+
+	err = sender.Send(context.Background(), Message{
+		To:      "viewer@example.com",
+		Subject: "Café is live\r\nBcc: evil@example.com",
+		Text:    "Line one\nhttps://sharpen.live/streamers/caf%C3%A9\n.leading dot",
+		Headers: map[string]string{"List-Unsubscribe": "<https://sharpen.live/email/unsubscribe?id=1>"},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	messages := server.Wait(1, time.Second)
+	if len(messages) != 1 {
+		t.Fatalf("expected one message, got %d", len(messages))
+	}
+	msg := messages[0]
+	if msg.From != "alerts@sharpen.live" || len(msg.To) != 1 || msg.To[0] != "viewer@example.com" {
+		t.Fatalf("unexpected envelope %+v", msg)
+	}
+	if msg.Header.Get("Bcc") != "" || msg.Header.Get("List-Unsubscribe") != "<https://sharpen.live/email/unsubscribe?id=1>" {
+		t.Fatalf("unexpected headers %v", msg.Header)
+	}
+	if subject, _ := msg.Header.Date(); subject.IsZero() {
+		t.Fatalf("expected a Date header")
+	}
+	if !strings.Contains(msg.Header.Get("From"), "Sharpen.Live") {
+		t.Fatalf("expected the sender name, got %q", msg.Header.Get("From"))
+	}
+	if msg.Body != "Line one\nhttps://sharpen.live/streamers/caf%C3%A9\n.leading dot" {
+		t.Fatalf("unexpected body %q", msg.Body)
+	}
+
+	if err := sender.Send(context.Background(), Message{To: "Someone <x@example.com>"}); !errors.Is(err, ErrInvalidAddress) {
+		t.Fatalf("expected recipients with display names to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+// Package mailtest runs a local SMTP stand-in that accepts every message
+// and keeps it for inspection.
+package mailtest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Message is one accepted email.
+type Message struct {
+	From   string
+	To     []string
+	Header mail.Header
+	// Body is the decoded text body.
+	Body string
+}
+
+// Server is an SMTP stand-in listening on localhost.
+type Server struct {
+	// Addr is the host:port to point an SMTP sender at.
+	Addr string
+
+	listener net.Listener
+	mu       sync.Mutex
+	messages []Message
+	received chan struct{}
+}
+
+// NewServer starts a stand-in that stops when the test ends.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("mailtest: listen: %v", err)
+	}
+	s := &Server{Addr: listener.Addr().String(), listener: listener, received: make(chan struct{}, 64)}
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+// Messages returns the messages accepted so far.
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+// Wait blocks until n messages have arrived or timeout passes, returning
+// what was received.
+func (s *Server) Wait(n int, timeout time.Duration) []Message {
+	deadline := time.After(timeout)
+	for {
+		if messages := s.Messages(); len(messages) >= n {
+			return messages
+		}
+		select {
+		case <-s.received:
+		case <-deadline:
+			return s.Messages()
+		}
+	}
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(format string, args ...any) {
+		fmt.Fprintf(conn, format+"\r\n", args...)
+	}
+	reply("220 mailtest ready")
+	var current Message
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(verb, "EHLO"), strings.HasPrefix(verb, "HELO"):
+			reply("250-mailtest")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(verb, "MAIL FROM:"):
+			current = Message{From: address(line[len("MAIL FROM:"):])}
+			reply("250 ok")
+		case strings.HasPrefix(verb, "RCPT TO:"):
+			current.To = append(current.To, address(line[len("RCPT TO:"):]))
+			reply("250 ok")
+		case verb == "DATA":
+			reply("354 end with .")
+			data, err := readData(r)
+			if err != nil {
+				return
+			}
+			if parsed, err := mail.ReadMessage(strings.NewReader(data)); err == nil {
+				current.Header = parsed.Header
+				body, _ := io.ReadAll(parsed.Body)
+				if strings.EqualFold(parsed.Header.Get("Content-Transfer-Encoding"), "quoted-printable") {
+					body, _ = io.ReadAll(quotedprintable.NewReader(strings.NewReader(string(body))))
+				}
+				// DATA always ends with a line break the sender did not write.
+				current.Body = strings.TrimSuffix(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n")
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, current)
+			s.mu.Unlock()
+			select {
+			case s.received <- struct{}{}:
+			default:
+			}
+			reply("250 queued")
+		case verb == "RSET", verb == "NOOP":
+			reply("250 ok")
+		case verb == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+// address extracts the path from a MAIL FROM or RCPT TO argument, ignoring
+// parameters such as BODY=8BITMIME.
+func address(arg string) string {
+	arg = strings.TrimSpace(arg)
+	if start := strings.Index(arg, "<"); start >= 0 {
+		if end := strings.Index(arg[start:], ">"); end >= 0 {
+			return arg[start+1 : start+end]
+		}
+	}
+	if fields := strings.Fields(arg); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+// readData reads a DATA section up to the lone "." line, undoing dot
+// stuffing.
+func readData(r *bufio.Reader) (string, error) {
+	var b strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		trimmed := strings.TrimRight(line, "\r\n")
+		if trimmed == "." {
+			return b.String(), nil
+		}
+		b.WriteString(strings.TrimPrefix(trimmed, "."))
+		b.WriteString("\r\n")
+	}
+}
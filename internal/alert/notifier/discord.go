@@ -0,0 +1,83 @@
+package notifier
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Embed colours for live and ended streams.
+const (
+	discordLiveColor  = 0xE91916
+	discordEndedColor = 0x747F8D
+)
+
+// discord posts webhook embeds. Messages are created with ?wait=true so the
+// response carries the message ID used to edit them.
+type discord struct{}
+
+type discordMessage struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	URL         string         `json:"url,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+	Footer      *discordFooter `json:"footer,omitempty"`
+}
+
+type discordFooter struct {
+	Text string `json:"text"`
+}
+
+func (discord) format(msg message) discordMessage {
+	embed := discordEmbed{
+		Title:       msg.Text,
+		URL:         msg.URL,
+		Description: msg.Description,
+		Color:       discordLiveColor,
+	}
+	if msg.Ended {
+		embed.Color = discordEndedColor
+		embed.URL = msg.PageURL
+	}
+	if !msg.StartedAt.IsZero() {
+		embed.Timestamp = msg.StartedAt.UTC().Format(time.RFC3339)
+	}
+	if footer := strings.Trim(strings.Join([]string{msg.Site, msg.PlatformName}, " · "), " ·"); footer != "" {
+		embed.Footer = &discordFooter{Text: footer}
+	}
+	return discordMessage{Embeds: []discordEmbed{embed}}
+}
+
+func (d discord) post(ctx context.Context, client *http.Client, ch Channel, msg message) (string, error) {
+	target, err := url.Parse(ch.URL)
+	if err != nil {
+		return "", err
+	}
+	query := target.Query()
+	query.Set("wait", "true")
+	target.RawQuery = query.Encode()
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := send(ctx, client, http.MethodPost, target.String(), nil, d.format(msg), &created); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+func (d discord) edit(ctx context.Context, client *http.Client, ch Channel, ref string, msg message) error {
+	target, err := url.Parse(ch.URL)
+	if err != nil {
+		return err
+	}
+	// Thread webhooks keep their thread_id query on edits.
+	target.Path = strings.TrimSuffix(target.Path, "/") + "/messages/" + url.PathEscape(ref)
+	return send(ctx, client, http.MethodPatch, target.String(), nil, d.format(msg), nil)
+}
@@ -0,0 +1,64 @@
+package notifier
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// matrix sends m.room.message events through the client-server API and
+// edits them with m.replace relations.
+type matrix struct{}
+
+type matrixContent struct {
+	MsgType    string          `json:"msgtype"`
+	Body       string          `json:"body"`
+	NewContent *matrixContent  `json:"m.new_content,omitempty"`
+	RelatesTo  *matrixRelation `json:"m.relates_to,omitempty"`
+}
+
+type matrixRelation struct {
+	RelType string `json:"rel_type"`
+	EventID string `json:"event_id"`
+}
+
+func (matrix) content(msg message) matrixContent {
+	body := msg.Text
+	if msg.URL != "" && !msg.Ended && !strings.Contains(body, msg.URL) {
+		body += "\n" + msg.URL
+	}
+	return matrixContent{MsgType: "m.text", Body: body}
+}
+
+func (m matrix) post(ctx context.Context, client *http.Client, ch Channel, msg message) (string, error) {
+	return m.send(ctx, client, ch, m.content(msg))
+}
+
+func (m matrix) edit(ctx context.Context, client *http.Client, ch Channel, ref string, msg message) error {
+	replacement := m.content(msg)
+	_, err := m.send(ctx, client, ch, matrixContent{
+		MsgType:    replacement.MsgType,
+		Body:       "* " + replacement.Body,
+		NewContent: &replacement,
+		RelatesTo:  &matrixRelation{RelType: "m.replace", EventID: ref},
+	})
+	return err
+}
+
+func (matrix) send(ctx context.Context, client *http.Client, ch Channel, content matrixContent) (string, error) {
+	txn := make([]byte, 8)
+	_, _ = rand.Read(txn)
+	target := strings.TrimSuffix(ch.URL, "/") + "/_matrix/client/v3/rooms/" + url.PathEscape(ch.Room) +
+		"/send/m.room.message/sharpen-" + hex.EncodeToString(txn)
+	header := http.Header{"Authorization": {"Bearer " + ch.Token}}
+	var sent struct {
+		EventID string `json:"event_id"`
+	}
+	if err := send(ctx, client, http.MethodPut, target, header, content, &sent); err != nil {
+		return "", err
+	}
+	return sent.EventID, nil
+}
@@ -0,0 +1,421 @@
+// Package notifier posts "now live" announcements to chat channels. Discord
+// and Matrix messages are edited in place when the stream ends; Slack
+// incoming webhooks cannot edit, so their messages are left as posted.
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/rules"
+)
+
+// Channel kinds.
+const (
+	KindDiscord = "discord"
+	KindSlack   = "slack"
+	KindMatrix  = "matrix"
+)
+
+const (
+	// DefaultTemplate renders announcements when a channel sets none.
+	DefaultTemplate = `{{if .Ended}}{{.Alias}}'s {{.PlatformName}} stream has ended.{{else}}{{.Alias}} is live on {{.PlatformName}}: {{.URL}}{{end}}`
+	// DefaultCooldown is how long a streamer's announcement suppresses the
+	// next one in the same channel.
+	DefaultCooldown = 10 * time.Minute
+)
+
+// ErrUnknownKind is returned for channels that are not discord, slack or matrix.
+var ErrUnknownKind = errors.New("unknown announcement channel type")
+
+// Channel is one chat destination for a site's announcements.
+type Channel struct {
+	// Name identifies the channel in logs and saved state; it defaults to
+	// the kind and URL.
+	Name string
+	Kind string
+	// URL is the Discord or Slack webhook URL, or the Matrix homeserver.
+	URL string
+	// Room and Token address a Matrix room as a bot user.
+	Room  string
+	Token string
+	// Template is a text/template executed with an Announcement.
+	Template string
+	// Cooldown suppresses repeat announcements for a streamer, so a stream
+	// that flaps offline and back reuses the first message.
+	Cooldown time.Duration
+	// Rule, in the rules package syntax, limits the streams announced in
+	// the channel; empty announces every stream.
+	Rule string
+}
+
+// Announcement describes a streamer going live or ending a stream.
+type Announcement struct {
+	StreamerID   string
+	Alias        string
+	Description  string
+	Platform     string
+	PlatformName string
+	// URL is where the stream is watched; PageURL is the streamer's page
+	// on the site.
+	URL       string
+	PageURL   string
+	Site      string
+	StartedAt time.Time
+	Ended     bool
+	// Languages, StreamType, Title and Tags describe the stream for
+	// channel rules.
+	Languages  []string
+	StreamType string
+	Title      string
+	Tags       []string
+}
+
+// Event returns the announcement as seen by channel rules, at now when the
+// start time is unknown.
+func (a Announcement) Event(now time.Time) rules.Event {
+	at := a.StartedAt
+	if at.IsZero() {
+		at = now
+	}
+	return rules.Event{
+		StreamerID: a.StreamerID,
+		Alias:      a.Alias,
+		Languages:  a.Languages,
+		Platform:   a.Platform,
+		StreamType: a.StreamType,
+		Title:      a.Title,
+		Tags:       a.Tags,
+		At:         at,
+	}
+}
+
+// message is an announcement rendered for one channel.
+type message struct {
+	Announcement
+	Text string
+}
+
+// sender posts to one kind of channel. edit returns errEditUnsupported when
+// the channel cannot change a posted message.
+type sender interface {
+	post(ctx context.Context, client *http.Client, ch Channel, msg message) (string, error)
+	edit(ctx context.Context, client *http.Client, ch Channel, ref string, msg message) error
+}
+
+var errEditUnsupported = errors.New("channel cannot edit messages")
+
+type channel struct {
+	Channel
+	tmpl   *template.Template
+	rule   *rules.Rule
+	sender sender
+}
+
+// Options configures a Notifier.
+type Options struct {
+	// StatePath persists cooldowns and posted message IDs so a restart can
+	// still mark streams ended; empty keeps them in memory.
+	StatePath string
+	// Client sends messages; it defaults to a client with a 10s timeout.
+	Client *http.Client
+}
+
+// Notifier announces streams to a site's channels.
+type Notifier struct {
+	channels []channel
+	client   *http.Client
+	path     string
+	now      func() time.Time
+
+	// busy serialises announcements so concurrent events for one streamer
+	// cannot both post.
+	busy  sync.Mutex
+	mu    sync.Mutex
+	state map[string]posted
+	dirty bool
+}
+
+// posted records the last announcement for a streamer in a channel.
+type posted struct {
+	Ref      string    `json:"ref,omitempty"`
+	Platform string    `json:"platform"`
+	PostedAt time.Time `json:"postedAt"`
+	Ended    bool      `json:"ended,omitempty"`
+}
+
+// New validates channels and returns a notifier for them.
+func New(channels []Channel, opts Options) (*Notifier, error) {
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	n := &Notifier{client: client, path: opts.StatePath, now: time.Now}
+	for _, ch := range channels {
+		ch.Kind = strings.ToLower(strings.TrimSpace(ch.Kind))
+		var s sender
+		switch ch.Kind {
+		case KindDiscord:
+			s = discord{}
+		case KindSlack:
+			s = slack{}
+		case KindMatrix:
+			if ch.Room == "" || ch.Token == "" {
+				return nil, fmt.Errorf("matrix channel %s needs a room and token", ch.URL)
+			}
+			s = matrix{}
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrUnknownKind, ch.Kind)
+		}
+		if strings.TrimSpace(ch.URL) == "" {
+			return nil, fmt.Errorf("%s channel needs a url", ch.Kind)
+		}
+		if ch.Name == "" {
+			ch.Name = ch.Kind + " " + ch.URL
+		}
+		if ch.Template == "" {
+			ch.Template = DefaultTemplate
+		}
+		tmpl, err := template.New(ch.Name).Parse(ch.Template)
+		if err != nil {
+			return nil, fmt.Errorf("parse template for %s: %w", ch.Name, err)
+		}
+		rule, err := rules.Parse(ch.Rule)
+		if err != nil {
+			return nil, fmt.Errorf("parse rule for %s: %w", ch.Name, err)
+		}
+		if ch.Cooldown <= 0 {
+			ch.Cooldown = DefaultCooldown
+		}
+		n.channels = append(n.channels, channel{Channel: ch, tmpl: tmpl, rule: rule, sender: s})
+	}
+	return n, nil
+}
+
+// Live announces that a streamer went live on a platform to the channels
+// whose rule matches it. Within a channel's cooldown a repeat is skipped, or
+// the ended message is restored if the channel can edit it.
+func (n *Notifier) Live(ctx context.Context, a Announcement) error {
+	if n == nil {
+		return nil
+	}
+	n.busy.Lock()
+	defer n.busy.Unlock()
+	a.Ended = false
+	event := a.Event(n.now())
+	var errs []error
+	for _, ch := range n.channels {
+		if !ch.rule.Match(event) {
+			continue
+		}
+		if err := n.live(ctx, ch, a); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ch.Name, err))
+		}
+	}
+	errs = append(errs, n.save())
+	return errors.Join(errs...)
+}
+
+// Route reports whether a channel's rule lets an announcement through.
+type Route struct {
+	Channel string
+	Rule    string
+	Matched bool
+}
+
+// Routes evaluates each channel's rule against a live announcement without
+// posting anything.
+func (n *Notifier) Routes(a Announcement) []Route {
+	if n == nil {
+		return nil
+	}
+	event := a.Event(n.now())
+	routes := make([]Route, 0, len(n.channels))
+	for _, ch := range n.channels {
+		routes = append(routes, Route{Channel: ch.Name, Rule: ch.Rule, Matched: ch.rule.Match(event)})
+	}
+	return routes
+}
+
+// Ended marks a streamer's announcement for the platform as ended, editing
+// it where the channel supports that.
+func (n *Notifier) Ended(ctx context.Context, a Announcement) error {
+	if n == nil {
+		return nil
+	}
+	n.busy.Lock()
+	defer n.busy.Unlock()
+	a.Ended = true
+	var errs []error
+	for _, ch := range n.channels {
+		if err := n.ended(ctx, ch, a); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ch.Name, err))
+		}
+	}
+	errs = append(errs, n.save())
+	return errors.Join(errs...)
+}
+
+func (n *Notifier) live(ctx context.Context, ch channel, a Announcement) error {
+	key := ch.Name + "\x00" + a.StreamerID
+	now := n.now()
+	last, ok := n.lookup(key)
+	if ok && now.Sub(last.PostedAt) < ch.Cooldown {
+		if !last.Ended || last.Platform != a.Platform || last.Ref == "" {
+			return nil
+		}
+		msg, err := render(ch, a)
+		if err != nil {
+			return err
+		}
+		if err := ch.sender.edit(ctx, n.client, ch.Channel, last.Ref, msg); err != nil {
+			if errors.Is(err, errEditUnsupported) {
+				return nil
+			}
+			return err
+		}
+		last.Ended = false
+		n.record(key, last)
+		return nil
+	}
+	msg, err := render(ch, a)
+	if err != nil {
+		return err
+	}
+	ref, err := ch.sender.post(ctx, n.client, ch.Channel, msg)
+	if err != nil {
+		return err
+	}
+	n.record(key, posted{Ref: ref, Platform: a.Platform, PostedAt: now})
+	return nil
+}
+
+func (n *Notifier) ended(ctx context.Context, ch channel, a Announcement) error {
+	key := ch.Name + "\x00" + a.StreamerID
+	last, ok := n.lookup(key)
+	if !ok || last.Ended || last.Platform != a.Platform {
+		return nil
+	}
+	last.Ended = true
+	n.record(key, last)
+	if last.Ref == "" {
+		return nil
+	}
+	msg, err := render(ch, a)
+	if err != nil {
+		return err
+	}
+	if err := ch.sender.edit(ctx, n.client, ch.Channel, last.Ref, msg); err != nil && !errors.Is(err, errEditUnsupported) {
+		return err
+	}
+	return nil
+}
+
+func render(ch channel, a Announcement) (message, error) {
+	var buf bytes.Buffer
+	if err := ch.tmpl.Execute(&buf, a); err != nil {
+		return message{}, fmt.Errorf("render template: %w", err)
+	}
+	return message{Announcement: a, Text: strings.TrimSpace(buf.String())}, nil
+}
+
+func (n *Notifier) lookup(key string) (posted, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.state == nil {
+		n.state = n.load()
+	}
+	last, ok := n.state[key]
+	return last, ok
+}
+
+func (n *Notifier) record(key string, last posted) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.state[key] = last
+	n.dirty = true
+}
+
+func (n *Notifier) load() map[string]posted {
+	state := map[string]posted{}
+	if n.path == "" {
+		return state
+	}
+	data, err := os.ReadFile(n.path)
+	if err != nil {
+		return state
+	}
+	_ = json.Unmarshal(data, &state)
+	return state
+}
+
+func (n *Notifier) save() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if !n.dirty || n.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(n.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode announcement state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(n.path), 0o755); err != nil {
+		return fmt.Errorf("create announcement state dir: %w", err)
+	}
+	if err := os.WriteFile(n.path, data, 0o644); err != nil {
+		return fmt.Errorf("write announcement state: %w", err)
+	}
+	n.dirty = false
+	return nil
+}
+
+// send issues a JSON request and decodes a JSON response into out when set.
+func send(ctx context.Context, client *http.Client, method, target string, header http.Header, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encode message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s %s responded %s", method, redact(target), resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// redact drops the path of webhook URLs, which carry their secret token.
+func redact(target string) string {
+	if i := strings.Index(target, "://"); i >= 0 {
+		if j := strings.Index(target[i+3:], "/"); j >= 0 {
+			return target[:i+3+j] + "/…"
+		}
+	}
+	return target
+}
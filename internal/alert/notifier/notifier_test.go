@@ -0,0 +1,252 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// standIn records the requests a chat service receives and answers with
+// reply.
+type standIn struct {
+	mu       sync.Mutex
+	requests []recorded
+	reply    string
+}
+
+type recorded struct {
+	Method string
+	Path   string
+	Query  string
+	Auth   string
+	Body   map[string]any
+}
+
+func (s *standIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	raw, _ := io.ReadAll(r.Body)
+	var body map[string]any
+	_ = json.Unmarshal(raw, &body)
+	s.requests = append(s.requests, recorded{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery, Auth: r.Header.Get("Authorization"), Body: body})
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = io.WriteString(w, s.reply)
+}
+
+func (s *standIn) methods() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []string
+	for _, req := range s.requests {
+		out = append(out, req.Method)
+	}
+	return strings.Join(out, ",")
+}
+
+func (s *standIn) last() recorded {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[len(s.requests)-1]
+}
+
+func testAnnouncement() Announcement {
+	return Announcement{
+		StreamerID:   "c",
+		Alias:        "Charlie",
+		Platform:     "twitch",
+		PlatformName: "Twitch",
+		URL:          "https://www.twitch.tv/charlie",
+		PageURL:      "https://synth.example/streamers/Charlie",
+		Site:         "synth.wave",
+		StartedAt:    time.Date(2025, 6, 1, 20, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestNewValidatesChannels(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel Channel
+	}{
+		{"unknown kind", Channel{Kind: "irc", URL: "https://example.com"}},
+		{"missing url", Channel{Kind: KindSlack}},
+		{"matrix without token", Channel{Kind: KindMatrix, URL: "https://matrix.example", Room: "!room"}},
+		{"bad template", Channel{Kind: KindDiscord, URL: "https://example.com", Template: "{{.Alias"}},
+		{"bad rule", Channel{Kind: KindDiscord, URL: "https://example.com", Rule: "colour:red"}},
+	}
+	for _, tc := range tests {
+		if _, err := New([]Channel{tc.channel}, Options{}); err == nil {
+			t.Fatalf("%s: expected an error", tc.name)
+		}
+	}
+	if _, err := New([]Channel{{Kind: "teams", URL: "https://example.com"}}, Options{}); !errors.Is(err, ErrUnknownKind) {
+		t.Fatalf("expected ErrUnknownKind, got %v", err)
+	}
+}
+
+func TestDiscordCooldownAndEditInPlace(t *testing.T) {
+	discordStandIn := &standIn{reply: `{"id":"m1"}`}
+	target := httptest.NewServer(discordStandIn)
+	defer target.Close()
+
+	n, err := New([]Channel{{Kind: KindDiscord, URL: target.URL + "/api/webhooks/1/token", Cooldown: time.Hour}}, Options{})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	clock := time.Date(2025, 6, 1, 20, 0, 0, 0, time.UTC)
+	n.now = func() time.Time { return clock }
+	ctx := context.Background()
+	a := testAnnouncement()
+
+	if err := n.Live(ctx, a); err != nil {
+		t.Fatalf("live: %v", err)
+	}
+	created := discordStandIn.last()
+	if created.Method != http.MethodPost || created.Query != "wait=true" {
+		t.Fatalf("expected a waited webhook post, got %+v", created)
+	}
+	embed := created.Body["embeds"].([]any)[0].(map[string]any)
+	if embed["title"] != "Charlie is live on Twitch: https://www.twitch.tv/charlie" || embed["url"] != a.URL || embed["color"].(float64) != discordLiveColor {
+		t.Fatalf("unexpected embed %v", embed)
+	}
+
+	// A repeat inside the cooldown is dropped.
+	if err := n.Live(ctx, a); err != nil || discordStandIn.methods() != "POST" {
+		t.Fatalf("expected the repeat to be suppressed, got %s (%v)", discordStandIn.methods(), err)
+	}
+
+	if err := n.Ended(ctx, a); err != nil {
+		t.Fatalf("ended: %v", err)
+	}
+	edited := discordStandIn.last()
+	embed = edited.Body["embeds"].([]any)[0].(map[string]any)
+	if edited.Method != http.MethodPatch || edited.Path != "/api/webhooks/1/token/messages/m1" || embed["title"] != "Charlie's Twitch stream has ended." || embed["color"].(float64) != discordEndedColor {
+		t.Fatalf("expected the message to be edited to ended, got %+v", edited)
+	}
+
+	// Flapping back live inside the cooldown restores the same message.
+	clock = clock.Add(5 * time.Minute)
+	if err := n.Live(ctx, a); err != nil || discordStandIn.methods() != "POST,PATCH,PATCH" {
+		t.Fatalf("expected the message to be restored in place, got %s (%v)", discordStandIn.methods(), err)
+	}
+
+	clock = clock.Add(2 * time.Hour)
+	if err := n.Live(ctx, a); err != nil || discordStandIn.methods() != "POST,PATCH,PATCH,POST" {
+		t.Fatalf("expected a new post after the cooldown, got %s (%v)", discordStandIn.methods(), err)
+	}
+}
+
+func TestSlackBlocks(t *testing.T) {
+	slackStandIn := &standIn{reply: "ok"}
+	target := httptest.NewServer(slackStandIn)
+	defer target.Close()
+
+	n, err := New([]Channel{{Kind: KindSlack, URL: target.URL, Template: "{{.Alias}} & friends are live"}}, Options{})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	ctx := context.Background()
+	if err := n.Live(ctx, testAnnouncement()); err != nil {
+		t.Fatalf("live: %v", err)
+	}
+	body := slackStandIn.last().Body
+	section := body["blocks"].([]any)[0].(map[string]any)["text"].(map[string]any)
+	if body["text"] != "Charlie & friends are live" || section["text"] != "Charlie &amp; friends are live\n<https://www.twitch.tv/charlie|Watch now>" {
+		t.Fatalf("unexpected slack message %v", body)
+	}
+	if err := n.Ended(ctx, testAnnouncement()); err != nil || slackStandIn.methods() != "POST" {
+		t.Fatalf("expected slack messages to be left as posted, got %s (%v)", slackStandIn.methods(), err)
+	}
+}
+
+func TestMatrixEdits(t *testing.T) {
+	matrixStandIn := &standIn{reply: `{"event_id":"$ev1"}`}
+	target := httptest.NewServer(matrixStandIn)
+	defer target.Close()
+
+	statePath := filepath.Join(t.TempDir(), "announcements.json")
+	channels := []Channel{{Kind: KindMatrix, URL: target.URL, Room: "!room:example.org", Token: "bot-token"}}
+	n, err := New(channels, Options{StatePath: statePath})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := n.Live(context.Background(), testAnnouncement()); err != nil {
+		t.Fatalf("live: %v", err)
+	}
+	sent := matrixStandIn.last()
+	if sent.Method != http.MethodPut || !strings.HasPrefix(sent.Path, "/_matrix/client/v3/rooms/!room:example.org/send/m.room.message/") || sent.Auth != "Bearer bot-token" {
+		t.Fatalf("unexpected matrix request %+v", sent)
+	}
+	if sent.Body["msgtype"] != "m.text" || !strings.Contains(sent.Body["body"].(string), "is live on Twitch") {
+		t.Fatalf("unexpected matrix content %v", sent.Body)
+	}
+
+	// A restarted notifier finds the event ID in its saved state.
+	restarted, err := New(channels, Options{StatePath: statePath})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := restarted.Ended(context.Background(), testAnnouncement()); err != nil {
+		t.Fatalf("ended: %v", err)
+	}
+	edit := matrixStandIn.last().Body
+	relation := edit["m.relates_to"].(map[string]any)
+	replacement := edit["m.new_content"].(map[string]any)
+	if relation["rel_type"] != "m.replace" || relation["event_id"] != "$ev1" || replacement["body"] != "Charlie's Twitch stream has ended." || edit["body"] != "* Charlie's Twitch stream has ended." {
+		t.Fatalf("unexpected matrix edit %v", edit)
+	}
+}
+
+func TestEndedIgnoresOtherPlatforms(t *testing.T) {
+	discordStandIn := &standIn{reply: `{"id":"m1"}`}
+	target := httptest.NewServer(discordStandIn)
+	defer target.Close()
+
+	n, _ := New([]Channel{{Kind: KindDiscord, URL: target.URL}}, Options{})
+	ctx := context.Background()
+	_ = n.Live(ctx, testAnnouncement())
+	other := testAnnouncement()
+	other.Platform, other.PlatformName = "youtube", "YouTube"
+	if err := n.Ended(ctx, other); err != nil || discordStandIn.methods() != "POST" {
+		t.Fatalf("expected a YouTube end to leave the Twitch message alone, got %s (%v)", discordStandIn.methods(), err)
+	}
+}
+
+func TestRulesChooseChannels(t *testing.T) {
+	spanish := &standIn{reply: `{"id":"es"}`}
+	main := &standIn{reply: `{"id":"main"}`}
+	spanishTarget := httptest.NewServer(spanish)
+	defer spanishTarget.Close()
+	mainTarget := httptest.NewServer(main)
+	defer mainTarget.Close()
+
+	n, err := New([]Channel{
+		{Name: "spanish", Kind: KindDiscord, URL: spanishTarget.URL, Rule: "language:Spanish"},
+		{Name: "main", Kind: KindDiscord, URL: mainTarget.URL, Rule: "-type:rerun"},
+	}, Options{})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	ctx := context.Background()
+	rerun := testAnnouncement()
+	rerun.Languages = []string{"Spanish"}
+	rerun.StreamType = "rerun"
+	if err := n.Live(ctx, rerun); err != nil {
+		t.Fatalf("live: %v", err)
+	}
+	if spanish.methods() != "POST" || main.methods() != "" {
+		t.Fatalf("expected only the Spanish channel, got %q and %q", spanish.methods(), main.methods())
+	}
+
+	// Channels the rule skipped have nothing to mark ended.
+	if err := n.Ended(ctx, rerun); err != nil || main.methods() != "" {
+		t.Fatalf("expected the main channel to stay quiet, got %q (%v)", main.methods(), err)
+	}
+}
@@ -0,0 +1,56 @@
+package notifier
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// slack posts Block Kit messages to an incoming webhook. Incoming webhooks
+// return no message reference, so posts cannot be edited later.
+type slack struct{}
+
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func (slack) format(msg message) slackMessage {
+	text := slackEscaper.Replace(msg.Text)
+	if msg.URL != "" && !strings.Contains(msg.Text, msg.URL) {
+		text += "\n<" + msg.URL + "|Watch now>"
+	}
+	blocks := []slackBlock{{Type: "section", Text: &slackText{Type: "mrkdwn", Text: text}}}
+	var footer []string
+	if msg.Site != "" {
+		footer = append(footer, slackEscaper.Replace(msg.Site))
+	}
+	if msg.PageURL != "" {
+		footer = append(footer, "<"+msg.PageURL+"|Profile>")
+	}
+	if len(footer) > 0 {
+		blocks = append(blocks, slackBlock{Type: "context", Elements: []slackText{{Type: "mrkdwn", Text: strings.Join(footer, " · ")}}})
+	}
+	return slackMessage{Text: msg.Text, Blocks: blocks}
+}
+
+func (s slack) post(ctx context.Context, client *http.Client, ch Channel, msg message) (string, error) {
+	return "", send(ctx, client, http.MethodPost, ch.URL, nil, s.format(msg), nil)
+}
+
+func (slack) edit(context.Context, *http.Client, Channel, string, message) error {
+	return errEditUnsupported
+}
@@ -38,6 +38,7 @@ type StreamResult struct {
 	Title       string
 	ViewerCount int
 	GameName    string
+	Tags        []string
 }
 
 type streamsResponse struct {
@@ -108,6 +109,7 @@ func GetStreams(ctx context.Context, client *http.Client, auth *Authenticator, b
 			Title:       stream.Title,
 			ViewerCount: stream.ViewerCount,
 			GameName:    stream.GameName,
+			Tags:        stream.Tags,
 		}
 	}
 
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
@@ -52,6 +53,12 @@ type EventSubHandler struct {
 	// GetAllStores returns all streamer stores across sites for multi-site support.
 	// If nil, only StreamersStore is used.
 	GetAllStores func() map[string]*streamers.Store
+
+	// Index maps broadcaster IDs to streamers. If nil, one is built over
+	// getStores on first use.
+	Index *streamers.ChannelIndex
+
+	indexOnce sync.Once
 }
 
 // EventSubPayload represents the common structure of EventSub webhook payloads.
@@ -321,44 +328,38 @@ func (h *EventSubHandler) handleStreamOffline(payload EventSubPayload) {
 
 // updateStreamerLive marks a Twitch streamer as live.
 func (h *EventSubHandler) updateStreamerLive(broadcasterID, streamID string, startedAt time.Time) {
-	stores := h.getStores()
-
-	for siteKey, store := range stores {
-		records, err := store.List()
+	for _, ref := range h.index().Twitch(broadcasterID) {
+		siteKey, store := ref.Site, ref.Store
+		record, err := store.Shared().Get(ref.StreamerID)
 		if err != nil {
-			fmt.Printf("WARNING: Failed to list streamers for site %s: %v\n", siteKey, err)
+			fmt.Printf("WARNING: Indexed streamer %s missing from site %s: %v\n", ref.StreamerID, siteKey, err)
 			continue
 		}
+		fmt.Printf("INFO: Found matching streamer: %s (site: %s)\n", record.Streamer.Alias, siteKey)
 
-		for _, record := range records {
-			if record.Platforms.Twitch != nil && record.Platforms.Twitch.BroadcasterID == broadcasterID {
-				fmt.Printf("INFO: Found matching streamer: %s (site: %s)\n", record.Streamer.Alias, siteKey)
-
-				_, err := store.SetTwitchLive(broadcasterID, streamID, startedAt)
-				if err != nil {
-					fmt.Printf("ERROR: Failed to set Twitch live status: %v\n", err)
-					if h.Logger != nil {
-						h.Logger.Error("twitch-eventsub", "Failed to set Twitch live status", err, map[string]any{
-							"broadcasterId": broadcasterID,
-							"streamerId":    record.Streamer.ID,
-							"site":          siteKey,
-						})
-					}
-				} else {
-					fmt.Printf("SUCCESS: Updated streamer %s to LIVE\n", record.Streamer.Alias)
-					if h.Logger != nil {
-						h.Logger.Info("twitch-eventsub", "Set streamer live", map[string]any{
-							"broadcasterId": broadcasterID,
-							"streamerId":    record.Streamer.ID,
-							"alias":         record.Streamer.Alias,
-							"streamId":      streamID,
-							"site":          siteKey,
-						})
-					}
-				}
-				return
+		_, err = store.SetTwitchLive(broadcasterID, streamID, startedAt)
+		if err != nil {
+			fmt.Printf("ERROR: Failed to set Twitch live status: %v\n", err)
+			if h.Logger != nil {
+				h.Logger.Error("twitch-eventsub", "Failed to set Twitch live status", err, map[string]any{
+					"broadcasterId": broadcasterID,
+					"streamerId":    record.Streamer.ID,
+					"site":          siteKey,
+				})
+			}
+		} else {
+			fmt.Printf("SUCCESS: Updated streamer %s to LIVE\n", record.Streamer.Alias)
+			if h.Logger != nil {
+				h.Logger.Info("twitch-eventsub", "Set streamer live", map[string]any{
+					"broadcasterId": broadcasterID,
+					"streamerId":    record.Streamer.ID,
+					"alias":         record.Streamer.Alias,
+					"streamId":      streamID,
+					"site":          siteKey,
+				})
 			}
 		}
+		return
 	}
 
 	fmt.Printf("WARNING: No streamer found for broadcaster ID: %s\n", broadcasterID)
@@ -371,43 +372,37 @@ func (h *EventSubHandler) updateStreamerLive(broadcasterID, streamID string, sta
 
 // updateStreamerOffline marks a Twitch streamer as offline.
 func (h *EventSubHandler) updateStreamerOffline(broadcasterID string) {
-	stores := h.getStores()
-
-	for siteKey, store := range stores {
-		records, err := store.List()
+	for _, ref := range h.index().Twitch(broadcasterID) {
+		siteKey, store := ref.Site, ref.Store
+		record, err := store.Shared().Get(ref.StreamerID)
 		if err != nil {
-			fmt.Printf("WARNING: Failed to list streamers for site %s: %v\n", siteKey, err)
+			fmt.Printf("WARNING: Indexed streamer %s missing from site %s: %v\n", ref.StreamerID, siteKey, err)
 			continue
 		}
+		fmt.Printf("INFO: Found matching streamer: %s (site: %s)\n", record.Streamer.Alias, siteKey)
 
-		for _, record := range records {
-			if record.Platforms.Twitch != nil && record.Platforms.Twitch.BroadcasterID == broadcasterID {
-				fmt.Printf("INFO: Found matching streamer: %s (site: %s)\n", record.Streamer.Alias, siteKey)
-
-				_, err := store.ClearTwitchLive(broadcasterID)
-				if err != nil {
-					fmt.Printf("ERROR: Failed to clear Twitch live status: %v\n", err)
-					if h.Logger != nil {
-						h.Logger.Error("twitch-eventsub", "Failed to clear Twitch live status", err, map[string]any{
-							"broadcasterId": broadcasterID,
-							"streamerId":    record.Streamer.ID,
-							"site":          siteKey,
-						})
-					}
-				} else {
-					fmt.Printf("SUCCESS: Updated streamer %s to OFFLINE\n", record.Streamer.Alias)
-					if h.Logger != nil {
-						h.Logger.Info("twitch-eventsub", "Set streamer offline", map[string]any{
-							"broadcasterId": broadcasterID,
-							"streamerId":    record.Streamer.ID,
-							"alias":         record.Streamer.Alias,
-							"site":          siteKey,
-						})
-					}
-				}
-				return
+		_, err = store.ClearTwitchLive(broadcasterID)
+		if err != nil {
+			fmt.Printf("ERROR: Failed to clear Twitch live status: %v\n", err)
+			if h.Logger != nil {
+				h.Logger.Error("twitch-eventsub", "Failed to clear Twitch live status", err, map[string]any{
+					"broadcasterId": broadcasterID,
+					"streamerId":    record.Streamer.ID,
+					"site":          siteKey,
+				})
+			}
+		} else {
+			fmt.Printf("SUCCESS: Updated streamer %s to OFFLINE\n", record.Streamer.Alias)
+			if h.Logger != nil {
+				h.Logger.Info("twitch-eventsub", "Set streamer offline", map[string]any{
+					"broadcasterId": broadcasterID,
+					"streamerId":    record.Streamer.ID,
+					"alias":         record.Streamer.Alias,
+					"site":          siteKey,
+				})
 			}
 		}
+		return
 	}
 
 	fmt.Printf("WARNING: No streamer found for broadcaster ID: %s\n", broadcasterID)
@@ -418,6 +413,17 @@ func (h *EventSubHandler) updateStreamerOffline(broadcasterID string) {
 	}
 }
 
+// index returns the broadcaster index, building one over getStores when none
+// was injected.
+func (h *EventSubHandler) index() *streamers.ChannelIndex {
+	h.indexOnce.Do(func() {
+		if h.Index == nil {
+			h.Index = streamers.NewChannelIndex(h.getStores)
+		}
+	})
+	return h.Index
+}
+
 // getStores returns all streamer stores to check.
 func (h *EventSubHandler) getStores() map[string]*streamers.Store {
 	if h.GetAllStores != nil {
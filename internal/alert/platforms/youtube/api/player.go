@@ -97,6 +97,7 @@ type LiveStatus struct {
 	IsLive            bool
 	IsLiveNow         bool
 	StartedAt         time.Time
+	EndedAt           time.Time
 	PlayabilityStatus string
 }
 
@@ -161,6 +162,11 @@ func (c *PlayerClient) LiveStatus(ctx context.Context, videoID string) (LiveStat
 				status.StartedAt = parsed
 			}
 		}
+		if ts := strings.TrimSpace(details.EndTimestamp); ts != "" {
+			if parsed, err := time.Parse(time.RFC3339, ts); err == nil {
+				status.EndedAt = parsed
+			}
+		}
 	}
 
 	return status, nil
@@ -189,6 +195,7 @@ type playerResponse struct {
 			LiveBroadcastDetails *struct {
 				IsLiveNow      bool   `json:"isLiveNow"`
 				StartTimestamp string `json:"startTimestamp"`
+				EndTimestamp   string `json:"endTimestamp"`
 			} `json:"liveBroadcastDetails"`
 		} `json:"playerMicroformatRenderer"`
 	} `json:"microformat"`
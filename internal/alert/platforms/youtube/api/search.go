@@ -17,6 +17,7 @@ type SearchLiveResult struct {
 	VideoID   string
 	StartedAt time.Time
 	ChannelID string
+	Title     string
 }
 
 // SearchClient queries the YouTube Data API search endpoint.
@@ -85,6 +86,7 @@ func (c SearchClient) LiveNow(ctx context.Context, channelID string) (SearchLive
 		result := SearchLiveResult{
 			VideoID:   id,
 			ChannelID: strings.TrimSpace(item.Snippet.ChannelID),
+			Title:     strings.TrimSpace(item.Snippet.Title),
 		}
 		if ts := strings.TrimSpace(item.Snippet.PublishedAt); ts != "" {
 			if parsed, err := time.Parse(time.RFC3339, ts); err == nil {
@@ -112,6 +114,7 @@ type searchResponse struct {
 		Snippet struct {
 			PublishedAt string `json:"publishedAt"`
 			ChannelID   string `json:"channelId"`
+			Title       string `json:"title"`
 		} `json:"snippet"`
 	} `json:"items"`
 }
@@ -0,0 +1,265 @@
+// Package streamend notices when live YouTube broadcasts end. WebSub only
+// pushes when a video is published or updated, so without polling a channel
+// stays marked live after its stream stops.
+package streamend
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/platforms/youtube/api"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+)
+
+const (
+	defaultMinInterval = time.Minute
+	defaultMaxInterval = 8 * time.Minute
+	defaultScan        = 30 * time.Second
+	checkTimeout       = 15 * time.Second
+)
+
+// Checker reports the player status of a video. *api.PlayerClient satisfies
+// it, and its HTTP client goes through the shared YouTube rate limiter.
+type Checker interface {
+	LiveStatus(ctx context.Context, videoID string) (api.LiveStatus, error)
+}
+
+// Config configures a Tracker.
+type Config struct {
+	Store *streamers.Store
+	// Checker defaults to a player API client.
+	Checker Checker
+	// MinInterval is the first delay before checking a stream, and the delay
+	// used to confirm a stream that looks ended. Each check that finds the
+	// stream still live doubles the delay, up to MaxInterval.
+	MinInterval time.Duration
+	MaxInterval time.Duration
+	// Scan is how often the roster is read for newly live videos.
+	Scan time.Duration
+	Now  func() time.Time
+	// OnEnded is called with the updated record once a stream is marked ended.
+	OnEnded func(streamers.Record)
+	OnError func(error)
+}
+
+// Tracker polls the live YouTube videos in a roster until their broadcasts
+// end, then clears the channel's live status and records the end time.
+type Tracker struct {
+	cfg    Config
+	mu     sync.Mutex
+	videos map[string]*video
+	cancel context.CancelFunc
+	runWg  sync.WaitGroup
+}
+
+// video is the polling state for one live video.
+type video struct {
+	channelID string
+	interval  time.Duration
+	next      time.Time
+	// ended counts consecutive checks that found the broadcast over; one is
+	// confirmed by a second before the status is cleared. endedAt is the
+	// player's end time, or when the first of those checks ran.
+	ended   int
+	endedAt time.Time
+}
+
+// Start launches a tracker using the provided context.
+func Start(ctx context.Context, cfg Config) *Tracker {
+	t := newTracker(cfg)
+	runCtx, cancel := context.WithCancel(ctx)
+	t.cancel = cancel
+	t.runWg.Add(1)
+	go func() {
+		defer t.runWg.Done()
+		t.run(runCtx)
+	}()
+	return t
+}
+
+func newTracker(cfg Config) *Tracker {
+	if cfg.Checker == nil {
+		cfg.Checker = api.NewPlayerClient(api.PlayerClientOptions{HTTPClient: &http.Client{Timeout: checkTimeout}})
+	}
+	if cfg.MinInterval <= 0 {
+		cfg.MinInterval = defaultMinInterval
+	}
+	if cfg.MaxInterval < cfg.MinInterval {
+		cfg.MaxInterval = max(defaultMaxInterval, cfg.MinInterval)
+	}
+	if cfg.Scan <= 0 {
+		cfg.Scan = defaultScan
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &Tracker{cfg: cfg, videos: make(map[string]*video)}
+}
+
+func (t *Tracker) run(ctx context.Context) {
+	t.poll(ctx)
+
+	ticker := time.NewTicker(t.cfg.Scan)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.poll(ctx)
+		}
+	}
+}
+
+// poll syncs the tracked videos with the roster and checks those that are
+// due, one at a time so the rate limiter paces them.
+func (t *Tracker) poll(ctx context.Context) {
+	records, err := t.cfg.Store.List()
+	if err != nil {
+		t.reportError(fmt.Errorf("stream end tracker: failed to read streamers file: %w", err))
+		return
+	}
+	now := t.cfg.Now().UTC()
+	due := t.sync(records, now)
+	for _, id := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		t.check(ctx, id)
+	}
+}
+
+// sync starts tracking newly live videos, forgets videos that are no longer
+// live in the roster, and returns the IDs due for a check.
+func (t *Tracker) sync(records []streamers.Record, now time.Time) []string {
+	live := make(map[string]string)
+	for _, record := range records {
+		yt := record.Platforms.YouTube
+		if yt == nil || record.Status == nil || record.Status.YouTube == nil || !record.Status.YouTube.Live {
+			continue
+		}
+		id := strings.TrimSpace(record.Status.YouTube.VideoID)
+		channelID := strings.TrimSpace(yt.ChannelID)
+		if id == "" || channelID == "" {
+			continue
+		}
+		live[id] = channelID
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id := range t.videos {
+		if _, ok := live[id]; !ok {
+			delete(t.videos, id)
+		}
+	}
+	var due []string
+	for id, channelID := range live {
+		v, ok := t.videos[id]
+		if !ok {
+			t.videos[id] = &video{channelID: channelID, interval: t.cfg.MinInterval, next: now.Add(t.cfg.MinInterval)}
+			continue
+		}
+		if !now.Before(v.next) {
+			due = append(due, id)
+		}
+	}
+	return due
+}
+
+func (t *Tracker) check(ctx context.Context, id string) {
+	checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+	status, err := t.cfg.Checker.LiveStatus(checkCtx, id)
+	cancel()
+
+	t.mu.Lock()
+	v, ok := t.videos[id]
+	if !ok {
+		t.mu.Unlock()
+		return
+	}
+	now := t.cfg.Now().UTC()
+	switch {
+	case err != nil:
+		v.next = now.Add(v.interval)
+		t.mu.Unlock()
+		t.reportError(fmt.Errorf("stream end tracker: check %s: %w", id, err))
+		return
+	case !broadcastEnded(status):
+		v.ended = 0
+		v.endedAt = time.Time{}
+		v.interval = min(v.interval*2, t.cfg.MaxInterval)
+		v.next = now.Add(v.interval)
+		t.mu.Unlock()
+		return
+	}
+	v.ended++
+	if v.endedAt.IsZero() {
+		v.endedAt = status.EndedAt
+	}
+	if v.endedAt.IsZero() {
+		v.endedAt = now
+	}
+	if v.ended < 2 {
+		v.next = now.Add(t.cfg.MinInterval)
+		t.mu.Unlock()
+		return
+	}
+	delete(t.videos, id)
+	channelID, endedAt := v.channelID, v.endedAt
+	t.mu.Unlock()
+
+	record, err := t.cfg.Store.EndYouTubeStream(channelID, id, endedAt.UTC())
+	if err != nil {
+		t.reportError(fmt.Errorf("stream end tracker: end %s: %w", id, err))
+		return
+	}
+	if record.Status != nil && record.Status.YouTube != nil && record.Status.YouTube.Live {
+		// The channel moved on to another video in the meantime.
+		return
+	}
+	if t.cfg.OnEnded != nil {
+		t.cfg.OnEnded(record)
+	}
+}
+
+// broadcastEnded reports whether the player says the broadcast is over. A
+// video the player cannot show, such as a members-only stream, is assumed to
+// be live until it says otherwise, while a removed video has ended.
+func broadcastEnded(status api.LiveStatus) bool {
+	if !status.EndedAt.IsZero() {
+		return true
+	}
+	switch strings.ToUpper(status.PlayabilityStatus) {
+	case "OK":
+		return !status.IsLiveNow
+	case "ERROR":
+		return true
+	default:
+		return false
+	}
+}
+
+// Stop cancels the tracker and waits for it to finish.
+func (t *Tracker) Stop() {
+	if t == nil {
+		return
+	}
+	if t.cancel != nil {
+		t.cancel()
+	}
+	t.runWg.Wait()
+}
+
+func (t *Tracker) reportError(err error) {
+	if err == nil {
+		return
+	}
+	if t.cfg.OnError != nil {
+		t.cfg.OnError(err)
+	}
+}
@@ -0,0 +1,166 @@
+package streamend
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/platforms/youtube/api"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+)
+
+// stubChecker answers with the next queued status for each video.
+type stubChecker struct {
+	mu      sync.Mutex
+	answers map[string][]api.LiveStatus
+	calls   []string
+}
+
+func (c *stubChecker) LiveStatus(_ context.Context, videoID string) (api.LiveStatus, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, videoID)
+	queue := c.answers[videoID]
+	if len(queue) == 0 {
+		return api.LiveStatus{}, errors.New("no answer queued")
+	}
+	c.answers[videoID] = queue[1:]
+	return queue[0], nil
+}
+
+func (c *stubChecker) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.calls)
+}
+
+var (
+	stillLive = api.LiveStatus{IsLive: true, IsLiveNow: true, PlayabilityStatus: "OK"}
+	offAir    = api.LiveStatus{IsLive: true, PlayabilityStatus: "OK"}
+)
+
+func TestTrackerEndsStreams(t *testing.T) {
+	store := streamers.NewStore(filepath.Join(t.TempDir(), "streamers.json"))
+	if _, err := store.Append(streamers.Record{
+		Streamer:  streamers.Streamer{ID: "a", Alias: "alpha"},
+		Platforms: streamers.Platforms{YouTube: &streamers.YouTubePlatform{ChannelID: "UCalpha"}},
+	}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	started := time.Date(2025, 6, 1, 20, 0, 0, 0, time.UTC)
+	if _, err := store.SetYouTubeLive("UCalpha", "vid1", started); err != nil {
+		t.Fatalf("set live: %v", err)
+	}
+
+	endedAt := started.Add(95 * time.Minute)
+	checker := &stubChecker{answers: map[string][]api.LiveStatus{
+		"vid1": {stillLive, stillLive, offAir, stillLive, {IsLive: true, PlayabilityStatus: "OK", EndedAt: endedAt}, offAir},
+	}}
+	clock := started
+	var ended []streamers.Record
+	tracker := newTracker(Config{
+		Store:       store,
+		Checker:     checker,
+		MinInterval: time.Minute,
+		MaxInterval: 3 * time.Minute,
+		Now:         func() time.Time { return clock },
+		OnEnded:     func(record streamers.Record) { ended = append(ended, record) },
+	})
+	ctx := context.Background()
+	step := func(d time.Duration, wantCalls int) {
+		t.Helper()
+		clock = clock.Add(d)
+		tracker.poll(ctx)
+		if got := checker.count(); got != wantCalls {
+			t.Fatalf("after %s: expected %d checks, got %d", clock.Sub(started), wantCalls, got)
+		}
+	}
+
+	step(0, 0)             // picked up, first check in a minute
+	step(time.Minute, 1)   // live: back off to 2m
+	step(time.Minute, 1)   // not yet due
+	step(time.Minute, 2)   // live: back off to the 3m cap
+	step(3*time.Minute, 3) // looks ended: confirm in a minute
+	step(time.Minute, 4)   // live again, so it was a blip
+	step(3*time.Minute, 5) // ended with an end time
+	if len(ended) != 0 {
+		t.Fatalf("expected a single ended check to wait for confirmation, got %+v", ended)
+	}
+	step(time.Minute, 6) // confirmed
+
+	if len(ended) != 1 {
+		t.Fatalf("expected the stream to be ended once, got %+v", ended)
+	}
+	record, err := store.FindByChannel("UCalpha", "")
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	yt := record.Status.YouTube
+	if yt.Live || yt.VideoID != "" || !yt.EndedAt.Equal(endedAt) || record.Status.Live {
+		t.Fatalf("expected the channel to be offline with the end time, got %+v", yt)
+	}
+	step(time.Hour, 6)
+}
+
+func TestTrackerLeavesReplacedStreams(t *testing.T) {
+	store := streamers.NewStore(filepath.Join(t.TempDir(), "streamers.json"))
+	if _, err := store.Append(streamers.Record{
+		Streamer:  streamers.Streamer{ID: "a", Alias: "alpha"},
+		Platforms: streamers.Platforms{YouTube: &streamers.YouTubePlatform{ChannelID: "UCalpha"}},
+	}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if _, err := store.SetYouTubeLive("UCalpha", "vid1", time.Time{}); err != nil {
+		t.Fatalf("set live: %v", err)
+	}
+	checker := &stubChecker{answers: map[string][]api.LiveStatus{"vid1": {offAir, offAir}}}
+	clock := time.Date(2025, 6, 1, 20, 0, 0, 0, time.UTC)
+	var errs []error
+	tracker := newTracker(Config{
+		Store:   store,
+		Checker: checker,
+		Now:     func() time.Time { return clock },
+		OnEnded: func(record streamers.Record) { t.Fatalf("unexpected end %+v", record) },
+		OnError: func(err error) { errs = append(errs, err) },
+	})
+	ctx := context.Background()
+	tracker.poll(ctx)
+	clock = clock.Add(time.Minute)
+	tracker.poll(ctx)
+
+	// The channel starts a new video before the end is confirmed.
+	if _, err := store.SetYouTubeLive("UCalpha", "vid2", time.Time{}); err != nil {
+		t.Fatalf("set live: %v", err)
+	}
+	clock = clock.Add(time.Minute)
+	tracker.poll(ctx)
+	record, _ := store.FindByChannel("UCalpha", "")
+	if !record.Status.YouTube.Live || record.Status.YouTube.VideoID != "vid2" {
+		t.Fatalf("expected the new video to stay live, got %+v", record.Status.YouTube)
+	}
+	if checker.count() != 1 || len(errs) != 0 {
+		t.Fatalf("expected the replaced video to be dropped, got %d checks and %v", checker.count(), errs)
+	}
+}
+
+func TestBroadcastEnded(t *testing.T) {
+	tests := []struct {
+		name   string
+		status api.LiveStatus
+		want   bool
+	}{
+		{"live", stillLive, false},
+		{"off air", offAir, true},
+		{"end time", api.LiveStatus{PlayabilityStatus: "LOGIN_REQUIRED", EndedAt: time.Now()}, true},
+		{"members only", api.LiveStatus{PlayabilityStatus: "LOGIN_REQUIRED"}, false},
+		{"removed", api.LiveStatus{PlayabilityStatus: "ERROR"}, true},
+	}
+	for _, tt := range tests {
+		if got := broadcastEnded(tt.status); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+// Package rules is a small language deciding which announcement channels
+// hear about a live stream. A rule is a list of terms separated by spaces,
+// and a stream must satisfy every term:
+//
+//	language:Spanish,Portuguese  the streamer speaks any of the languages
+//	platform:twitch              the stream is on twitch or youtube
+//	tag:cozy,chill               the stream carries any of the tags
+//	type:live                    the Twitch stream type: live, rerun, premiere...
+//	title:speedrun               the stream title contains the text
+//	title~"(?i)speed ?run"       the stream title matches the regular expression
+//	time:18:00-23:30             the stream went live inside the window
+//	streamer:alpha               the streamer's alias or ID
+//	tz:Europe/Madrid             the zone for time terms, UTC by default
+//
+// Commas list alternatives, a leading - negates a term, and double quotes
+// keep spaces and commas in a value. Text matching ignores case, and a time
+// window whose end is before its start runs past midnight. An empty rule
+// matches every stream.
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Fields understood in rule terms.
+const (
+	FieldLanguage = "language"
+	FieldPlatform = "platform"
+	FieldTag      = "tag"
+	FieldType     = "type"
+	FieldTitle    = "title"
+	FieldTime     = "time"
+	FieldStreamer = "streamer"
+	FieldZone     = "tz"
+)
+
+// ErrSyntax is returned for rules that cannot be parsed.
+var ErrSyntax = errors.New("invalid rule")
+
+// Event is the stream a rule is evaluated against.
+type Event struct {
+	StreamerID string
+	Alias      string
+	Languages  []string
+	Platform   string
+	// StreamType is the Twitch stream type; YouTube streams are "live".
+	StreamType string
+	Title      string
+	Tags       []string
+	// At is when the stream went live, or now when unknown.
+	At time.Time
+}
+
+// Result reports whether one term of a rule held for an event.
+type Result struct {
+	Term    string
+	Matched bool
+}
+
+// Rule is a parsed rule. The nil Rule matches every event.
+type Rule struct {
+	src   string
+	terms []term
+	zone  *time.Location
+}
+
+type term struct {
+	src     string
+	field   string
+	negate  bool
+	values  []string
+	pattern *regexp.Regexp
+	windows []window
+}
+
+// window is a time of day range in minutes after midnight.
+type window struct {
+	from, to int
+}
+
+// Parse compiles a rule.
+func Parse(src string) (*Rule, error) {
+	r := &Rule{src: strings.TrimSpace(src), zone: time.UTC}
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	for _, tok := range tokens {
+		t, err := parseTerm(tok)
+		if err != nil {
+			return nil, err
+		}
+		if t.field == FieldZone {
+			zone, err := time.LoadLocation(t.values[0])
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: unknown time zone", ErrSyntax, tok.src)
+			}
+			r.zone = zone
+			continue
+		}
+		r.terms = append(r.terms, t)
+	}
+	return r, nil
+}
+
+// String returns the rule as written.
+func (r *Rule) String() string {
+	if r == nil {
+		return ""
+	}
+	return r.src
+}
+
+// Location returns the zone the rule's time terms are read in: its tz term,
+// or UTC.
+func (r *Rule) Location() *time.Location {
+	if r == nil || r.zone == nil {
+		return time.UTC
+	}
+	return r.zone
+}
+
+// Match reports whether the event satisfies every term of the rule.
+func (r *Rule) Match(e Event) bool {
+	if r == nil {
+		return true
+	}
+	for _, t := range r.terms {
+		if !r.holds(t, e) {
+			return false
+		}
+	}
+	return true
+}
+
+// Explain evaluates each term of the rule against the event.
+func (r *Rule) Explain(e Event) []Result {
+	if r == nil {
+		return nil
+	}
+	results := make([]Result, 0, len(r.terms))
+	for _, t := range r.terms {
+		results = append(results, Result{Term: t.src, Matched: r.holds(t, e)})
+	}
+	return results
+}
+
+func (r *Rule) holds(t term, e Event) bool {
+	var ok bool
+	switch t.field {
+	case FieldLanguage:
+		ok = anyEqual(t.values, e.Languages...)
+	case FieldPlatform:
+		ok = anyEqual(t.values, e.Platform)
+	case FieldTag:
+		ok = anyEqual(t.values, e.Tags...)
+	case FieldType:
+		ok = anyEqual(t.values, e.StreamType)
+	case FieldStreamer:
+		ok = anyEqual(t.values, e.StreamerID, e.Alias)
+	case FieldTitle:
+		if t.pattern != nil {
+			ok = t.pattern.MatchString(e.Title)
+			break
+		}
+		title := strings.ToLower(e.Title)
+		for _, v := range t.values {
+			if strings.Contains(title, strings.ToLower(v)) {
+				ok = true
+				break
+			}
+		}
+	case FieldTime:
+		at := e.At.In(r.zone)
+		minute := at.Hour()*60 + at.Minute()
+		for _, w := range t.windows {
+			if w.contains(minute) {
+				ok = true
+				break
+			}
+		}
+	}
+	return ok != t.negate
+}
+
+func (w window) contains(minute int) bool {
+	if w.from <= w.to {
+		return minute >= w.from && minute < w.to
+	}
+	return minute >= w.from || minute < w.to
+}
+
+func anyEqual(values []string, have ...string) bool {
+	for _, v := range values {
+		for _, h := range have {
+			if strings.EqualFold(strings.TrimSpace(h), v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// token is one whitespace-separated term split at unquoted commas, with
+// quotes removed.
+type token struct {
+	src   string
+	parts []string
+}
+
+func lex(src string) ([]token, error) {
+	var (
+		tokens []token
+		cur    token
+		part   strings.Builder
+		raw    strings.Builder
+		quoted bool
+		inTerm bool
+	)
+	flush := func() {
+		if !inTerm {
+			return
+		}
+		cur.parts = append(cur.parts, part.String())
+		cur.src = raw.String()
+		tokens = append(tokens, cur)
+		cur = token{}
+		part.Reset()
+		raw.Reset()
+		inTerm = false
+	}
+	runes := []rune(src)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(runes) && runes[i+1] == '"':
+			raw.WriteString(`\"`)
+			part.WriteRune('"')
+			i++
+			continue
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+			part.WriteRune(c)
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			flush()
+			continue
+		case c == ',':
+			cur.parts = append(cur.parts, part.String())
+			part.Reset()
+		default:
+			part.WriteRune(c)
+		}
+		raw.WriteRune(c)
+		inTerm = true
+	}
+	if quoted {
+		return nil, fmt.Errorf("%w: unterminated quote", ErrSyntax)
+	}
+	flush()
+	return tokens, nil
+}
+
+func parseTerm(tok token) (term, error) {
+	t := term{src: tok.src}
+	fail := func(msg string) (term, error) {
+		return term{}, fmt.Errorf("%w: %s: %s", ErrSyntax, tok.src, msg)
+	}
+	head := tok.parts[0]
+	if strings.HasPrefix(head, "-") {
+		t.negate = true
+		head = head[1:]
+	}
+	at := strings.IndexAny(head, ":~")
+	if at <= 0 {
+		return fail("expected field:value")
+	}
+	t.field = strings.ToLower(head[:at])
+	regex := head[at] == '~'
+	values := append([]string{head[at+1:]}, tok.parts[1:]...)
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			t.values = append(t.values, v)
+		}
+	}
+	if len(t.values) == 0 {
+		return fail("missing value")
+	}
+	if regex && t.field != FieldTitle {
+		return fail("only title supports ~")
+	}
+	switch t.field {
+	case FieldLanguage, FieldPlatform, FieldTag, FieldType, FieldStreamer:
+	case FieldTitle:
+		if regex {
+			pattern, err := regexp.Compile(strings.Join(t.values, ","))
+			if err != nil {
+				return fail(err.Error())
+			}
+			t.pattern = pattern
+		}
+	case FieldTime:
+		for _, v := range t.values {
+			w, err := parseWindow(v)
+			if err != nil {
+				return fail(err.Error())
+			}
+			t.windows = append(t.windows, w)
+		}
+	case FieldZone:
+		if t.negate || len(t.values) != 1 {
+			return fail("expected a single time zone")
+		}
+	default:
+		return fail("unknown field " + t.field)
+	}
+	return t, nil
+}
+
+func parseWindow(v string) (window, error) {
+	from, to, ok := strings.Cut(v, "-")
+	if !ok {
+		return window{}, errors.New("expected a window like 18:00-23:00")
+	}
+	var w window
+	var err error
+	if w.from, err = parseClock(from); err != nil {
+		return window{}, err
+	}
+	if w.to, err = parseClock(to); err != nil {
+		return window{}, err
+	}
+	if w.from == w.to {
+		return window{}, errors.New("window is empty")
+	}
+	return w, nil
+}
+
+func parseClock(v string) (int, error) {
+	v = strings.TrimSpace(v)
+	if v == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", v)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
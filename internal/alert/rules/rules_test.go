@@ -0,0 +1,103 @@
+package rules
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMatch(t *testing.T) {
+	event := Event{
+		StreamerID: "a1",
+		Alias:      "Alpha",
+		Languages:  []string{"English", "Spanish"},
+		Platform:   "twitch",
+		StreamType: "rerun",
+		Title:      "Cozy Sunday speed run, part 2",
+		Tags:       []string{"Cozy", "Speedrun"},
+		At:         time.Date(2025, 3, 2, 23, 30, 0, 0, time.UTC),
+	}
+	tests := []struct {
+		rule string
+		want bool
+	}{
+		{"", true},
+		{"language:spanish", true},
+		{"language:French,Portuguese", false},
+		{"platform:twitch language:Spanish", true},
+		{"platform:youtube language:Spanish", false},
+		{"-type:rerun", false},
+		{"type:live,rerun", true},
+		{"tag:cozy", true},
+		{"-tag:horror", true},
+		{`title:"speed run"`, true},
+		{`title~"(?i)^cozy .*part \d+$"`, true},
+		{`title~^cozy`, false},
+		{"time:18:00-23:59", true},
+		{"time:22:00-02:00", true},
+		{"time:08:00-12:00,23:00-24:00", true},
+		{"time:08:00-12:00", false},
+		{"time:22:00-23:00 tz:Europe/Madrid", false},
+		{"time:00:00-01:00 tz:Europe/Madrid", true},
+		{"streamer:alpha", true},
+		{"-streamer:a1", false},
+	}
+	for _, tt := range tests {
+		rule, err := Parse(tt.rule)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tt.rule, err)
+		}
+		if got := rule.Match(event); got != tt.want {
+			t.Errorf("%q: got %v, want %v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, src := range []string{
+		"spanish",
+		"colour:red",
+		"language:",
+		"platform~twitch",
+		`title~"("`,
+		"time:18:00",
+		"time:25:00-26:00",
+		"time:18:00-18:00",
+		"tz:Nowhere/Special",
+		`title:"unterminated`,
+	} {
+		if _, err := Parse(src); !errors.Is(err, ErrSyntax) {
+			t.Errorf("%q: expected a syntax error, got %v", src, err)
+		}
+	}
+}
+
+func TestExplain(t *testing.T) {
+	rule, err := Parse(`language:Spanish  -type:rerun tz:UTC`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	results := rule.Explain(Event{Languages: []string{"Spanish"}, StreamType: "rerun"})
+	want := []Result{{Term: "language:Spanish", Matched: true}, {Term: "-type:rerun", Matched: false}}
+	if len(results) != len(want) {
+		t.Fatalf("got %+v, want %+v", results, want)
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Fatalf("got %+v, want %+v", results, want)
+		}
+	}
+	if rule.String() != "language:Spanish  -type:rerun tz:UTC" {
+		t.Fatalf("unexpected source %q", rule.String())
+	}
+	var none *Rule
+	if !none.Match(Event{}) {
+		t.Fatal("a nil rule should match everything")
+	}
+	if none.Location() != time.UTC {
+		t.Fatal("a nil rule should read times in UTC")
+	}
+	if madrid, err := Parse("tz:Europe/Madrid"); err != nil || madrid.Location().String() != "Europe/Madrid" {
+		t.Fatalf("expected the tz term to set the location, got %v (%v)", madrid.Location(), err)
+	}
+}
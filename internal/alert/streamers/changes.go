@@ -0,0 +1,46 @@
+package streamers
+
+import (
+	"path/filepath"
+	"sync"
+)
+
+// writeSignals holds one writeSignal per cleaned file path so writes through
+// any Store for a path wake every watcher of it.
+var writeSignals sync.Map
+
+type writeSignal struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+func signalForPath(path string) *writeSignal {
+	if path == "" {
+		path = DefaultFilePath
+	}
+	cleaned := filepath.Clean(path)
+	if existing, ok := writeSignals.Load(cleaned); ok {
+		return existing.(*writeSignal)
+	}
+	actual, _ := writeSignals.LoadOrStore(cleaned, &writeSignal{ch: make(chan struct{})})
+	return actual.(*writeSignal)
+}
+
+// Changed returns a channel that is closed after the next write to the
+// store's file by this process. Callers wait on it and then call Changed
+// again for the following write; edits made by other processes are not seen.
+func (s *Store) Changed() <-chan struct{} {
+	sig := signalForPath(s.Path())
+	sig.mu.Lock()
+	defer sig.mu.Unlock()
+	return sig.ch
+}
+
+// notifyWrite wakes everyone waiting on Changed for path.
+func notifyWrite(path string) {
+	sig := signalForPath(path)
+	sig.mu.Lock()
+	close(sig.ch)
+	sig.ch = make(chan struct{})
+	sig.mu.Unlock()
+}
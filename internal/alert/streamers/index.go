@@ -0,0 +1,174 @@
+package streamers
+
+import (
+	"hash/fnv"
+	"strings"
+	"sync"
+)
+
+// ChannelRef locates the streamer that owns a channel in one site's store.
+type ChannelRef struct {
+	Site       string
+	StreamerID string
+	Store      *Store
+}
+
+// ChannelIndex maps YouTube channel IDs and Twitch broadcaster IDs to the
+// streamers that own them across every site's store, so notification
+// handlers avoid listing each roster per event. Entries are rebuilt on the
+// next lookup after any indexed store is written.
+type ChannelIndex struct {
+	stores func() map[string]*Store
+
+	mu      sync.Mutex
+	built   map[string]indexedStore
+	youtube map[string][]ChannelRef
+	twitch  map[string][]ChannelRef
+}
+
+type indexedStore struct {
+	store      *Store
+	generation uint64
+}
+
+// NewChannelIndex returns an index over the stores returned by stores, keyed
+// by site.
+func NewChannelIndex(stores func() map[string]*Store) *ChannelIndex {
+	return &ChannelIndex{stores: stores}
+}
+
+// YouTube returns the streamers owning the YouTube channel ID.
+func (x *ChannelIndex) YouTube(channelID string) []ChannelRef {
+	key := youtubeIndexKey(channelID)
+	if key == "" {
+		return nil
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.refreshLocked()
+	return append([]ChannelRef(nil), x.youtube[key]...)
+}
+
+// Twitch returns the streamers owning the Twitch broadcaster ID.
+func (x *ChannelIndex) Twitch(broadcasterID string) []ChannelRef {
+	key := twitchIndexKey(broadcasterID)
+	if key == "" {
+		return nil
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.refreshLocked()
+	return append([]ChannelRef(nil), x.twitch[key]...)
+}
+
+// refreshLocked rebuilds the index when the set of stores changed or one of
+// them was written since the last build. The check costs one comparison per
+// store, independent of roster size.
+func (x *ChannelIndex) refreshLocked() {
+	var stores map[string]*Store
+	if x.stores != nil {
+		stores = x.stores()
+	}
+	if !x.staleLocked(stores) {
+		return
+	}
+	built := make(map[string]indexedStore, len(stores))
+	youtube := make(map[string][]ChannelRef)
+	twitch := make(map[string][]ChannelRef)
+	for site, store := range stores {
+		if store == nil {
+			continue
+		}
+		// Read the generation first so a concurrent write forces another rebuild.
+		generation := store.ChannelGeneration()
+		records, err := store.Shared().List()
+		if err != nil {
+			continue
+		}
+		built[site] = indexedStore{store: store, generation: generation}
+		for _, record := range records {
+			ref := ChannelRef{Site: site, StreamerID: record.Streamer.ID, Store: store}
+			if key := youtubeIndexKey(recordChannelID(record)); key != "" {
+				youtube[key] = append(youtube[key], ref)
+			}
+			if tw := record.Platforms.Twitch; tw != nil {
+				if key := twitchIndexKey(tw.BroadcasterID); key != "" {
+					twitch[key] = append(twitch[key], ref)
+				}
+			}
+		}
+	}
+	x.built = built
+	x.youtube = youtube
+	x.twitch = twitch
+}
+
+func (x *ChannelIndex) staleLocked(stores map[string]*Store) bool {
+	if x.built == nil || len(stores) != len(x.built) {
+		return true
+	}
+	for site, store := range stores {
+		indexed, ok := x.built[site]
+		if !ok || indexed.store != store || indexed.generation != store.ChannelGeneration() {
+			return true
+		}
+	}
+	return false
+}
+
+// ChannelGeneration returns a counter that advances whenever a write changes
+// the channel or broadcaster IDs held by the store.
+func (s *Store) ChannelGeneration() uint64 {
+	if s == nil {
+		return 0
+	}
+	return s.Shared().channelGen.Load()
+}
+
+// noteChannels advances the channel generation when the written records own a
+// different set of channels. Callers must hold the store lock.
+func (s *Store) noteChannels(records []Record) {
+	owner := s.Shared()
+	sig := channelSignature(records)
+	if sig == owner.channelSig && owner.channelGen.Load() != 0 {
+		return
+	}
+	owner.channelSig = sig
+	owner.channelGen.Add(1)
+}
+
+func channelSignature(records []Record) uint64 {
+	h := fnv.New64a()
+	for _, record := range records {
+		h.Write([]byte(record.Streamer.ID))
+		h.Write([]byte{0})
+		h.Write([]byte(youtubeIndexKey(recordChannelID(record))))
+		h.Write([]byte{0})
+		if tw := record.Platforms.Twitch; tw != nil {
+			h.Write([]byte(twitchIndexKey(tw.BroadcasterID)))
+		}
+		h.Write([]byte{1})
+	}
+	return h.Sum64()
+}
+
+func recordChannelID(record Record) string {
+	yt := record.Platforms.YouTube
+	if yt == nil {
+		return ""
+	}
+	if id := strings.TrimSpace(yt.ChannelID); id != "" {
+		return id
+	}
+	return extractChannelIDFromTopic(yt.Topic)
+}
+
+// youtubeIndexKey mirrors channelMatches: case-insensitive with the UC prefix
+// ignored.
+func youtubeIndexKey(channelID string) string {
+	return trimChannelPrefix(channelID)
+}
+
+func twitchIndexKey(broadcasterID string) string {
+	return strings.ToLower(strings.TrimSpace(broadcasterID))
+}
@@ -0,0 +1,171 @@
+package streamers
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestChannelIndexRoutesAcrossStores(t *testing.T) {
+	dir := t.TempDir()
+	sharpen := NewStore(filepath.Join(dir, "sharpen", "streamers.json"))
+	synth := NewStore(filepath.Join(dir, "synth", "streamers.json"))
+	if _, err := sharpen.Append(Record{
+		Streamer:  Streamer{ID: "blades", Alias: "Blades"},
+		Platforms: Platforms{YouTube: &YouTubePlatform{ChannelID: "UCblades"}},
+	}); err != nil {
+		t.Fatalf("append sharpen: %v", err)
+	}
+	if _, err := synth.Append(Record{
+		Streamer:  Streamer{ID: "neon", Alias: "Neon"},
+		Platforms: Platforms{Twitch: &TwitchPlatform{BroadcasterID: "1234"}},
+	}); err != nil {
+		t.Fatalf("append synth: %v", err)
+	}
+	index := NewChannelIndex(func() map[string]*Store {
+		return map[string]*Store{"sharpen-live": sharpen, "synth-wave": synth}
+	})
+
+	tests := []struct {
+		name   string
+		lookup func() []ChannelRef
+		site   string
+		id     string
+	}{
+		{name: "youtube channel", lookup: func() []ChannelRef { return index.YouTube("UCblades") }, site: "sharpen-live", id: "blades"},
+		{name: "youtube without prefix", lookup: func() []ChannelRef { return index.YouTube("BLADES") }, site: "sharpen-live", id: "blades"},
+		{name: "twitch broadcaster", lookup: func() []ChannelRef { return index.Twitch("1234") }, site: "synth-wave", id: "neon"},
+		{name: "unknown channel", lookup: func() []ChannelRef { return index.YouTube("UCmissing") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refs := tt.lookup()
+			if tt.id == "" {
+				if len(refs) != 0 {
+					t.Fatalf("expected no refs, got %+v", refs)
+				}
+				return
+			}
+			if len(refs) != 1 || refs[0].Site != tt.site || refs[0].StreamerID != tt.id {
+				t.Fatalf("expected %s/%s, got %+v", tt.site, tt.id, refs)
+			}
+		})
+	}
+}
+
+func TestChannelIndexInvalidatesOnChannelWrites(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "streamers.json"))
+	index := NewChannelIndex(func() map[string]*Store { return map[string]*Store{"base": store} })
+
+	if refs := index.Twitch("42"); len(refs) != 0 {
+		t.Fatalf("expected empty index, got %+v", refs)
+	}
+	if _, err := store.Append(Record{
+		Streamer:  Streamer{ID: "blades", Alias: "Blades"},
+		Platforms: Platforms{Twitch: &TwitchPlatform{BroadcasterID: "42"}},
+	}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if refs := index.Twitch("42"); len(refs) != 1 {
+		t.Fatalf("expected appended streamer indexed, got %+v", refs)
+	}
+
+	generation := store.ChannelGeneration()
+	if _, err := store.SetTwitchLive("42", "stream-1", time.Now()); err != nil {
+		t.Fatalf("set live: %v", err)
+	}
+	if store.ChannelGeneration() != generation {
+		t.Fatalf("status-only write should not invalidate the index")
+	}
+
+	if _, err := store.UpdateTwitchPlatform("blades", &TwitchPlatform{BroadcasterID: "43"}); err != nil {
+		t.Fatalf("update twitch platform: %v", err)
+	}
+	if refs := index.Twitch("42"); len(refs) != 0 {
+		t.Fatalf("expected old broadcaster dropped, got %+v", refs)
+	}
+	if refs := index.Twitch("43"); len(refs) != 1 {
+		t.Fatalf("expected new broadcaster indexed, got %+v", refs)
+	}
+
+	if err := store.Delete("blades"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if refs := index.Twitch("43"); len(refs) != 0 {
+		t.Fatalf("expected deleted streamer dropped, got %+v", refs)
+	}
+}
+
+// The index lookups should stay flat as the roster grows, while the linear
+// scan they replace grows with it.
+func BenchmarkChannelIndexYouTube(b *testing.B) {
+	for _, size := range []int{10, 1000, 10000} {
+		store := benchmarkStore(b, size)
+		index := NewChannelIndex(func() map[string]*Store { return map[string]*Store{"base": store} })
+		target := fmt.Sprintf("UCchannel%d", size-1)
+		index.YouTube(target)
+		b.Run(fmt.Sprintf("index/%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if len(index.YouTube(target)) != 1 {
+					b.Fatal("channel not indexed")
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("scan/%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				records, err := store.List()
+				if err != nil {
+					b.Fatal(err)
+				}
+				found := false
+				for _, record := range records {
+					if channelMatches(record.Platforms.YouTube, target) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					b.Fatal("channel not found")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkChannelIndexTwitch(b *testing.B) {
+	for _, size := range []int{10, 1000, 10000} {
+		store := benchmarkStore(b, size)
+		index := NewChannelIndex(func() map[string]*Store { return map[string]*Store{"base": store} })
+		target := fmt.Sprintf("%d", size-1)
+		index.Twitch(target)
+		b.Run(fmt.Sprintf("index/%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if len(index.Twitch(target)) != 1 {
+					b.Fatal("broadcaster not indexed")
+				}
+			}
+		})
+	}
+}
+
+func benchmarkStore(b *testing.B, size int) *Store {
+	b.Helper()
+	store := NewStore(filepath.Join(b.TempDir(), "streamers.json"))
+	err := store.UpdateFile(func(file *File) error {
+		for i := 0; i < size; i++ {
+			file.Records = append(file.Records, Record{
+				Streamer: Streamer{ID: fmt.Sprintf("streamer-%d", i), Alias: fmt.Sprintf("Streamer %d", i)},
+				Platforms: Platforms{
+					YouTube: &YouTubePlatform{ChannelID: fmt.Sprintf("UCchannel%d", i)},
+					Twitch:  &TwitchPlatform{BroadcasterID: fmt.Sprintf("%d", i)},
+				},
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		b.Fatalf("seed roster: %v", err)
+	}
+	return store
+}
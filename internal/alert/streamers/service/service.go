@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/mail"
 	"github.com/Its-donkey/Sharpen-live/internal/alert/platforms/youtube/subscriptions"
 	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
 	"github.com/Its-donkey/Sharpen-live/internal/alert/submissions"
@@ -43,6 +44,8 @@ type CreateRequest struct {
 	Languages   []string
 	PlatformURL string // Deprecated: use Platforms instead
 	Platforms   map[string]submissions.PlatformInfo
+	// ContactEmail optionally receives confirmation and decision emails.
+	ContactEmail string
 }
 
 // CreateResult captures the stored submission returned by Create.
@@ -56,6 +59,10 @@ type UpdateRequest struct {
 	Alias       *string
 	Description *string
 	Languages   *[]string
+	Featured    *bool
+	// SiteOnly keeps Description as an override for the store's site when the
+	// roster is shared.
+	SiteOnly bool
 }
 
 // DeleteRequest describes the streamer deletion payload.
@@ -98,15 +105,22 @@ func (s *Service) Create(ctx context.Context, req CreateRequest) (CreateResult,
 	if err != nil {
 		return CreateResult{}, fmt.Errorf("%w: %v", ErrValidation, err)
 	}
+	var contact string
+	if strings.TrimSpace(req.ContactEmail) != "" {
+		if contact, err = mail.ParseAddress(req.ContactEmail); err != nil {
+			return CreateResult{}, fmt.Errorf("%w: streamer.contactEmail must be a plain email address", ErrValidation)
+		}
+	}
 	if err := s.ensureUniqueAlias(alias, ""); err != nil {
 		return CreateResult{}, err
 	}
 	submission := submissions.Submission{
-		Alias:       alias,
-		Description: strings.TrimSpace(req.Description),
-		Languages:   langs,
-		PlatformURL: strings.TrimSpace(req.PlatformURL),
-		Platforms:   req.Platforms,
+		Alias:        alias,
+		Description:  strings.TrimSpace(req.Description),
+		Languages:    langs,
+		PlatformURL:  strings.TrimSpace(req.PlatformURL),
+		Platforms:    req.Platforms,
+		ContactEmail: contact,
 	}
 	saved, err := s.submissions.Append(submission)
 	if err != nil {
@@ -151,6 +165,11 @@ func (s *Service) Update(ctx context.Context, req UpdateRequest) (streamers.Reco
 		*update.Languages = langs
 		hasUpdate = true
 	}
+	if req.Featured != nil {
+		update.Featured = req.Featured
+		hasUpdate = true
+	}
+	update.SiteOnly = req.SiteOnly
 	if !hasUpdate {
 		return streamers.Record{}, fmt.Errorf("%w: at least one streamer field must be provided", ErrValidation)
 	}
@@ -170,7 +189,9 @@ func (s *Service) Delete(ctx context.Context, req DeleteRequest) error {
 	if err != nil {
 		return err
 	}
-	if record.Platforms.YouTube != nil {
+	// On a shared roster the subscription stays while another site lists the streamer.
+	sharedElsewhere := s.streamers.Site() != "" && len(record.Sites) > 1
+	if record.Platforms.YouTube != nil && !sharedElsewhere {
 		if err := s.unsubscribe(ctx, record); err != nil {
 			return err
 		}
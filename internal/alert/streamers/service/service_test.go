@@ -28,6 +28,23 @@ func TestServiceCreateQueuesSubmission(t *testing.T) {
 	}
 }
 
+func TestServiceCreateStoresContactEmail(t *testing.T) {
+	dir := t.TempDir()
+	subStore := submissions.NewStore(filepath.Join(dir, "submissions.json"))
+	svc := New(Options{Streamers: streamers.NewStore(filepath.Join(dir, "streamers.json")), Submissions: subStore})
+
+	if _, err := svc.Create(t.Context(), CreateRequest{Alias: "Test", Languages: []string{"English"}, ContactEmail: "Me <me@example.com>"}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected a validation error for a display-name address, got %v", err)
+	}
+	result, err := svc.Create(t.Context(), CreateRequest{Alias: "Test", Languages: []string{"English"}, ContactEmail: " Me@Example.com "})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if result.Submission.ContactEmail != "me@example.com" {
+		t.Fatalf("expected the normalised contact email, got %q", result.Submission.ContactEmail)
+	}
+}
+
 func TestServiceCreateRejectsDuplicates(t *testing.T) {
 	dir := t.TempDir()
 	streamStore := streamers.NewStore(filepath.Join(dir, "streamers.json"))
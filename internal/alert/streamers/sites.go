@@ -0,0 +1,230 @@
+package streamers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ForSite returns a view of the store scoped to a single site of a shared
+// roster. The view shares the parent's file and lock; reads only see records
+// visible on site and writes tag new records with it. Live-status updates are
+// not scoped so a channel is tracked once regardless of how many sites list it.
+func (s *Store) ForSite(site string) *Store {
+	if s == nil {
+		return nil
+	}
+	root := s
+	if s.root != nil {
+		root = s.root
+	}
+	site = strings.TrimSpace(site)
+	if site == "" {
+		return root
+	}
+	return &Store{path: root.path, root: root, site: site}
+}
+
+// Site returns the site a view is scoped to, or "" for an unscoped store.
+func (s *Store) Site() string {
+	if s == nil {
+		return ""
+	}
+	return s.site
+}
+
+// Shared returns the unscoped store backing a site view.
+func (s *Store) Shared() *Store {
+	if s == nil || s.root == nil {
+		return s
+	}
+	return s.root
+}
+
+// SharedStore returns the process-wide store for path so every site reading a
+// shared roster serialises writes through the same lock.
+func SharedStore(path string) *Store {
+	return storeForPath(path)
+}
+
+// VisibleOn reports whether the record appears on site.
+func (r Record) VisibleOn(site string) bool {
+	if len(r.Sites) == 0 {
+		return true
+	}
+	for _, existing := range r.Sites {
+		if strings.EqualFold(existing, site) {
+			return true
+		}
+	}
+	return false
+}
+
+// ForSite returns a copy of the record with the site's overrides applied.
+func (r Record) ForSite(site string) Record {
+	override, ok := r.Overrides[site]
+	if !ok {
+		return r
+	}
+	if override.Description != nil {
+		r.Streamer.Description = *override.Description
+	}
+	if override.Featured != nil {
+		r.Streamer.Featured = *override.Featured
+	}
+	return r
+}
+
+// CopyToSite makes the streamer visible on site as well as its current sites.
+// Records without a site list are already visible everywhere and are left
+// untouched.
+func (s *Store) CopyToSite(streamerID, site string) (Record, error) {
+	return s.updateSites(streamerID, func(record *Record) error {
+		if len(record.Sites) == 0 {
+			return nil
+		}
+		record.Sites = addSite(record.Sites, site)
+		return nil
+	}, site)
+}
+
+// MoveToSite moves the streamer from one site to another. Records without a
+// site list become visible on the target site only.
+func (s *Store) MoveToSite(streamerID, from, to string) (Record, error) {
+	return s.updateSites(streamerID, func(record *Record) error {
+		if len(record.Sites) == 0 {
+			record.Sites = []string{to}
+			return nil
+		}
+		if !record.VisibleOn(from) {
+			return fmt.Errorf("%w: %s is not listed on %s", ErrStreamerNotFound, streamerID, from)
+		}
+		record.Sites = addSite(removeSite(record.Sites, from), to)
+		delete(record.Overrides, from)
+		if len(record.Overrides) == 0 {
+			record.Overrides = nil
+		}
+		return nil
+	}, to)
+}
+
+func (s *Store) updateSites(streamerID string, updateFn func(*Record) error, site string) (Record, error) {
+	if s == nil {
+		return Record{}, errors.New("streamers store is nil")
+	}
+	streamerID = strings.TrimSpace(streamerID)
+	if streamerID == "" {
+		return Record{}, errors.New("streamer id is required")
+	}
+	if strings.TrimSpace(site) == "" {
+		return Record{}, errors.New("site is required")
+	}
+	var updated Record
+	s.mutex().Lock()
+	defer s.mutex().Unlock()
+	err := s.updateFileLocked(func(file *File) error {
+		for i := range file.Records {
+			if !strings.EqualFold(file.Records[i].Streamer.ID, streamerID) {
+				continue
+			}
+			if err := updateFn(&file.Records[i]); err != nil {
+				return err
+			}
+			file.Records[i].UpdatedAt = time.Now().UTC()
+			updated = file.Records[i]
+			return nil
+		}
+		return fmt.Errorf("%w: %s", ErrStreamerNotFound, streamerID)
+	})
+	return updated, err
+}
+
+// FindByChannel returns the record that owns the YouTube channel ID or the
+// Twitch username, regardless of the sites it appears on. It lets callers reuse
+// an existing subscription rather than creating a second one for the channel.
+func (s *Store) FindByChannel(youtubeChannelID, twitchUsername string) (Record, error) {
+	if s == nil {
+		return Record{}, errors.New("streamers store is nil")
+	}
+	youtubeChannelID = strings.TrimSpace(youtubeChannelID)
+	twitchUsername = strings.TrimSpace(twitchUsername)
+	if youtubeChannelID == "" && twitchUsername == "" {
+		return Record{}, errors.New("channel id or twitch username is required")
+	}
+	s.mutex().Lock()
+	defer s.mutex().Unlock()
+	fileData, err := s.readFileLocked()
+	if err != nil {
+		return Record{}, err
+	}
+	for _, record := range fileData.Records {
+		if youtubeChannelID != "" && channelMatches(record.Platforms.YouTube, youtubeChannelID) {
+			return record, nil
+		}
+		if tw := record.Platforms.Twitch; twitchUsername != "" && tw != nil && strings.EqualFold(strings.TrimSpace(tw.Username), twitchUsername) {
+			return record, nil
+		}
+	}
+	return Record{}, fmt.Errorf("%w: channel %s%s", ErrStreamerNotFound, youtubeChannelID, twitchUsername)
+}
+
+func filterSite(records []Record, site string) []Record {
+	out := make([]Record, 0, len(records))
+	for _, record := range records {
+		if record.VisibleOn(site) {
+			out = append(out, record.ForSite(site))
+		}
+	}
+	return out
+}
+
+// applySiteFields writes description/featured edits made through a site view.
+// Featured is always per site; the description is shared unless SiteOnly is
+// set, in which case it becomes an override for the view's site.
+func applySiteFields(record *Record, site string, fields UpdateFields) {
+	override := record.Overrides[site]
+	if fields.Description != nil {
+		if fields.SiteOnly {
+			description := *fields.Description
+			override.Description = &description
+		} else {
+			record.Streamer.Description = *fields.Description
+			override.Description = nil
+		}
+	}
+	if fields.Featured != nil {
+		featured := *fields.Featured
+		override.Featured = &featured
+	}
+	if override.Description == nil && override.Featured == nil {
+		delete(record.Overrides, site)
+		if len(record.Overrides) == 0 {
+			record.Overrides = nil
+		}
+		return
+	}
+	if record.Overrides == nil {
+		record.Overrides = make(map[string]SiteOverride)
+	}
+	record.Overrides[site] = override
+}
+
+func addSite(sites []string, site string) []string {
+	for _, existing := range sites {
+		if strings.EqualFold(existing, site) {
+			return sites
+		}
+	}
+	return append(sites, site)
+}
+
+func removeSite(sites []string, site string) []string {
+	out := make([]string, 0, len(sites))
+	for _, existing := range sites {
+		if !strings.EqualFold(existing, site) {
+			out = append(out, existing)
+		}
+	}
+	return out
+}
@@ -0,0 +1,172 @@
+package streamers
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestSiteViewsFilterAndTagRecords(t *testing.T) {
+	shared := NewStore(filepath.Join(t.TempDir(), "streamers.json"))
+	sharpen := shared.ForSite("sharpen-live")
+	synth := shared.ForSite("synth-wave")
+
+	if _, err := shared.Append(Record{Streamer: Streamer{ID: "everywhere", Alias: "Everywhere"}}); err != nil {
+		t.Fatalf("append global: %v", err)
+	}
+	added, err := sharpen.Append(Record{Streamer: Streamer{ID: "blades", Alias: "Blades"}})
+	if err != nil {
+		t.Fatalf("append via view: %v", err)
+	}
+	if len(added.Sites) != 1 || added.Sites[0] != "sharpen-live" {
+		t.Fatalf("expected record tagged with view site, got %v", added.Sites)
+	}
+
+	tests := []struct {
+		name  string
+		store *Store
+		want  []string
+	}{
+		{name: "unscoped sees all", store: shared, want: []string{"everywhere", "blades"}},
+		{name: "owning site", store: sharpen, want: []string{"everywhere", "blades"}},
+		{name: "other site", store: synth, want: []string{"everywhere"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := tt.store.List()
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			if len(records) != len(tt.want) {
+				t.Fatalf("expected %d records, got %d", len(tt.want), len(records))
+			}
+			for i, id := range tt.want {
+				if records[i].Streamer.ID != id {
+					t.Fatalf("record %d: expected %s, got %s", i, id, records[i].Streamer.ID)
+				}
+			}
+		})
+	}
+
+	if _, err := synth.Get("blades"); !errors.Is(err, ErrStreamerNotFound) {
+		t.Fatalf("expected hidden record to be not found, got %v", err)
+	}
+}
+
+func TestSiteViewOverrides(t *testing.T) {
+	shared := NewStore(filepath.Join(t.TempDir(), "streamers.json"))
+	if _, err := shared.Append(Record{
+		Streamer: Streamer{ID: "neon", Alias: "Neon", Description: "Shared"},
+		Sites:    []string{"sharpen-live", "synth-wave"},
+	}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	synth := shared.ForSite("synth-wave")
+
+	desc := "Synth only"
+	featured := true
+	if _, err := synth.Update(UpdateFields{StreamerID: "neon", Description: &desc, Featured: &featured, SiteOnly: true}); err != nil {
+		t.Fatalf("update override: %v", err)
+	}
+
+	got, err := synth.Get("neon")
+	if err != nil {
+		t.Fatalf("get synth: %v", err)
+	}
+	if got.Streamer.Description != desc || !got.Streamer.Featured {
+		t.Fatalf("expected overrides applied, got %+v", got.Streamer)
+	}
+	other, err := shared.ForSite("sharpen-live").Get("neon")
+	if err != nil {
+		t.Fatalf("get sharpen: %v", err)
+	}
+	if other.Streamer.Description != "Shared" || other.Streamer.Featured {
+		t.Fatalf("expected shared values on other site, got %+v", other.Streamer)
+	}
+
+	shared2 := "Shared again"
+	if _, err := synth.Update(UpdateFields{StreamerID: "neon", Description: &shared2}); err != nil {
+		t.Fatalf("update shared: %v", err)
+	}
+	raw, err := shared.Get("neon")
+	if err != nil {
+		t.Fatalf("get raw: %v", err)
+	}
+	if raw.Streamer.Description != shared2 {
+		t.Fatalf("expected shared description updated, got %q", raw.Streamer.Description)
+	}
+	if o := raw.Overrides["synth-wave"]; o.Description != nil || o.Featured == nil {
+		t.Fatalf("expected description override cleared and featured kept, got %+v", o)
+	}
+}
+
+func TestSiteViewDeleteOnlyDropsSite(t *testing.T) {
+	shared := NewStore(filepath.Join(t.TempDir(), "streamers.json"))
+	if _, err := shared.Append(Record{Streamer: Streamer{ID: "both", Alias: "Both"}, Sites: []string{"a", "b"}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if err := shared.ForSite("a").Delete("both"); err != nil {
+		t.Fatalf("delete from a: %v", err)
+	}
+	record, err := shared.Get("both")
+	if err != nil {
+		t.Fatalf("expected record to remain: %v", err)
+	}
+	if len(record.Sites) != 1 || record.Sites[0] != "b" {
+		t.Fatalf("expected only site b, got %v", record.Sites)
+	}
+	if err := shared.ForSite("b").Delete("both"); err != nil {
+		t.Fatalf("delete from b: %v", err)
+	}
+	if _, err := shared.Get("both"); !errors.Is(err, ErrStreamerNotFound) {
+		t.Fatalf("expected record removed with its last site, got %v", err)
+	}
+}
+
+func TestCopyAndMoveToSite(t *testing.T) {
+	shared := NewStore(filepath.Join(t.TempDir(), "streamers.json"))
+	if _, err := shared.Append(Record{Streamer: Streamer{ID: "mover", Alias: "Mover"}, Sites: []string{"a"}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	copied, err := shared.CopyToSite("mover", "b")
+	if err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	if !copied.VisibleOn("a") || !copied.VisibleOn("b") {
+		t.Fatalf("expected record on both sites, got %v", copied.Sites)
+	}
+	moved, err := shared.MoveToSite("mover", "a", "c")
+	if err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if moved.VisibleOn("a") || !moved.VisibleOn("b") || !moved.VisibleOn("c") {
+		t.Fatalf("unexpected sites after move: %v", moved.Sites)
+	}
+	if _, err := shared.MoveToSite("mover", "a", "d"); !errors.Is(err, ErrStreamerNotFound) {
+		t.Fatalf("expected not found moving from unlisted site, got %v", err)
+	}
+}
+
+func TestFindByChannel(t *testing.T) {
+	shared := NewStore(filepath.Join(t.TempDir(), "streamers.json"))
+	if _, err := shared.Append(Record{
+		Streamer: Streamer{ID: "chan", Alias: "Chan"},
+		Platforms: Platforms{
+			YouTube: &YouTubePlatform{ChannelID: "UC123"},
+			Twitch:  &TwitchPlatform{Username: "Chan"},
+		},
+		Sites: []string{"a"},
+	}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	view := shared.ForSite("b")
+	if _, err := view.FindByChannel("UC123", ""); err != nil {
+		t.Fatalf("expected youtube match across sites: %v", err)
+	}
+	if _, err := view.FindByChannel("", "chan"); err != nil {
+		t.Fatalf("expected twitch match: %v", err)
+	}
+	if _, err := view.FindByChannel("UC999", ""); !errors.Is(err, ErrStreamerNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -30,8 +31,20 @@ type Record struct {
 	Streamer  Streamer  `json:"streamer"`
 	Platforms Platforms `json:"platforms"`
 	Status    *Status   `json:"status,omitempty"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	// Sites lists the site keys the record appears on when the roster is
+	// shared between sites. An empty list means every site.
+	Sites []string `json:"sites,omitempty"`
+	// Overrides holds per-site presentation changes keyed by site.
+	Overrides map[string]SiteOverride `json:"siteOverrides,omitempty"`
+	CreatedAt time.Time               `json:"createdAt"`
+	UpdatedAt time.Time               `json:"updatedAt"`
+}
+
+// SiteOverride replaces shared streamer fields on a single site. Nil fields
+// fall back to the shared value.
+type SiteOverride struct {
+	Description *string `json:"description,omitempty"`
+	Featured    *bool   `json:"featured,omitempty"`
 }
 
 // Streamer captures personal information for a streamer.
@@ -45,6 +58,7 @@ type Streamer struct {
 	City        string   `json:"city,omitempty"`
 	Country     string   `json:"country,omitempty"`
 	Languages   []string `json:"languages,omitempty"`
+	Featured    bool     `json:"featured,omitempty"`
 }
 
 // Platforms groups platform-specific configuration.
@@ -68,6 +82,10 @@ type YouTubeStatus struct {
 	Live      bool      `json:"live"`
 	VideoID   string    `json:"videoId,omitempty"`
 	StartedAt time.Time `json:"startedAt,omitempty"`
+	Title     string    `json:"title,omitempty"`
+	// EndedAt is when the last live stream ended; it is kept while the
+	// channel is offline.
+	EndedAt time.Time `json:"endedAt,omitempty"`
 }
 
 // YouTubePlatform stores YouTube-specific metadata and WebSub subscription details.
@@ -118,6 +136,11 @@ type TwitchStatus struct {
 	Live      bool      `json:"live"`
 	StreamID  string    `json:"streamId,omitempty"`
 	StartedAt time.Time `json:"startedAt,omitempty"`
+	// Type is the EventSub stream type: live, rerun, premiere, playlist or
+	// watch_party.
+	Type  string   `json:"type,omitempty"`
+	Title string   `json:"title,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
 }
 
 var (
@@ -129,10 +152,19 @@ var (
 	ErrDuplicateAlias = errors.New("streamer alias already exists")
 )
 
-// Store persists streamer records to a JSON file with per-path locking.
+// Store persists streamer records to a JSON file with per-path locking. A
+// store returned by ForSite is a view over a shared roster: it shares the
+// parent's lock and only exposes records visible on its site.
 type Store struct {
 	path string
 	mu   sync.Mutex
+	root *Store
+	site string
+
+	// channelGen advances whenever a write changes which streamer owns a
+	// channel; ChannelIndex uses it to detect stale entries.
+	channelGen atomic.Uint64
+	channelSig uint64
 }
 
 var storeCache sync.Map
@@ -166,6 +198,15 @@ func storeForPath(path string) *Store {
 	return actual.(*Store)
 }
 
+// mutex returns the lock guarding the backing file; site views share the
+// lock of the store they were derived from.
+func (s *Store) mutex() *sync.Mutex {
+	if s.root != nil {
+		return &s.root.mu
+	}
+	return &s.mu
+}
+
 func (s *Store) ensureDir() error {
 	if s == nil {
 		return errors.New("streamers store is nil")
@@ -206,6 +247,8 @@ func (s *Store) writeFileLocked(file File) error {
 	if err := os.WriteFile(s.path, encoded, 0o644); err != nil {
 		return fmt.Errorf("write streamers file: %w", err)
 	}
+	s.noteChannels(file.Records)
+	notifyWrite(s.path)
 	return nil
 }
 
@@ -226,6 +269,10 @@ type UpdateFields struct {
 	Alias       *string
 	Description *string
 	Languages   *[]string
+	Featured    *bool
+	// SiteOnly stores Description as an override for the store's site rather
+	// than the shared description. It is ignored by unscoped stores.
+	SiteOnly bool
 }
 
 // Append adds a new streamer record to disk and returns a copy with timestamps populated.
@@ -237,8 +284,8 @@ func (s *Store) Append(record Record) (Record, error) {
 		return Record{}, fmt.Errorf("create streamers dir: %w", err)
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mutex().Lock()
+	defer s.mutex().Unlock()
 
 	fileData, err := s.readFileLocked()
 	if err != nil {
@@ -251,6 +298,9 @@ func (s *Store) Append(record Record) (Record, error) {
 	if record.Streamer.ID == "" {
 		record.Streamer.ID = GenerateID()
 	}
+	if s.site != "" && len(record.Sites) == 0 {
+		record.Sites = []string{s.site}
+	}
 	now := time.Now().UTC()
 	record.CreatedAt = now
 	record.UpdatedAt = now
@@ -277,18 +327,22 @@ func Append(path string, record Record) (Record, error) {
 	return storeForPath(path).Append(record)
 }
 
-// List loads all streamer records from disk.
+// List loads all streamer records from disk. Site views return only the
+// records visible on their site, with that site's overrides applied.
 func (s *Store) List() ([]Record, error) {
 	if s == nil {
 		return nil, errors.New("streamers store is nil")
 	}
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mutex().Lock()
+	defer s.mutex().Unlock()
 
 	fileData, err := s.readFileLocked()
 	if err != nil {
 		return nil, err
 	}
+	if s.site != "" {
+		return filterSite(fileData.Records, s.site), nil
+	}
 	records := make([]Record, len(fileData.Records))
 	copy(records, fileData.Records)
 	return records, nil
@@ -316,8 +370,8 @@ func (s *Store) UpdateYouTubeLiveStatus(channelID string, liveStatus YouTubeLive
 		return Record{}, errors.New("channel id is required")
 	}
 	var updated Record
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mutex().Lock()
+	defer s.mutex().Unlock()
 	err := s.updateFileLocked(func(file *File) error {
 		for i := range file.Records {
 			yt := file.Records[i].Platforms.YouTube
@@ -349,6 +403,7 @@ func applyYouTubeStatus(record *Record, liveStatus YouTubeLiveStatus) {
 	if record.Status.YouTube == nil {
 		record.Status.YouTube = &YouTubeStatus{}
 	}
+	wasLive := record.Status.YouTube.Live
 	record.Status.YouTube.Live = liveStatus.Live
 	record.Status.YouTube.VideoID = liveStatus.VideoID
 	if liveStatus.StartedAt.IsZero() {
@@ -363,10 +418,17 @@ func applyYouTubeStatus(record *Record, liveStatus YouTubeLiveStatus) {
 		record.Status.Platforms = removePlatform(record.Status.Platforms, platformYouTube)
 	}
 	record.Status.Live = liveStatus.Live
+	switch {
+	case liveStatus.Live:
+		record.Status.YouTube.EndedAt = time.Time{}
+	case wasLive:
+		record.Status.YouTube.EndedAt = time.Now().UTC()
+	}
 	if !liveStatus.Live && record.Status.YouTube != nil {
 		record.Status.YouTube.Live = false
 		record.Status.YouTube.VideoID = ""
 		record.Status.YouTube.StartedAt = time.Time{}
+		record.Status.YouTube.Title = ""
 	}
 	if len(record.Status.Platforms) == 0 && !record.Status.Live {
 		record.Status.Platforms = nil
@@ -413,29 +475,42 @@ func (s *Store) Update(fields UpdateFields) (Record, error) {
 	if id == "" {
 		return Record{}, errors.New("streamer id is required")
 	}
-	if fields.Alias == nil && fields.Description == nil && fields.Languages == nil {
+	if fields.Alias == nil && fields.Description == nil && fields.Languages == nil && fields.Featured == nil {
 		return Record{}, errors.New("no fields provided to update")
 	}
 
 	var updated Record
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mutex().Lock()
+	defer s.mutex().Unlock()
 	err := s.updateFileLocked(func(file *File) error {
 		for i := range file.Records {
 			if !strings.EqualFold(file.Records[i].Streamer.ID, id) {
 				continue
 			}
+			if s.site != "" && !file.Records[i].VisibleOn(s.site) {
+				continue
+			}
 			if fields.Alias != nil {
 				file.Records[i].Streamer.Alias = *fields.Alias
 			}
-			if fields.Description != nil {
-				file.Records[i].Streamer.Description = *fields.Description
-			}
 			if fields.Languages != nil {
 				file.Records[i].Streamer.Languages = append([]string(nil), (*fields.Languages)...)
 			}
+			if s.site != "" {
+				applySiteFields(&file.Records[i], s.site, fields)
+			} else {
+				if fields.Description != nil {
+					file.Records[i].Streamer.Description = *fields.Description
+				}
+				if fields.Featured != nil {
+					file.Records[i].Streamer.Featured = *fields.Featured
+				}
+			}
 			file.Records[i].UpdatedAt = time.Now().UTC()
 			updated = file.Records[i]
+			if s.site != "" {
+				updated = updated.ForSite(s.site)
+			}
 			return nil
 		}
 		return fmt.Errorf("%w: %s", ErrStreamerNotFound, id)
@@ -459,8 +534,8 @@ func (s *Store) UpdateFile(updateFn func(*File) error) error {
 	if updateFn == nil {
 		return errors.New("updateFn is required")
 	}
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mutex().Lock()
+	defer s.mutex().Unlock()
 	return s.updateFileLocked(updateFn)
 }
 
@@ -469,7 +544,9 @@ func UpdateFile(path string, updateFn func(*File) error) error {
 	return storeForPath(path).UpdateFile(updateFn)
 }
 
-// Delete removes a streamer by ID.
+// Delete removes a streamer by ID. On a site view the streamer is only taken
+// off that site while other sites still list it; records without a site list
+// are removed everywhere.
 func (s *Store) Delete(streamerID string) error {
 	if s == nil {
 		return errors.New("streamers store is nil")
@@ -478,14 +555,27 @@ func (s *Store) Delete(streamerID string) error {
 	if streamerID == "" {
 		return errors.New("streamer id is required")
 	}
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mutex().Lock()
+	defer s.mutex().Unlock()
 	return s.updateFileLocked(func(file *File) error {
 		for i := range file.Records {
-			if strings.EqualFold(file.Records[i].Streamer.ID, streamerID) {
-				file.Records = append(file.Records[:i], file.Records[i+1:]...)
-				return nil
+			if !strings.EqualFold(file.Records[i].Streamer.ID, streamerID) {
+				continue
+			}
+			if s.site != "" {
+				if !file.Records[i].VisibleOn(s.site) {
+					continue
+				}
+				// Only drop the site; the record stays on the others.
+				if remaining := removeSite(file.Records[i].Sites, s.site); len(remaining) > 0 {
+					file.Records[i].Sites = remaining
+					delete(file.Records[i].Overrides, s.site)
+					file.Records[i].UpdatedAt = time.Now().UTC()
+					return nil
+				}
 			}
+			file.Records = append(file.Records[:i], file.Records[i+1:]...)
+			return nil
 		}
 		return fmt.Errorf("%w: %s", ErrStreamerNotFound, streamerID)
 	})
@@ -505,16 +595,23 @@ func (s *Store) Get(streamerID string) (Record, error) {
 	if streamerID == "" {
 		return Record{}, errors.New("streamer id is required")
 	}
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mutex().Lock()
+	defer s.mutex().Unlock()
 	fileData, err := s.readFileLocked()
 	if err != nil {
 		return Record{}, err
 	}
 	for _, record := range fileData.Records {
-		if strings.EqualFold(record.Streamer.ID, streamerID) {
-			return record, nil
+		if !strings.EqualFold(record.Streamer.ID, streamerID) {
+			continue
+		}
+		if s.site != "" {
+			if !record.VisibleOn(s.site) {
+				break
+			}
+			return record.ForSite(s.site), nil
 		}
+		return record, nil
 	}
 	return Record{}, fmt.Errorf("%w: %s", ErrStreamerNotFound, streamerID)
 }
@@ -526,17 +623,15 @@ func Get(path, streamerID string) (Record, error) {
 
 // SetYouTubeLive marks the streamer associated with the provided channel ID as live.
 func (s *Store) SetYouTubeLive(channelID, videoID string, startedAt time.Time) (Record, error) {
+	return s.SetYouTubeStream(channelID, YouTubeStatus{VideoID: videoID, StartedAt: startedAt})
+}
+
+// SetYouTubeStream marks the streamer associated with the provided channel ID
+// as live with the given stream details.
+func (s *Store) SetYouTubeStream(channelID string, stream YouTubeStatus) (Record, error) {
 	return s.updateYouTubeStatus(channelID, func(status *Status) {
-		if status.YouTube == nil {
-			status.YouTube = &YouTubeStatus{}
-		}
-		status.YouTube.Live = true
-		status.YouTube.VideoID = videoID
-		if !startedAt.IsZero() {
-			status.YouTube.StartedAt = startedAt
-		} else {
-			status.YouTube.StartedAt = time.Time{}
-		}
+		stream.Live = true
+		status.YouTube = &stream
 		status.Platforms = addPlatform(status.Platforms, platformYouTube)
 		status.Live = true
 	})
@@ -544,13 +639,26 @@ func (s *Store) SetYouTubeLive(channelID, videoID string, startedAt time.Time) (
 
 // ClearYouTubeLive marks the YouTube platform as offline for the matching channel ID.
 func (s *Store) ClearYouTubeLive(channelID string) (Record, error) {
+	return s.EndYouTubeStream(channelID, "", time.Now().UTC())
+}
+
+// EndYouTubeStream marks the YouTube platform as offline for the matching
+// channel ID, recording endedAt if it was live. A non-empty videoID leaves the
+// status alone unless that video is the one live, so a stream that has since
+// been replaced is not ended.
+func (s *Store) EndYouTubeStream(channelID, videoID string, endedAt time.Time) (Record, error) {
 	return s.updateYouTubeStatus(channelID, func(status *Status) {
-		if status.YouTube == nil {
-			status.YouTube = &YouTubeStatus{}
+		ended := YouTubeStatus{}
+		if prev := status.YouTube; prev != nil {
+			if videoID != "" && prev.Live && prev.VideoID != videoID {
+				return
+			}
+			ended.EndedAt = prev.EndedAt
+			if prev.Live {
+				ended.EndedAt = endedAt
+			}
 		}
-		status.YouTube.Live = false
-		status.YouTube.VideoID = ""
-		status.YouTube.StartedAt = time.Time{}
+		status.YouTube = &ended
 		status.Platforms = removePlatform(status.Platforms, platformYouTube)
 	})
 }
@@ -570,17 +678,15 @@ const platformTwitch = "twitch"
 
 // SetTwitchLive marks the streamer associated with the provided broadcaster ID as live.
 func (s *Store) SetTwitchLive(broadcasterID, streamID string, startedAt time.Time) (Record, error) {
+	return s.SetTwitchStream(broadcasterID, TwitchStatus{StreamID: streamID, StartedAt: startedAt})
+}
+
+// SetTwitchStream marks the streamer associated with the provided broadcaster
+// ID as live with the given stream details.
+func (s *Store) SetTwitchStream(broadcasterID string, stream TwitchStatus) (Record, error) {
 	return s.updateTwitchStatus(broadcasterID, func(status *Status) {
-		if status.Twitch == nil {
-			status.Twitch = &TwitchStatus{}
-		}
-		status.Twitch.Live = true
-		status.Twitch.StreamID = streamID
-		if !startedAt.IsZero() {
-			status.Twitch.StartedAt = startedAt
-		} else {
-			status.Twitch.StartedAt = time.Time{}
-		}
+		stream.Live = true
+		status.Twitch = &stream
 		status.Platforms = addPlatform(status.Platforms, platformTwitch)
 		status.Live = true
 	})
@@ -589,12 +695,7 @@ func (s *Store) SetTwitchLive(broadcasterID, streamID string, startedAt time.Tim
 // ClearTwitchLive marks the Twitch platform as offline for the matching broadcaster ID.
 func (s *Store) ClearTwitchLive(broadcasterID string) (Record, error) {
 	return s.updateTwitchStatus(broadcasterID, func(status *Status) {
-		if status.Twitch == nil {
-			status.Twitch = &TwitchStatus{}
-		}
-		status.Twitch.Live = false
-		status.Twitch.StreamID = ""
-		status.Twitch.StartedAt = time.Time{}
+		status.Twitch = &TwitchStatus{}
 		status.Platforms = removePlatform(status.Platforms, platformTwitch)
 	})
 }
@@ -618,8 +719,8 @@ func (s *Store) updateTwitchStatus(broadcasterID string, updateFn func(*Status))
 		return Record{}, errors.New("twitch broadcaster id is required")
 	}
 	var updated Record
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mutex().Lock()
+	defer s.mutex().Unlock()
 	err := s.updateFileLocked(func(file *File) error {
 		for i := range file.Records {
 			tw := file.Records[i].Platforms.Twitch
@@ -660,8 +761,8 @@ func (s *Store) updateYouTubeStatus(channelID string, updateFn func(*Status)) (R
 		return Record{}, errors.New("youtube channel id is required")
 	}
 	var updated Record
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mutex().Lock()
+	defer s.mutex().Unlock()
 	err := s.updateFileLocked(func(file *File) error {
 		for i := range file.Records {
 			yt := file.Records[i].Platforms.YouTube
@@ -770,8 +871,8 @@ func (s *Store) GetByTwitchBroadcasterID(broadcasterID string) (Record, error) {
 	if broadcasterID == "" {
 		return Record{}, errors.New("twitch broadcaster id is required")
 	}
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mutex().Lock()
+	defer s.mutex().Unlock()
 	fileData, err := s.readFileLocked()
 	if err != nil {
 		return Record{}, err
@@ -794,8 +895,8 @@ func (s *Store) UpdateTwitchPlatform(streamerID string, platform *TwitchPlatform
 		return Record{}, errors.New("streamer id is required")
 	}
 	var updated Record
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mutex().Lock()
+	defer s.mutex().Unlock()
 	err := s.updateFileLocked(func(file *File) error {
 		for i := range file.Records {
 			if !strings.EqualFold(file.Records[i].Streamer.ID, streamerID) {
@@ -810,3 +911,40 @@ func (s *Store) UpdateTwitchPlatform(streamerID string, platform *TwitchPlatform
 	})
 	return updated, err
 }
+
+// AddPlatforms sets the platforms the streamer does not have yet, leaving
+// the ones it already tracks untouched.
+func (s *Store) AddPlatforms(streamerID string, platforms Platforms) (Record, error) {
+	if s == nil {
+		return Record{}, errors.New("streamers store is nil")
+	}
+	streamerID = strings.TrimSpace(streamerID)
+	if streamerID == "" {
+		return Record{}, errors.New("streamer id is required")
+	}
+	var updated Record
+	s.mutex().Lock()
+	defer s.mutex().Unlock()
+	err := s.updateFileLocked(func(file *File) error {
+		for i := range file.Records {
+			if !strings.EqualFold(file.Records[i].Streamer.ID, streamerID) {
+				continue
+			}
+			current := &file.Records[i].Platforms
+			if current.YouTube == nil {
+				current.YouTube = platforms.YouTube
+			}
+			if current.Twitch == nil {
+				current.Twitch = platforms.Twitch
+			}
+			if current.Facebook == nil {
+				current.Facebook = platforms.Facebook
+			}
+			file.Records[i].UpdatedAt = time.Now().UTC()
+			updated = file.Records[i]
+			return nil
+		}
+		return fmt.Errorf("%w: %s", ErrStreamerNotFound, streamerID)
+	})
+	return updated, err
+}
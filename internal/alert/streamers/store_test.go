@@ -313,3 +313,23 @@ func TestClearYouTubeLiveResetsStatus(t *testing.T) {
 		t.Fatalf("expected platforms to be empty, got %v", updated.Status.Platforms)
 	}
 }
+
+func TestChangedFiresForWritesThroughAnyStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "streamers.json")
+	watcher := NewStore(path)
+	changed := watcher.Changed()
+
+	if _, err := Append(path, Record{Streamer: Streamer{Alias: "Test"}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatalf("expected write through another store to signal Changed")
+	}
+	select {
+	case <-watcher.Changed():
+		t.Fatalf("expected a fresh signal after the write")
+	default:
+	}
+}
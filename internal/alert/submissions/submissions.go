@@ -56,6 +56,8 @@ type Submission struct {
 	Platforms   map[string]PlatformInfo `json:"platforms,omitempty"`
 	SubmittedAt time.Time               `json:"submittedAt"`
 	SubmittedBy string                  `json:"submittedBy,omitempty"`
+	// ContactEmail is where the submitter asked to hear about the decision.
+	ContactEmail string `json:"contactEmail,omitempty"`
 }
 
 // StoreOption customises the store behaviour.
@@ -0,0 +1,413 @@
+// Package subscribers stores viewers' email alert subscriptions. Each
+// subscription covers one streamer or the daily digest and stays pending
+// until the viewer follows the signed link in the confirmation email.
+package subscribers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Subscription kinds.
+const (
+	KindStreamer = "streamer"
+	KindDigest   = "digest"
+)
+
+// Link actions signed into confirmation and unsubscribe URLs.
+const (
+	ActionConfirm     = "confirm"
+	ActionUnsubscribe = "unsubscribe"
+)
+
+const (
+	// DefaultFilePath is where subscriptions are stored when no path is given.
+	DefaultFilePath = "data/subscribers.json"
+	// PendingTTL is how long an unconfirmed subscription waits before it is
+	// dropped.
+	PendingTTL = 7 * 24 * time.Hour
+	// ResendInterval throttles confirmation emails to one address, whichever
+	// of its subscriptions they are for.
+	ResendInterval = 10 * time.Minute
+	// MaxPerAddress bounds the subscriptions one address can hold.
+	MaxPerAddress = 50
+	// activitySize bounds the live events kept for the next digest.
+	activitySize = 500
+)
+
+var (
+	// ErrNotFound is returned when a subscription ID cannot be located.
+	ErrNotFound = errors.New("subscription not found")
+	// ErrUnknownKind is returned for kinds other than streamer or digest.
+	ErrUnknownKind = errors.New("unknown subscription kind")
+	// ErrTooMany is returned when an address already holds MaxPerAddress
+	// subscriptions.
+	ErrTooMany = errors.New("too many subscriptions for this address")
+)
+
+// Subscription is one address's alert for a streamer or the digest.
+type Subscription struct {
+	ID         string    `json:"id"`
+	Email      string    `json:"email"`
+	Kind       string    `json:"kind"`
+	StreamerID string    `json:"streamerId,omitempty"`
+	Confirmed  bool      `json:"confirmed"`
+	CreatedAt  time.Time `json:"createdAt"`
+	// ConfirmedAt is zero while the subscription is pending.
+	ConfirmedAt time.Time `json:"confirmedAt,omitempty"`
+	// LastSent is when the last email for this subscription went out; it
+	// throttles confirmations and repeat live alerts.
+	LastSent time.Time `json:"lastSent,omitempty"`
+}
+
+// Activity is a live event remembered for the next digest.
+type Activity struct {
+	StreamerID string    `json:"streamerId"`
+	Alias      string    `json:"alias"`
+	Platform   string    `json:"platform"`
+	URL        string    `json:"url,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// File is the on-disk subscribers format.
+type File struct {
+	// Secret keys the HMAC on confirmation and unsubscribe links.
+	Secret        string         `json:"secret,omitempty"`
+	Subscriptions []Subscription `json:"subscriptions"`
+	Activity      []Activity     `json:"activity"`
+	LastDigest    time.Time      `json:"lastDigest,omitempty"`
+}
+
+// Store persists a site's email subscriptions.
+type Store struct {
+	path string
+	mu   sync.Mutex
+	now  func() time.Time
+}
+
+// NewStore returns a file-backed subscribers store for path.
+func NewStore(path string) *Store {
+	if path == "" {
+		path = DefaultFilePath
+	}
+	return &Store{path: filepath.Clean(path), now: time.Now}
+}
+
+// Path returns the path backing the store.
+func (s *Store) Path() string {
+	if s == nil {
+		return ""
+	}
+	return s.path
+}
+
+// Subscribe records a pending subscription for email, or returns the
+// existing one. send reports whether a confirmation email should go out:
+// it is false for confirmed subscriptions and while ResendInterval has not
+// passed since the last confirmation sent to the address for any of its
+// pending subscriptions. The address must already be normalised.
+func (s *Store) Subscribe(email, kind, streamerID string) (sub Subscription, send bool, err error) {
+	switch kind {
+	case KindStreamer:
+		if strings.TrimSpace(streamerID) == "" {
+			return Subscription{}, false, fmt.Errorf("%w: streamer subscriptions need a streamer", ErrUnknownKind)
+		}
+	case KindDigest:
+		streamerID = ""
+	default:
+		return Subscription{}, false, ErrUnknownKind
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.read()
+	if err != nil {
+		return Subscription{}, false, err
+	}
+	now := s.now().UTC()
+	held, match := 0, -1
+	var lastConfirmation time.Time
+	for i, existing := range file.Subscriptions {
+		if existing.Email != email {
+			continue
+		}
+		held++
+		if !existing.Confirmed && existing.LastSent.After(lastConfirmation) {
+			lastConfirmation = existing.LastSent
+		}
+		if existing.Kind == kind && strings.EqualFold(existing.StreamerID, streamerID) {
+			match = i
+		}
+	}
+	send = now.Sub(lastConfirmation) >= ResendInterval
+	if match >= 0 {
+		existing := file.Subscriptions[match]
+		if existing.Confirmed || !send {
+			return existing, false, nil
+		}
+		file.Subscriptions[match].LastSent = now
+		if err := writeFile(s.path, file); err != nil {
+			return Subscription{}, false, err
+		}
+		return file.Subscriptions[match], true, nil
+	}
+	if held >= MaxPerAddress {
+		return Subscription{}, false, ErrTooMany
+	}
+	sub = Subscription{
+		ID:         randomHex(12),
+		Email:      email,
+		Kind:       kind,
+		StreamerID: streamerID,
+		CreatedAt:  now,
+	}
+	if send {
+		sub.LastSent = now
+	}
+	file.Subscriptions = append(file.Subscriptions, sub)
+	if err := writeFile(s.path, file); err != nil {
+		return Subscription{}, false, err
+	}
+	return sub, send, nil
+}
+
+// Get returns the subscription with id.
+func (s *Store) Get(id string) (Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.read()
+	if err != nil {
+		return Subscription{}, err
+	}
+	for _, sub := range file.Subscriptions {
+		if sub.ID == id {
+			return sub, nil
+		}
+	}
+	return Subscription{}, ErrNotFound
+}
+
+// Confirm marks the subscription confirmed.
+func (s *Store) Confirm(id string) (Subscription, error) {
+	return s.update(id, func(sub *Subscription) {
+		if !sub.Confirmed {
+			sub.Confirmed = true
+			sub.ConfirmedAt = s.now().UTC()
+		}
+	})
+}
+
+// MarkSent records that an alert for the subscription went out at.
+func (s *Store) MarkSent(id string, at time.Time) error {
+	_, err := s.update(id, func(sub *Subscription) { sub.LastSent = at.UTC() })
+	return err
+}
+
+// Unsubscribe deletes the subscription.
+func (s *Store) Unsubscribe(id string) (Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.read()
+	if err != nil {
+		return Subscription{}, err
+	}
+	for i, sub := range file.Subscriptions {
+		if sub.ID == id {
+			file.Subscriptions = append(file.Subscriptions[:i], file.Subscriptions[i+1:]...)
+			return sub, writeFile(s.path, file)
+		}
+	}
+	return Subscription{}, ErrNotFound
+}
+
+// Followers returns the confirmed subscriptions to streamerID.
+func (s *Store) Followers(streamerID string) ([]Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	var followers []Subscription
+	for _, sub := range file.Subscriptions {
+		if sub.Confirmed && sub.Kind == KindStreamer && strings.EqualFold(sub.StreamerID, streamerID) {
+			followers = append(followers, sub)
+		}
+	}
+	return followers, nil
+}
+
+// RecordActivity remembers a live event for the next digest.
+func (s *Store) RecordActivity(activity Activity) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.read()
+	if err != nil {
+		return err
+	}
+	if activity.At.IsZero() {
+		activity.At = s.now()
+	}
+	activity.At = activity.At.UTC()
+	file.Activity = append(file.Activity, activity)
+	if len(file.Activity) > activitySize {
+		file.Activity = file.Activity[len(file.Activity)-activitySize:]
+	}
+	return writeFile(s.path, file)
+}
+
+// TakeDigest returns the confirmed digest subscriptions and the activity
+// since the last digest once every has passed since it, clearing the
+// activity. due is false until then.
+func (s *Store) TakeDigest(every time.Duration) (subs []Subscription, activity []Activity, due bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.read()
+	if err != nil {
+		return nil, nil, false, err
+	}
+	now := s.now().UTC()
+	if file.LastDigest.IsZero() {
+		// Start the clock on first run rather than sending straight away.
+		file.LastDigest = now
+		return nil, nil, false, writeFile(s.path, file)
+	}
+	if now.Sub(file.LastDigest) < every {
+		return nil, nil, false, nil
+	}
+	for _, sub := range file.Subscriptions {
+		if sub.Confirmed && sub.Kind == KindDigest {
+			subs = append(subs, sub)
+		}
+	}
+	activity = file.Activity
+	file.Activity = nil
+	file.LastDigest = now
+	return subs, activity, true, writeFile(s.path, file)
+}
+
+// Sign returns the signature for action on subscription id.
+func (s *Store) Sign(action, id string) (string, error) {
+	secret, err := s.secret()
+	if err != nil {
+		return "", err
+	}
+	return sign(secret, action, id), nil
+}
+
+// Verify reports whether sig is the signature for action on id.
+func (s *Store) Verify(action, id, sig string) bool {
+	secret, err := s.secret()
+	if err != nil || id == "" {
+		return false
+	}
+	return hmac.Equal([]byte(sign(secret, action, id)), []byte(sig))
+}
+
+func (s *Store) secret() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	if file.Secret == "" {
+		file.Secret = randomHex(32)
+		if err := writeFile(s.path, file); err != nil {
+			return nil, err
+		}
+	}
+	return []byte(file.Secret), nil
+}
+
+func (s *Store) update(id string, apply func(*Subscription)) (Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.read()
+	if err != nil {
+		return Subscription{}, err
+	}
+	for i := range file.Subscriptions {
+		if file.Subscriptions[i].ID == id {
+			apply(&file.Subscriptions[i])
+			return file.Subscriptions[i], writeFile(s.path, file)
+		}
+	}
+	return Subscription{}, ErrNotFound
+}
+
+// read loads the file, dropping pending subscriptions older than PendingTTL.
+func (s *Store) read() (File, error) {
+	file, err := readFile(s.path)
+	if err != nil {
+		return File{}, err
+	}
+	cutoff := s.now().Add(-PendingTTL)
+	kept := file.Subscriptions[:0]
+	for _, sub := range file.Subscriptions {
+		if sub.Confirmed || sub.CreatedAt.After(cutoff) {
+			kept = append(kept, sub)
+		}
+	}
+	file.Subscriptions = kept
+	return file, nil
+}
+
+func sign(secret []byte, action, id string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(action + ":" + id))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func readFile(path string) (File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return File{}, nil
+		}
+		return File{}, err
+	}
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return File{}, fmt.Errorf("decode subscribers file: %w", err)
+	}
+	return file, nil
+}
+
+func writeFile(path string, file File) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create subscribers dir: %w", err)
+	}
+	if file.Subscriptions == nil {
+		file.Subscriptions = []Subscription{}
+	}
+	if file.Activity == nil {
+		file.Activity = []Activity{}
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode subscribers file: %w", err)
+	}
+	// Viewer addresses and the link secret are stored in this file, so
+	// keep it private to the server.
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write subscribers file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package subscribers
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) (*Store, *time.Time) {
+	t.Helper()
+	store := NewStore(filepath.Join(t.TempDir(), "subscribers.json"))
+	clock := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return clock }
+	return store, &clock
+}
+
+func TestSubscribeConfirmAndThrottle(t *testing.T) {
+	store, clock := newTestStore(t)
+
+	if _, _, err := store.Subscribe("viewer@example.com", "weekly", ""); !errors.Is(err, ErrUnknownKind) {
+		t.Fatalf("expected ErrUnknownKind, got %v", err)
+	}
+	sub, send, err := store.Subscribe("viewer@example.com", KindStreamer, "alpha")
+	if err != nil || !send || sub.Confirmed {
+		t.Fatalf("expected a pending subscription needing confirmation, got %+v %v %v", sub, send, err)
+	}
+	if again, send, _ := store.Subscribe("viewer@example.com", KindStreamer, "ALPHA"); send || again.ID != sub.ID {
+		t.Fatalf("expected a repeat inside the resend interval to be throttled, got %+v %v", again, send)
+	}
+	*clock = clock.Add(ResendInterval)
+	if _, send, _ := store.Subscribe("viewer@example.com", KindStreamer, "alpha"); !send {
+		t.Fatalf("expected the confirmation to be resent after the interval")
+	}
+
+	if followers, _ := store.Followers("alpha"); len(followers) != 0 {
+		t.Fatalf("pending subscriptions must not receive alerts, got %+v", followers)
+	}
+	if _, err := store.Confirm(sub.ID); err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	followers, _ := store.Followers("Alpha")
+	if len(followers) != 1 || followers[0].ConfirmedAt.IsZero() {
+		t.Fatalf("expected one confirmed follower, got %+v", followers)
+	}
+	if _, send, _ := store.Subscribe("viewer@example.com", KindStreamer, "alpha"); send {
+		t.Fatalf("confirmed subscriptions must not be re-confirmed")
+	}
+
+	if _, err := store.Unsubscribe(sub.ID); err != nil {
+		t.Fatalf("unsubscribe: %v", err)
+	}
+	if _, err := store.Get(sub.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected the subscription to be gone, got %v", err)
+	}
+}
+
+func TestConfirmationsThrottledPerAddress(t *testing.T) {
+	store, clock := newTestStore(t)
+	if _, send, _ := store.Subscribe("viewer@example.com", KindStreamer, "alpha"); !send {
+		t.Fatal("expected the first confirmation to be sent")
+	}
+	beta, send, err := store.Subscribe("viewer@example.com", KindStreamer, "beta")
+	if err != nil || send {
+		t.Fatalf("expected a second streamer inside the resend interval to send nothing, got %v %v", send, err)
+	}
+	if _, send, _ := store.Subscribe("viewer@example.com", KindDigest, ""); send {
+		t.Fatal("expected the digest inside the resend interval to send nothing")
+	}
+	if _, send, _ := store.Subscribe("other@example.com", KindStreamer, "beta"); !send {
+		t.Fatal("expected another address to be unaffected")
+	}
+	*clock = clock.Add(ResendInterval)
+	if again, send, _ := store.Subscribe("viewer@example.com", KindStreamer, "beta"); !send || again.ID != beta.ID {
+		t.Fatalf("expected the held subscription to be confirmed after the interval, got %+v %v", again, send)
+	}
+}
+
+func TestPendingSubscriptionsExpire(t *testing.T) {
+	store, clock := newTestStore(t)
+	sub, _, _ := store.Subscribe("viewer@example.com", KindDigest, "")
+	*clock = clock.Add(PendingTTL + time.Minute)
+	if _, err := store.Confirm(sub.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected an expired pending subscription to be dropped, got %v", err)
+	}
+}
+
+func TestSignedLinks(t *testing.T) {
+	store, _ := newTestStore(t)
+	sig, err := store.Sign(ActionUnsubscribe, "abc")
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if !store.Verify(ActionUnsubscribe, "abc", sig) {
+		t.Fatalf("expected the signature to verify")
+	}
+	if store.Verify(ActionConfirm, "abc", sig) || store.Verify(ActionUnsubscribe, "abd", sig) {
+		t.Fatalf("signatures must be bound to the action and ID")
+	}
+	if !NewStore(store.Path()).Verify(ActionUnsubscribe, "abc", sig) {
+		t.Fatalf("expected the link secret to persist")
+	}
+}
+
+func TestTakeDigest(t *testing.T) {
+	store, clock := newTestStore(t)
+	sub, _, _ := store.Subscribe("viewer@example.com", KindDigest, "")
+	_, _ = store.Confirm(sub.ID)
+	_, _, _ = store.Subscribe("pending@example.com", KindDigest, "")
+
+	if _, _, due, _ := store.TakeDigest(24 * time.Hour); due {
+		t.Fatalf("the first call should only start the clock")
+	}
+	_ = store.RecordActivity(Activity{StreamerID: "alpha", Alias: "alpha", Platform: "twitch"})
+	*clock = clock.Add(23 * time.Hour)
+	if _, _, due, _ := store.TakeDigest(24 * time.Hour); due {
+		t.Fatalf("digest is not due yet")
+	}
+	*clock = clock.Add(time.Hour)
+	subs, activity, due, err := store.TakeDigest(24 * time.Hour)
+	if err != nil || !due || len(subs) != 1 || subs[0].Email != "viewer@example.com" || len(activity) != 1 {
+		t.Fatalf("unexpected digest %+v %+v %v %v", subs, activity, due, err)
+	}
+	*clock = clock.Add(24 * time.Hour)
+	if _, activity, due, _ := store.TakeDigest(24 * time.Hour); !due || len(activity) != 0 {
+		t.Fatalf("expected the activity to be cleared, got %+v", activity)
+	}
+}
@@ -0,0 +1,229 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// MaxAttempts is how many times a delivery is tried before it is dropped.
+	MaxAttempts = 8
+	// baseBackoff is the delay before the first retry; each retry doubles it.
+	baseBackoff = 30 * time.Second
+	// maxBackoff caps the delay between retries.
+	maxBackoff = 6 * time.Hour
+	// idlePoll is how often the queue is rechecked when nothing is scheduled,
+	// which also picks up deliveries queued by another process.
+	idlePoll = time.Minute
+)
+
+// Request headers sent with every delivery.
+const (
+	HeaderEvent     = "X-Sharpen-Event"
+	HeaderDelivery  = "X-Sharpen-Delivery"
+	HeaderTimestamp = "X-Sharpen-Timestamp"
+	HeaderSignature = "X-Sharpen-Signature"
+)
+
+// Payload is the JSON body posted to endpoints.
+type Payload struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Site      string    `json:"site,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      any       `json:"data"`
+}
+
+// Sign returns the signature header value for body sent at timestamp: the
+// hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the endpoint secret.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff returns the delay after the given failed attempt.
+func Backoff(attempt int) time.Duration {
+	delay := baseBackoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+// Dispatcher queues events for a site's endpoints and delivers them.
+type Dispatcher struct {
+	store  *Store
+	site   string
+	client *http.Client
+	now    func() time.Time
+	wake   chan struct{}
+}
+
+// Options configures a Dispatcher.
+type Options struct {
+	// Site is included in every payload.
+	Site string
+	// Client sends deliveries; it defaults to a client with a 10s timeout.
+	Client *http.Client
+}
+
+// NewDispatcher returns a dispatcher for the endpoints in store.
+func NewDispatcher(store *Store, opts Options) *Dispatcher {
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Dispatcher{
+		store:  store,
+		site:   opts.Site,
+		client: client,
+		now:    time.Now,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Store returns the dispatcher's store.
+func (d *Dispatcher) Store() *Store {
+	if d == nil {
+		return nil
+	}
+	return d.store
+}
+
+// Emit queues event for every subscribed endpoint and wakes the sender.
+func (d *Dispatcher) Emit(event string, data any) error {
+	if d == nil {
+		return nil
+	}
+	body, err := json.Marshal(Payload{
+		ID:        "evt_" + randomHex(8),
+		Type:      event,
+		Site:      d.site,
+		CreatedAt: d.now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return fmt.Errorf("encode webhook payload: %w", err)
+	}
+	queued, err := d.store.Enqueue(event, body)
+	if err != nil || queued == 0 {
+		return err
+	}
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run sends due deliveries until ctx is done, sleeping until the next retry
+// or a new event.
+func (d *Dispatcher) Run(ctx context.Context) {
+	for {
+		next := d.DeliverDue(ctx)
+		wait := idlePoll
+		if !next.IsZero() {
+			wait = min(max(next.Sub(d.now()), 0), idlePoll)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-d.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// DeliverDue attempts every due delivery once and returns when the next
+// queued one falls due, or zero when the queue is empty.
+func (d *Dispatcher) DeliverDue(ctx context.Context) time.Time {
+	due, endpoints, next, err := d.store.Due(d.now())
+	if err != nil {
+		return time.Time{}
+	}
+	for _, delivery := range due {
+		if ctx.Err() != nil {
+			return next
+		}
+		endpoint, ok := endpoints[delivery.EndpointID]
+		if !ok {
+			continue
+		}
+		delivery = d.attempt(ctx, endpoint, delivery)
+		if delivery.NextAttempt.After(d.now()) && (next.IsZero() || delivery.NextAttempt.Before(next)) {
+			next = delivery.NextAttempt
+		}
+	}
+	return next
+}
+
+// attempt posts one delivery and records the outcome.
+func (d *Dispatcher) attempt(ctx context.Context, endpoint Endpoint, delivery Delivery) Delivery {
+	delivery.Attempts++
+	entry := LogEntry{
+		DeliveryID: delivery.ID,
+		EndpointID: endpoint.ID,
+		URL:        endpoint.URL,
+		Event:      delivery.Event,
+		Attempt:    delivery.Attempts,
+		At:         d.now().UTC(),
+	}
+
+	status, err := d.post(ctx, endpoint, delivery)
+	entry.StatusCode = status
+	switch {
+	case err == nil:
+		entry.Outcome = OutcomeDelivered
+		delivery.LastError = ""
+	case delivery.Attempts >= MaxAttempts:
+		entry.Outcome = OutcomeFailed
+		entry.Error = err.Error()
+	default:
+		entry.Outcome = OutcomeRetrying
+		entry.Error = err.Error()
+		delivery.LastError = err.Error()
+		delivery.NextAttempt = d.now().Add(Backoff(delivery.Attempts)).UTC()
+	}
+	_ = d.store.Record(delivery, entry)
+	return delivery
+}
+
+func (d *Dispatcher) post(ctx context.Context, endpoint Endpoint, delivery Delivery) (int, error) {
+	timestamp := d.now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Sharpen-Live-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(endpoint.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
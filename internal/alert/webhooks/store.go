@@ -0,0 +1,371 @@
+// Package webhooks delivers roster events to endpoints registered by site
+// admins. Endpoints, the retry queue and the delivery log are persisted
+// together in one JSON file per site.
+package webhooks
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event types delivered to endpoints.
+const (
+	EventStreamerLive     = "streamer.live"
+	EventStreamerOffline  = "streamer.offline"
+	EventStreamerApproved = "streamer.approved"
+	EventStreamerDeleted  = "streamer.deleted"
+)
+
+// EventTypes lists every event an endpoint can subscribe to.
+var EventTypes = []string{EventStreamerLive, EventStreamerOffline, EventStreamerApproved, EventStreamerDeleted}
+
+const (
+	// DefaultFilePath is where webhooks are stored when no path is given.
+	DefaultFilePath = "data/webhooks.json"
+	// logSize bounds the delivery log kept on disk.
+	logSize = 200
+)
+
+var (
+	// ErrNotFound is returned when an endpoint ID cannot be located.
+	ErrNotFound = errors.New("webhook endpoint not found")
+	// ErrInvalidURL is returned for endpoint URLs that are not absolute http(s) URLs.
+	ErrInvalidURL = errors.New("webhook URL must be an absolute http or https URL")
+	// ErrUnknownEvent is returned when an endpoint filter names an unknown event.
+	ErrUnknownEvent = errors.New("unknown webhook event")
+)
+
+// Endpoint is a URL that receives signed event deliveries.
+type Endpoint struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+	// Events filters the deliveries; empty means every event.
+	Events    []string  `json:"events,omitempty"`
+	Secret    string    `json:"secret"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Wants reports whether the endpoint subscribes to event.
+func (e Endpoint) Wants(event string) bool {
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, want := range e.Events {
+		if want == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery is one event waiting to be sent to one endpoint.
+type Delivery struct {
+	ID          string          `json:"id"`
+	EndpointID  string          `json:"endpointId"`
+	Event       string          `json:"event"`
+	Payload     json.RawMessage `json:"payload"`
+	Attempts    int             `json:"attempts"`
+	NextAttempt time.Time       `json:"nextAttempt"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	LastError   string          `json:"lastError,omitempty"`
+}
+
+// Delivery outcomes recorded in the log.
+const (
+	OutcomeDelivered = "delivered"
+	OutcomeRetrying  = "retrying"
+	OutcomeFailed    = "failed"
+)
+
+// LogEntry records one delivery attempt.
+type LogEntry struct {
+	DeliveryID string    `json:"deliveryId"`
+	EndpointID string    `json:"endpointId"`
+	URL        string    `json:"url"`
+	Event      string    `json:"event"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Outcome    string    `json:"outcome"`
+	At         time.Time `json:"at"`
+}
+
+// File is the on-disk webhooks format.
+type File struct {
+	Endpoints []Endpoint `json:"endpoints"`
+	Queue     []Delivery `json:"queue"`
+	Log       []LogEntry `json:"log"`
+}
+
+// Store persists a site's endpoints, retry queue and delivery log.
+type Store struct {
+	path string
+	mu   sync.Mutex
+	now  func() time.Time
+}
+
+// NewStore returns a file-backed webhooks store for path.
+func NewStore(path string) *Store {
+	if path == "" {
+		path = DefaultFilePath
+	}
+	return &Store{path: filepath.Clean(path), now: time.Now}
+}
+
+// Path returns the path backing the store.
+func (s *Store) Path() string {
+	if s == nil {
+		return ""
+	}
+	return s.path
+}
+
+// Endpoints returns the registered endpoints, oldest first.
+func (s *Store) Endpoints() ([]Endpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := readFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	return file.Endpoints, nil
+}
+
+// AddEndpoint validates and registers an endpoint, generating its ID and,
+// when none is given, its signing secret.
+func (s *Store) AddEndpoint(endpoint Endpoint) (Endpoint, error) {
+	endpoint.URL = strings.TrimSpace(endpoint.URL)
+	parsed, err := url.Parse(endpoint.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return Endpoint{}, ErrInvalidURL
+	}
+	for _, event := range endpoint.Events {
+		if !knownEvent(event) {
+			return Endpoint{}, fmt.Errorf("%w: %s", ErrUnknownEvent, event)
+		}
+	}
+	endpoint.Secret = strings.TrimSpace(endpoint.Secret)
+	if endpoint.Secret == "" {
+		endpoint.Secret = "whsec_" + randomHex(24)
+	}
+	endpoint.ID = "wh_" + randomHex(8)
+	endpoint.CreatedAt = s.now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := readFile(s.path)
+	if err != nil {
+		return Endpoint{}, err
+	}
+	file.Endpoints = append(file.Endpoints, endpoint)
+	if err := writeFile(s.path, file); err != nil {
+		return Endpoint{}, err
+	}
+	return endpoint, nil
+}
+
+// RemoveEndpoint deletes an endpoint and drops its queued deliveries.
+func (s *Store) RemoveEndpoint(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := readFile(s.path)
+	if err != nil {
+		return err
+	}
+	kept := file.Endpoints[:0]
+	for _, endpoint := range file.Endpoints {
+		if endpoint.ID != id {
+			kept = append(kept, endpoint)
+		}
+	}
+	if len(kept) == len(file.Endpoints) {
+		return ErrNotFound
+	}
+	file.Endpoints = kept
+	queue := file.Queue[:0]
+	for _, delivery := range file.Queue {
+		if delivery.EndpointID != id {
+			queue = append(queue, delivery)
+		}
+	}
+	file.Queue = queue
+	return writeFile(s.path, file)
+}
+
+// Enqueue queues payload for every endpoint subscribed to event and returns
+// the number of deliveries created.
+func (s *Store) Enqueue(event string, payload []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := readFile(s.path)
+	if err != nil {
+		return 0, err
+	}
+	now := s.now().UTC()
+	queued := 0
+	for _, endpoint := range file.Endpoints {
+		if !endpoint.Wants(event) {
+			continue
+		}
+		file.Queue = append(file.Queue, Delivery{
+			ID:          "whd_" + randomHex(8),
+			EndpointID:  endpoint.ID,
+			Event:       event,
+			Payload:     json.RawMessage(payload),
+			NextAttempt: now,
+			CreatedAt:   now,
+		})
+		queued++
+	}
+	if queued == 0 {
+		return 0, nil
+	}
+	return queued, writeFile(s.path, file)
+}
+
+// Due returns the queued deliveries whose next attempt is at or before now,
+// with their endpoints, and the time the next remaining one falls due.
+func (s *Store) Due(now time.Time) ([]Delivery, map[string]Endpoint, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := readFile(s.path)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	endpoints := make(map[string]Endpoint, len(file.Endpoints))
+	for _, endpoint := range file.Endpoints {
+		endpoints[endpoint.ID] = endpoint
+	}
+	var (
+		due  []Delivery
+		next time.Time
+	)
+	for _, delivery := range file.Queue {
+		if !delivery.NextAttempt.After(now) {
+			due = append(due, delivery)
+		} else if next.IsZero() || delivery.NextAttempt.Before(next) {
+			next = delivery.NextAttempt
+		}
+	}
+	return due, endpoints, next, nil
+}
+
+// Record stores the outcome of an attempt: delivered and failed deliveries
+// leave the queue, retrying ones are rescheduled. The attempt is logged.
+func (s *Store) Record(delivery Delivery, entry LogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := readFile(s.path)
+	if err != nil {
+		return err
+	}
+	for i, queued := range file.Queue {
+		if queued.ID != delivery.ID {
+			continue
+		}
+		if entry.Outcome == OutcomeRetrying {
+			file.Queue[i] = delivery
+		} else {
+			file.Queue = append(file.Queue[:i], file.Queue[i+1:]...)
+		}
+		break
+	}
+	file.Log = append(file.Log, entry)
+	if excess := len(file.Log) - logSize; excess > 0 {
+		file.Log = append([]LogEntry(nil), file.Log[excess:]...)
+	}
+	return writeFile(s.path, file)
+}
+
+// Log returns up to limit delivery attempts, newest first.
+func (s *Store) Log(limit int) ([]LogEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := readFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	out := append([]LogEntry(nil), file.Log...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].At.After(out[j].At) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// Pending returns the number of queued deliveries.
+func (s *Store) Pending() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := readFile(s.path)
+	if err != nil {
+		return 0, err
+	}
+	return len(file.Queue), nil
+}
+
+func knownEvent(event string) bool {
+	for _, known := range EventTypes {
+		if known == event {
+			return true
+		}
+	}
+	return false
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
+
+func readFile(path string) (File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return File{}, nil
+		}
+		return File{}, err
+	}
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return File{}, fmt.Errorf("decode webhooks file: %w", err)
+	}
+	return file, nil
+}
+
+func writeFile(path string, file File) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create webhooks dir: %w", err)
+	}
+	if file.Endpoints == nil {
+		file.Endpoints = []Endpoint{}
+	}
+	if file.Queue == nil {
+		file.Queue = []Delivery{}
+	}
+	if file.Log == nil {
+		file.Log = []LogEntry{}
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode webhooks file: %w", err)
+	}
+	// Secrets are stored in this file, so keep it private to the server.
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write webhooks file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStoreEndpoints(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "webhooks.json"))
+
+	if _, err := store.AddEndpoint(Endpoint{URL: "ftp://example.com"}); !errors.Is(err, ErrInvalidURL) {
+		t.Fatalf("expected invalid URL error, got %v", err)
+	}
+	if _, err := store.AddEndpoint(Endpoint{URL: "https://example.com/hook", Events: []string{"streamer.renamed"}}); !errors.Is(err, ErrUnknownEvent) {
+		t.Fatalf("expected unknown event error, got %v", err)
+	}
+	all, err := store.AddEndpoint(Endpoint{URL: " https://example.com/all "})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if all.ID == "" || all.URL != "https://example.com/all" || len(all.Secret) < 20 {
+		t.Fatalf("expected generated ID and secret, got %+v", all)
+	}
+	live, err := store.AddEndpoint(Endpoint{URL: "https://example.com/live", Events: []string{EventStreamerLive}, Secret: "s3cret"})
+	if err != nil || live.Secret != "s3cret" {
+		t.Fatalf("add filtered: %+v %v", live, err)
+	}
+
+	if queued, err := store.Enqueue(EventStreamerDeleted, []byte(`{}`)); err != nil || queued != 1 {
+		t.Fatalf("expected only the unfiltered endpoint to get deletions, got %d %v", queued, err)
+	}
+	if queued, err := store.Enqueue(EventStreamerLive, []byte(`{}`)); err != nil || queued != 2 {
+		t.Fatalf("expected both endpoints to get live events, got %d %v", queued, err)
+	}
+
+	if err := store.RemoveEndpoint(all.ID); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := store.RemoveEndpoint(all.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	reopened := NewStore(store.Path())
+	endpoints, _ := reopened.Endpoints()
+	pending, _ := reopened.Pending()
+	if len(endpoints) != 1 || endpoints[0].ID != live.ID || pending != 1 {
+		t.Fatalf("expected removal to drop the endpoint's queue, got %d endpoints, %d pending", len(endpoints), pending)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := map[int]time.Duration{1: 30 * time.Second, 2: time.Minute, 4: 4 * time.Minute, 20: 6 * time.Hour}
+	for attempt, want := range tests {
+		if got := Backoff(attempt); got != want {
+			t.Fatalf("attempt %d: expected %s, got %s", attempt, want, got)
+		}
+	}
+}
+
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	body, _ := io.ReadAll(req.Body)
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	status := http.StatusNoContent
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func TestDispatcherSignsAndRetries(t *testing.T) {
+	recv := &receiver{statuses: []int{http.StatusInternalServerError, http.StatusBadGateway}}
+	target := httptest.NewServer(recv)
+	defer target.Close()
+
+	store := NewStore(filepath.Join(t.TempDir(), "webhooks.json"))
+	endpoint, err := store.AddEndpoint(Endpoint{URL: target.URL, Secret: "topsecret"})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	clock := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	d := NewDispatcher(store, Options{Site: "synth-wave"})
+	d.now = func() time.Time { return clock }
+	store.now = d.now
+
+	if err := d.Emit(EventStreamerLive, map[string]string{"alias": "Charlie"}); err != nil {
+		t.Fatalf("emit: %v", err)
+	}
+	ctx := context.Background()
+
+	next := d.DeliverDue(ctx)
+	if want := clock.Add(30 * time.Second); !next.Equal(want) {
+		t.Fatalf("expected first retry at %s, got %s", want, next)
+	}
+	if again := d.DeliverDue(ctx); !again.Equal(next) || len(recv.requests) != 1 {
+		t.Fatalf("expected nothing to be sent before the retry is due")
+	}
+	clock = next
+	next = d.DeliverDue(ctx)
+	if want := clock.Add(time.Minute); !next.Equal(want) {
+		t.Fatalf("expected second retry after a doubled delay, got %s", next)
+	}
+	clock = next
+	if next = d.DeliverDue(ctx); !next.IsZero() {
+		t.Fatalf("expected the queue to be empty after delivery, got %s", next)
+	}
+
+	if len(recv.requests) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(recv.requests))
+	}
+	req, body := recv.requests[2], recv.bodies[2]
+	timestamp, _ := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+	if req.Header.Get(HeaderSignature) != Sign(endpoint.Secret, timestamp, body) || req.Header.Get(HeaderEvent) != EventStreamerLive || req.Header.Get(HeaderDelivery) == "" {
+		t.Fatalf("unexpected delivery headers %v", req.Header)
+	}
+	if recv.bodies[0] == nil || string(recv.bodies[0]) != string(body) {
+		t.Fatalf("expected retries to resend the same payload")
+	}
+	var payload struct {
+		Type string            `json:"type"`
+		Site string            `json:"site"`
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Type != EventStreamerLive || payload.Site != "synth-wave" || payload.Data["alias"] != "Charlie" {
+		t.Fatalf("unexpected payload %s (%v)", body, err)
+	}
+
+	log, _ := store.Log(10)
+	if len(log) != 3 || log[0].Outcome != OutcomeDelivered || log[0].StatusCode != http.StatusNoContent || log[2].Outcome != OutcomeRetrying || log[2].StatusCode != http.StatusInternalServerError {
+		t.Fatalf("unexpected delivery log %+v", log)
+	}
+}
+
+func TestDispatcherGivesUp(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer target.Close()
+
+	store := NewStore(filepath.Join(t.TempDir(), "webhooks.json"))
+	if _, err := store.AddEndpoint(Endpoint{URL: target.URL}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	clock := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	d := NewDispatcher(store, Options{})
+	d.now = func() time.Time { return clock }
+	store.now = d.now
+	if err := d.Emit(EventStreamerDeleted, nil); err != nil {
+		t.Fatalf("emit: %v", err)
+	}
+
+	// A restarted dispatcher picks the queue up from disk.
+	restarted := NewDispatcher(NewStore(store.Path()), Options{})
+	restarted.now = d.now
+	for i := 0; i < MaxAttempts; i++ {
+		if next := restarted.DeliverDue(context.Background()); !next.IsZero() {
+			clock = next
+		}
+	}
+	pending, _ := store.Pending()
+	log, _ := store.Log(1)
+	if pending != 0 || len(log) != 1 || log[0].Outcome != OutcomeFailed || log[0].Attempt != MaxAttempts {
+		t.Fatalf("expected the delivery to be dropped after %d attempts, got %d pending, log %+v", MaxAttempts, pending, log)
+	}
+}
@@ -0,0 +1,96 @@
+package webpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	// recordSize is the aes128gcm record size advertised in the header.
+	// Payloads are sent as a single record, so they must fit in it.
+	recordSize = 4096
+	// MaxPayload is the largest plaintext that fits one record after the
+	// padding delimiter and GCM tag.
+	MaxPayload = recordSize - 16 - 1
+)
+
+// ErrPayloadTooLarge is returned for payloads over MaxPayload bytes.
+var ErrPayloadTooLarge = errors.New("push payload too large")
+
+// Encrypt encrypts payload for sub using the aes128gcm content coding
+// (RFC 8188) with the Web Push key derivation of RFC 8291. The result is
+// the request body; it carries its own salt and sender key.
+func Encrypt(sub Subscription, payload []byte) ([]byte, error) {
+	local, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate push key: %w", err)
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generate push salt: %w", err)
+	}
+	return encrypt(sub, payload, local, salt)
+}
+
+func encrypt(sub Subscription, payload []byte, local *ecdh.PrivateKey, salt []byte) ([]byte, error) {
+	if len(payload) > MaxPayload {
+		return nil, ErrPayloadTooLarge
+	}
+	if err := validate(sub, nil); err != nil {
+		return nil, err
+	}
+	uaPublicBytes, _ := decodeKey(sub.Keys.P256DH)
+	auth, _ := decodeKey(sub.Keys.Auth)
+	uaPublic, err := ecdh.P256().NewPublicKey(uaPublicBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSubscription, err)
+	}
+	shared, err := local.ECDH(uaPublic)
+	if err != nil {
+		return nil, fmt.Errorf("derive push secret: %w", err)
+	}
+	asPublic := local.PublicKey().Bytes()
+
+	// RFC 8291 section 3.4: mix the auth secret and both public keys into
+	// the input keying material, then derive the content key and nonce
+	// from it with the record salt as in RFC 8188.
+	info := append([]byte("WebPush: info\x00"), uaPublicBytes...)
+	info = append(info, asPublic...)
+	ikm, err := hkdf.Key(sha256.New, shared, auth, string(info), 32)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	// A single, final record: the plaintext followed by the 0x02 delimiter.
+	record := append(append(make([]byte, 0, len(payload)+1), payload...), 0x02)
+
+	header := make([]byte, 0, 16+4+1+len(asPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+	return gcm.Seal(header, nonce, record, nil), nil
+}
@@ -0,0 +1,67 @@
+package webpush
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which
+// netip does not count as private.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// publicAddr reports whether addr is a routable unicast address outside the
+// loopback, private, link-local and shared ranges, so a push cannot be
+// aimed at the server's own network or a cloud metadata service.
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() &&
+		!addr.IsPrivate() &&
+		!addr.IsLoopback() &&
+		!addr.IsLinkLocalUnicast() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// checkHost rejects endpoint hosts that name a non-public address outright.
+// Names are checked again against the addresses they resolve to when the
+// push is sent, since DNS can change after subscribing.
+func checkHost(host string) error {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: endpoint must be a public host", ErrInvalidSubscription)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !publicAddr(addr) {
+		return fmt.Errorf("%w: endpoint must be a public host", ErrInvalidSubscription)
+	}
+	return nil
+}
+
+// dialPublic refuses connections to non-public addresses. It runs after DNS
+// resolution, so a name that resolves to an internal host is caught too.
+func dialPublic(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !publicAddr(addrPort.Addr()) {
+		return fmt.Errorf("push endpoint resolves to non-public address %s", addrPort.Addr())
+	}
+	return nil
+}
+
+// newClient returns the default push client: it dials only public
+// addresses and ignores proxy settings, which would hide the target.
+func newClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   dialPublic,
+	}).DialContext
+	return &http.Client{Timeout: 10 * time.Second, Transport: transport}
+}
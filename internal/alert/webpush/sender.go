@@ -0,0 +1,162 @@
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DefaultTTL is how long push services hold a message for an offline
+// browser; a live notice is stale once the stream is likely over.
+const DefaultTTL = 4 * time.Hour
+
+// Message is the JSON payload the service worker shows as a notification.
+type Message struct {
+	Title string `json:"title"`
+	Body  string `json:"body,omitempty"`
+	// URL is opened when the notification is clicked.
+	URL  string `json:"url,omitempty"`
+	Icon string `json:"icon,omitempty"`
+	// Tag replaces an earlier notification with the same tag.
+	Tag string `json:"tag,omitempty"`
+}
+
+// Options configures a Sender.
+type Options struct {
+	// Subject is the VAPID contact, a mailto: or https: URL.
+	Subject string
+	// TTL defaults to DefaultTTL.
+	TTL time.Duration
+	// Client sends pushes; it defaults to a client with a 10s timeout that
+	// only dials public addresses.
+	Client *http.Client
+}
+
+// Sender delivers messages to the browsers following a streamer.
+type Sender struct {
+	store   *Store
+	subject string
+	ttl     time.Duration
+	client  *http.Client
+	now     func() time.Time
+}
+
+// NewSender returns a Sender for the subscriptions in store.
+func NewSender(store *Store, opts Options) *Sender {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	if opts.Client == nil {
+		opts.Client = newClient()
+	}
+	return &Sender{store: store, subject: opts.Subject, ttl: opts.TTL, client: opts.Client, now: time.Now}
+}
+
+// Store returns the sender's subscription store; it is nil for a nil Sender.
+func (s *Sender) Store() *Store {
+	if s == nil {
+		return nil
+	}
+	return s.store
+}
+
+// Result counts the outcome of a Notify call.
+type Result struct {
+	Sent   int
+	Pruned int
+	Failed int
+}
+
+// Notify pushes msg to every subscription following streamerID.
+// Subscriptions whose endpoint answers 404 or 410 have expired or been
+// revoked and are removed; other failures are returned joined.
+func (s *Sender) Notify(ctx context.Context, streamerID string, msg Message) (Result, error) {
+	if s == nil {
+		return Result{}, nil
+	}
+	followers, err := s.store.Followers(streamerID)
+	if err != nil || len(followers) == 0 {
+		return Result{}, err
+	}
+	key, err := s.store.Key()
+	if err != nil {
+		return Result{}, err
+	}
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return Result{}, fmt.Errorf("encode push message: %w", err)
+	}
+
+	var (
+		result Result
+		gone   []string
+		errs   []error
+	)
+	for _, sub := range followers {
+		status, err := s.send(ctx, key, sub, payload)
+		switch {
+		case status == http.StatusNotFound || status == http.StatusGone:
+			gone = append(gone, sub.Endpoint)
+		case err != nil:
+			result.Failed++
+			errs = append(errs, err)
+		default:
+			result.Sent++
+		}
+	}
+	if len(gone) > 0 {
+		if err := s.store.Remove(gone...); err != nil && !errors.Is(err, ErrNotFound) {
+			errs = append(errs, err)
+		} else {
+			result.Pruned = len(gone)
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// send posts one encrypted message and returns the push service's status.
+func (s *Sender) send(ctx context.Context, key *VAPIDKey, sub Subscription, payload []byte) (int, error) {
+	body, err := Encrypt(sub, payload)
+	if err != nil {
+		return 0, err
+	}
+	auth, err := key.Authorization(sub.Endpoint, s.subject, s.now())
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(int(s.ttl/time.Second)))
+	req.Header.Set("Urgency", "high")
+	req.Header.Set("Authorization", auth)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("push to %s: %w", redact(sub.Endpoint), err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("push to %s: unexpected status %d", redact(sub.Endpoint), resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// redact trims an endpoint to its host; the path identifies the browser.
+func redact(endpoint string) string {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Host == "" {
+		return "push endpoint"
+	}
+	return parsed.Host
+}
@@ -0,0 +1,300 @@
+// Package webpush sends browser push notifications (RFC 8030) to viewers
+// who follow streamers. Messages are encrypted per RFC 8291 and signed with
+// the site's VAPID key (RFC 8292). The key and every subscription are
+// persisted together in one JSON file per site.
+package webpush
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultFilePath is where push state is stored when no path is given.
+	DefaultFilePath = "data/push.json"
+	// MaxFollows bounds how many streamers one subscription can follow.
+	MaxFollows = 200
+	// MaxSubscriptions bounds how many browsers one site keeps, since
+	// anyone can subscribe and the file is rewritten on every change.
+	MaxSubscriptions = 10000
+)
+
+var (
+	// ErrNotFound is returned when a subscription endpoint cannot be located.
+	ErrNotFound = errors.New("push subscription not found")
+	// ErrInvalidSubscription is returned for subscriptions without an https
+	// endpoint or with malformed keys.
+	ErrInvalidSubscription = errors.New("invalid push subscription")
+	// ErrFull is returned when a new subscription would exceed
+	// MaxSubscriptions.
+	ErrFull = errors.New("too many push subscriptions")
+)
+
+// Keys holds a subscription's browser keys, base64url encoded as returned
+// by PushSubscription.toJSON().
+type Keys struct {
+	// P256DH is the browser's uncompressed P-256 public key.
+	P256DH string `json:"p256dh"`
+	// Auth is the 16-byte authentication secret.
+	Auth string `json:"auth"`
+}
+
+// Subscription is one browser's push endpoint and the streamers it follows.
+type Subscription struct {
+	Endpoint  string    `json:"endpoint"`
+	Keys      Keys      `json:"keys"`
+	Streamers []string  `json:"streamers"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Follows reports whether the subscription follows streamerID.
+func (s Subscription) Follows(streamerID string) bool {
+	for _, id := range s.Streamers {
+		if strings.EqualFold(id, streamerID) {
+			return true
+		}
+	}
+	return false
+}
+
+// File is the on-disk push format.
+type File struct {
+	VAPID         *VAPIDKey      `json:"vapid,omitempty"`
+	Subscriptions []Subscription `json:"subscriptions"`
+}
+
+// Store persists a site's VAPID key and push subscriptions.
+type Store struct {
+	path string
+	mu   sync.Mutex
+	now  func() time.Time
+	// limit and checkHost default to MaxSubscriptions and checkHost; tests
+	// lower the one and relax the other for local push services.
+	limit     int
+	checkHost func(host string) error
+}
+
+// NewStore returns a file-backed push store for path.
+func NewStore(path string) *Store {
+	if path == "" {
+		path = DefaultFilePath
+	}
+	return &Store{path: filepath.Clean(path), now: time.Now, limit: MaxSubscriptions, checkHost: checkHost}
+}
+
+// Path returns the path backing the store.
+func (s *Store) Path() string {
+	if s == nil {
+		return ""
+	}
+	return s.path
+}
+
+// Key returns the site's VAPID key, generating and saving one on first use.
+func (s *Store) Key() (*VAPIDKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := readFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	if file.VAPID != nil {
+		if err := file.VAPID.parse(); err != nil {
+			return nil, err
+		}
+		return file.VAPID, nil
+	}
+	key, err := GenerateVAPIDKey()
+	if err != nil {
+		return nil, err
+	}
+	file.VAPID = key
+	if err := writeFile(s.path, file); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Subscribe saves sub, replacing the followed streamers of an existing
+// subscription with the same endpoint. Subscribing with no streamers removes
+// the subscription. A new endpoint is refused with ErrFull once the store
+// holds MaxSubscriptions.
+func (s *Store) Subscribe(sub Subscription) (Subscription, error) {
+	sub.Endpoint = strings.TrimSpace(sub.Endpoint)
+	if err := validate(sub, s.checkHost); err != nil {
+		return Subscription{}, err
+	}
+	sub.Streamers = dedupe(sub.Streamers)
+	if len(sub.Streamers) > MaxFollows {
+		return Subscription{}, fmt.Errorf("%w: follows more than %d streamers", ErrInvalidSubscription, MaxFollows)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := readFile(s.path)
+	if err != nil {
+		return Subscription{}, err
+	}
+	now := s.now().UTC()
+	sub.CreatedAt, sub.UpdatedAt = now, now
+	kept := file.Subscriptions[:0]
+	for _, existing := range file.Subscriptions {
+		if existing.Endpoint == sub.Endpoint {
+			sub.CreatedAt = existing.CreatedAt
+			continue
+		}
+		kept = append(kept, existing)
+	}
+	if len(sub.Streamers) > 0 {
+		if len(kept) == len(file.Subscriptions) && len(kept) >= s.limit {
+			return Subscription{}, ErrFull
+		}
+		kept = append(kept, sub)
+	}
+	file.Subscriptions = kept
+	if err := writeFile(s.path, file); err != nil {
+		return Subscription{}, err
+	}
+	return sub, nil
+}
+
+// Remove deletes the subscriptions for the given endpoints.
+func (s *Store) Remove(endpoints ...string) error {
+	if len(endpoints) == 0 {
+		return nil
+	}
+	drop := make(map[string]bool, len(endpoints))
+	for _, endpoint := range endpoints {
+		drop[endpoint] = true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := readFile(s.path)
+	if err != nil {
+		return err
+	}
+	kept := file.Subscriptions[:0]
+	for _, sub := range file.Subscriptions {
+		if !drop[sub.Endpoint] {
+			kept = append(kept, sub)
+		}
+	}
+	if len(kept) == len(file.Subscriptions) {
+		return ErrNotFound
+	}
+	file.Subscriptions = kept
+	return writeFile(s.path, file)
+}
+
+// Subscriptions returns every subscription, oldest first.
+func (s *Store) Subscriptions() ([]Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := readFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	subs := file.Subscriptions
+	sort.SliceStable(subs, func(i, j int) bool { return subs[i].CreatedAt.Before(subs[j].CreatedAt) })
+	return subs, nil
+}
+
+// Followers returns the subscriptions following streamerID.
+func (s *Store) Followers(streamerID string) ([]Subscription, error) {
+	subs, err := s.Subscriptions()
+	if err != nil {
+		return nil, err
+	}
+	var followers []Subscription
+	for _, sub := range subs {
+		if sub.Follows(streamerID) {
+			followers = append(followers, sub)
+		}
+	}
+	return followers, nil
+}
+
+// validate checks sub's endpoint and keys. checkHost vets the endpoint host;
+// it is nil when only the keys matter.
+func validate(sub Subscription, checkHost func(string) error) error {
+	parsed, err := url.Parse(sub.Endpoint)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return fmt.Errorf("%w: endpoint must be an https URL", ErrInvalidSubscription)
+	}
+	if checkHost != nil {
+		if err := checkHost(parsed.Hostname()); err != nil {
+			return err
+		}
+	}
+	if key, err := decodeKey(sub.Keys.P256DH); err != nil || len(key) != 65 || key[0] != 4 {
+		return fmt.Errorf("%w: p256dh must be an uncompressed P-256 key", ErrInvalidSubscription)
+	}
+	if auth, err := decodeKey(sub.Keys.Auth); err != nil || len(auth) != 16 {
+		return fmt.Errorf("%w: auth must be 16 bytes", ErrInvalidSubscription)
+	}
+	return nil
+}
+
+func dedupe(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		key := strings.ToLower(id)
+		if id == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, id)
+	}
+	return out
+}
+
+// decodeKey accepts base64url with or without padding, as browsers differ.
+func decodeKey(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(strings.TrimSpace(value), "="))
+}
+
+func readFile(path string) (File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return File{}, nil
+		}
+		return File{}, err
+	}
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return File{}, fmt.Errorf("decode push file: %w", err)
+	}
+	return file, nil
+}
+
+func writeFile(path string, file File) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create push dir: %w", err)
+	}
+	if file.Subscriptions == nil {
+		file.Subscriptions = []Subscription{}
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode push file: %w", err)
+	}
+	// The VAPID private key and subscription secrets are stored in this
+	// file, so keep it private to the server.
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write push file: %w", err)
+	}
+	return nil
+}
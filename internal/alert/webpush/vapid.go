@@ -0,0 +1,110 @@
+package webpush
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// vapidLifetime is how long a VAPID token is valid; push services reject
+// tokens that expire more than 24 hours ahead.
+const vapidLifetime = 12 * time.Hour
+
+// VAPIDKey is a site's application server key pair (RFC 8292).
+type VAPIDKey struct {
+	// PublicKey is the uncompressed P-256 point, base64url encoded, which
+	// browsers pass to pushManager.subscribe as applicationServerKey.
+	PublicKey string `json:"publicKey"`
+	// PrivateKey is the raw P-256 scalar, base64url encoded.
+	PrivateKey string `json:"privateKey"`
+
+	private *ecdsa.PrivateKey
+}
+
+// GenerateVAPIDKey creates a new P-256 key pair.
+func GenerateVAPIDKey() (*VAPIDKey, error) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate vapid key: %w", err)
+	}
+	public, err := private.PublicKey.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("encode vapid public key: %w", err)
+	}
+	scalar, err := private.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("encode vapid private key: %w", err)
+	}
+	return &VAPIDKey{
+		PublicKey:  base64.RawURLEncoding.EncodeToString(public),
+		PrivateKey: base64.RawURLEncoding.EncodeToString(scalar),
+		private:    private,
+	}, nil
+}
+
+// parse loads the private key and checks it matches the public key.
+func (k *VAPIDKey) parse() error {
+	if k.private != nil {
+		return nil
+	}
+	scalar, err := decodeKey(k.PrivateKey)
+	if err != nil {
+		return fmt.Errorf("decode vapid private key: %w", err)
+	}
+	private, err := ecdsa.ParseRawPrivateKey(elliptic.P256(), scalar)
+	if err != nil {
+		return fmt.Errorf("parse vapid private key: %w", err)
+	}
+	public, err := private.PublicKey.Bytes()
+	if err != nil {
+		return fmt.Errorf("encode vapid public key: %w", err)
+	}
+	if base64.RawURLEncoding.EncodeToString(public) != k.PublicKey {
+		return fmt.Errorf("vapid public key does not match the private key")
+	}
+	k.private = private
+	return nil
+}
+
+// Authorization returns the Authorization header value for a push to
+// endpoint: a "vapid" token signed for the endpoint's origin, naming subject
+// (a mailto: or https: contact for the push service operator).
+func (k *VAPIDKey) Authorization(endpoint, subject string, now time.Time) (string, error) {
+	if err := k.parse(); err != nil {
+		return "", err
+	}
+	target, err := url.Parse(endpoint)
+	if err != nil || target.Host == "" {
+		return "", fmt.Errorf("invalid push endpoint %q", endpoint)
+	}
+	header, _ := json.Marshal(map[string]string{"typ": "JWT", "alg": "ES256"})
+	claims := map[string]any{
+		"aud": target.Scheme + "://" + target.Host,
+		"exp": now.Add(vapidLifetime).Unix(),
+	}
+	if subject != "" {
+		claims["sub"] = subject
+	}
+	body, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("encode vapid claims: %w", err)
+	}
+	signing := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(body)
+	digest := sha256.Sum256([]byte(signing))
+	r, s, err := ecdsa.Sign(rand.Reader, k.private, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("sign vapid token: %w", err)
+	}
+	// JWS ES256 signatures are the fixed-width r and s values, not ASN.1.
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	token := signing + "." + base64.RawURLEncoding.EncodeToString(signature)
+	return "vapid t=" + token + ", k=" + k.PublicKey, nil
+}
@@ -0,0 +1,350 @@
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func b64(t *testing.T, value string) []byte {
+	t.Helper()
+	out, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		t.Fatalf("decode %q: %v", value, err)
+	}
+	return out
+}
+
+// browser plays the user agent: it owns a subscription's keys and decrypts
+// what the sender posts.
+type browser struct {
+	private *ecdh.PrivateKey
+	auth    []byte
+}
+
+func newBrowser(t *testing.T) *browser {
+	t.Helper()
+	private, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("browser key: %v", err)
+	}
+	return &browser{private: private, auth: bytes.Repeat([]byte{7}, 16)}
+}
+
+func (b *browser) subscription(endpoint string, streamers ...string) Subscription {
+	return Subscription{
+		Endpoint: endpoint,
+		Keys: Keys{
+			P256DH: base64.RawURLEncoding.EncodeToString(b.private.PublicKey().Bytes()),
+			Auth:   base64.RawURLEncoding.EncodeToString(b.auth),
+		},
+		Streamers: streamers,
+	}
+}
+
+// decrypt reverses Encrypt following RFC 8291 from the receiving side.
+func (b *browser) decrypt(t *testing.T, body []byte) []byte {
+	t.Helper()
+	if len(body) < 21 {
+		t.Fatalf("body too short: %d", len(body))
+	}
+	salt := body[:16]
+	if rs := binary.BigEndian.Uint32(body[16:20]); rs != recordSize {
+		t.Fatalf("unexpected record size %d", rs)
+	}
+	idlen := int(body[20])
+	asPublicBytes := body[21 : 21+idlen]
+	asPublic, err := ecdh.P256().NewPublicKey(asPublicBytes)
+	if err != nil {
+		t.Fatalf("sender key: %v", err)
+	}
+	shared, err := b.private.ECDH(asPublic)
+	if err != nil {
+		t.Fatalf("ecdh: %v", err)
+	}
+	info := append([]byte("WebPush: info\x00"), b.private.PublicKey().Bytes()...)
+	info = append(info, asPublicBytes...)
+	ikm, _ := hkdf.Key(sha256.New, shared, b.auth, string(info), 32)
+	cek, _ := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	nonce, _ := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	block, _ := aes.NewCipher(cek)
+	gcm, _ := cipher.NewGCM(block)
+	record, err := gcm.Open(nil, nonce, body[21+idlen:], nil)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	end := bytes.LastIndexByte(record, 0x02)
+	if end < 0 {
+		t.Fatalf("missing padding delimiter")
+	}
+	return record[:end]
+}
+
+// TestEncryptRFC8291Vector checks the worked example in RFC 8291 appendix A.
+func TestEncryptRFC8291Vector(t *testing.T) {
+	local, err := ecdh.P256().NewPrivateKey(b64(t, "yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw"))
+	if err != nil {
+		t.Fatalf("sender key: %v", err)
+	}
+	sub := Subscription{
+		Endpoint: "https://push.example.net/push/JzLQ3raZJfFBR0aqvOMsLrt54w4rJUsV",
+		Keys: Keys{
+			P256DH: "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4",
+			Auth:   "BTBZMqHH6r4Tts7J_aSIgg",
+		},
+	}
+	body, err := encrypt(sub, []byte("When I grow up, I want to be a watermelon"), local, b64(t, "DGv6ra1nlYgDCS1FRnbzlw"))
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	want := "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN"
+	if got := base64.RawURLEncoding.EncodeToString(body); got != want {
+		t.Fatalf("unexpected ciphertext\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestEncryptRoundTripAndLimits(t *testing.T) {
+	b := newBrowser(t)
+	sub := b.subscription("https://push.example.com/abc")
+	body, err := Encrypt(sub, []byte(`{"title":"hi"}`))
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if got := b.decrypt(t, body); string(got) != `{"title":"hi"}` {
+		t.Fatalf("unexpected plaintext %q", got)
+	}
+	if _, err := Encrypt(sub, make([]byte, MaxPayload+1)); !errors.Is(err, ErrPayloadTooLarge) {
+		t.Fatalf("expected ErrPayloadTooLarge, got %v", err)
+	}
+}
+
+func TestVAPIDAuthorization(t *testing.T) {
+	key, err := GenerateVAPIDKey()
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	header, err := key.Authorization("https://fcm.googleapis.com/fcm/send/abc", "mailto:admin@example.com", now)
+	if err != nil {
+		t.Fatalf("authorization: %v", err)
+	}
+	token, public, ok := strings.Cut(strings.TrimPrefix(header, "vapid t="), ", k=")
+	if !ok || public != key.PublicKey {
+		t.Fatalf("unexpected header %q", header)
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected a JWS, got %q", token)
+	}
+	var claims struct {
+		Aud string `json:"aud"`
+		Exp int64  `json:"exp"`
+		Sub string `json:"sub"`
+	}
+	if err := json.Unmarshal(b64(t, parts[1]), &claims); err != nil {
+		t.Fatalf("claims: %v", err)
+	}
+	if claims.Aud != "https://fcm.googleapis.com" || claims.Exp != now.Add(vapidLifetime).Unix() || claims.Sub != "mailto:admin@example.com" {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+	pub, err := ecdsa.ParseUncompressedPublicKey(key.private.Curve, b64(t, public))
+	if err != nil {
+		t.Fatalf("public key: %v", err)
+	}
+	signature := b64(t, parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if len(signature) != 64 || !ecdsa.Verify(pub, digest[:], new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])) {
+		t.Fatalf("signature does not verify")
+	}
+
+	// A reloaded key signs with the same identity.
+	reloaded := &VAPIDKey{PublicKey: key.PublicKey, PrivateKey: key.PrivateKey}
+	if _, err := reloaded.Authorization("https://push.example.com/x", "", now); err != nil {
+		t.Fatalf("reloaded key: %v", err)
+	}
+	mismatched := &VAPIDKey{PublicKey: key.PublicKey, PrivateKey: base64.RawURLEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))}
+	if _, err := mismatched.Authorization("https://push.example.com/x", "", now); err == nil {
+		t.Fatalf("expected a mismatched key pair to be rejected")
+	}
+}
+
+func TestStoreSubscriptions(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "push.json"))
+	b := newBrowser(t)
+
+	invalid := []Subscription{
+		b.subscription("http://push.example.com/a", "alpha"),
+		{Endpoint: "https://push.example.com/a", Keys: Keys{P256DH: "AAAA", Auth: "AAAA"}, Streamers: []string{"alpha"}},
+		b.subscription("https://localhost/a", "alpha"),
+		b.subscription("https://127.0.0.1:8443/a", "alpha"),
+		b.subscription("https://10.0.0.7/a", "alpha"),
+		b.subscription("https://169.254.169.254/latest/meta-data", "alpha"),
+		b.subscription("https://[::1]/a", "alpha"),
+		b.subscription("https://[::ffff:192.168.1.1]/a", "alpha"),
+	}
+	for _, sub := range invalid {
+		if _, err := store.Subscribe(sub); !errors.Is(err, ErrInvalidSubscription) {
+			t.Fatalf("expected %+v to be rejected, got %v", sub, err)
+		}
+	}
+
+	if _, err := store.Subscribe(b.subscription("https://push.example.com/a", "alpha", "Alpha", " bravo ")); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	followers, _ := store.Followers("ALPHA")
+	if len(followers) != 1 || len(followers[0].Streamers) != 2 {
+		t.Fatalf("unexpected followers %+v", followers)
+	}
+	// Resubscribing replaces the follow list.
+	if _, err := store.Subscribe(b.subscription("https://push.example.com/a", "charlie")); err != nil {
+		t.Fatalf("resubscribe: %v", err)
+	}
+	if followers, _ := store.Followers("alpha"); len(followers) != 0 {
+		t.Fatalf("expected alpha to be unfollowed, got %+v", followers)
+	}
+	// An empty follow list unsubscribes.
+	if _, err := store.Subscribe(b.subscription("https://push.example.com/a")); err != nil {
+		t.Fatalf("unsubscribe: %v", err)
+	}
+	if subs, _ := store.Subscriptions(); len(subs) != 0 {
+		t.Fatalf("expected no subscriptions, got %+v", subs)
+	}
+
+	store.limit = 2
+	for _, endpoint := range []string{"https://push.example.com/a", "https://push.example.com/b"} {
+		if _, err := store.Subscribe(b.subscription(endpoint, "alpha")); err != nil {
+			t.Fatalf("subscribe %s: %v", endpoint, err)
+		}
+	}
+	if _, err := store.Subscribe(b.subscription("https://push.example.com/c", "alpha")); !errors.Is(err, ErrFull) {
+		t.Fatalf("expected a full store to refuse new endpoints, got %v", err)
+	}
+	if _, err := store.Subscribe(b.subscription("https://push.example.com/a", "bravo")); err != nil {
+		t.Fatalf("expected a full store to accept updates, got %v", err)
+	}
+
+	key, err := store.Key()
+	if err != nil {
+		t.Fatalf("key: %v", err)
+	}
+	again, err := NewStore(store.Path()).Key()
+	if err != nil || again.PublicKey != key.PublicKey {
+		t.Fatalf("expected the key to persist, got %+v (%v)", again, err)
+	}
+}
+
+type pushService struct {
+	mu       sync.Mutex
+	statuses map[string]int
+	bodies   map[string][]byte
+	headers  map[string]http.Header
+}
+
+func (p *pushService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bodies[r.URL.Path] = body
+	p.headers[r.URL.Path] = r.Header.Clone()
+	if status, ok := p.statuses[r.URL.Path]; ok {
+		w.WriteHeader(status)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func TestNotifyDeliversAndPrunes(t *testing.T) {
+	service := &pushService{
+		statuses: map[string]int{"/gone": http.StatusGone, "/missing": http.StatusNotFound, "/broken": http.StatusInternalServerError},
+		bodies:   map[string][]byte{},
+		headers:  map[string]http.Header{},
+	}
+	target := httptest.NewTLSServer(service)
+	defer target.Close()
+
+	store := NewStore(filepath.Join(t.TempDir(), "push.json"))
+	// The test push service listens on loopback.
+	store.checkHost = func(string) error { return nil }
+	b := newBrowser(t)
+	for _, path := range []string{"/ok", "/gone", "/missing", "/broken"} {
+		if _, err := store.Subscribe(b.subscription(target.URL+path, "alpha")); err != nil {
+			t.Fatalf("subscribe %s: %v", path, err)
+		}
+	}
+	if _, err := store.Subscribe(b.subscription(target.URL+"/other", "bravo")); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	sender := NewSender(store, Options{Subject: "mailto:admin@example.com", TTL: time.Hour, Client: target.Client()})
+	result, err := sender.Notify(context.Background(), "alpha", Message{Title: "alpha is live", URL: "https://example.com/streamers/alpha"})
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Fatalf("expected the 500 to be reported, got %v", err)
+	}
+	if result != (Result{Sent: 1, Pruned: 2, Failed: 1}) {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	service.mu.Lock()
+	body, header := service.bodies["/ok"], service.headers["/ok"]
+	_, otherNotified := service.bodies["/other"]
+	service.mu.Unlock()
+	var msg Message
+	if err := json.Unmarshal(b.decrypt(t, body), &msg); err != nil || msg.Title != "alpha is live" {
+		t.Fatalf("unexpected message %+v (%v)", msg, err)
+	}
+	if header.Get("Content-Encoding") != "aes128gcm" || header.Get("TTL") != "3600" || !strings.HasPrefix(header.Get("Authorization"), "vapid t=") {
+		t.Fatalf("unexpected headers %v", header)
+	}
+	if otherNotified {
+		t.Fatalf("expected only alpha's followers to be notified")
+	}
+
+	subs, _ := store.Subscriptions()
+	var endpoints []string
+	for _, sub := range subs {
+		endpoints = append(endpoints, strings.TrimPrefix(sub.Endpoint, target.URL))
+	}
+	if strings.Join(endpoints, ",") != "/ok,/broken,/other" {
+		t.Fatalf("expected 404/410 endpoints to be pruned, got %v", endpoints)
+	}
+}
+
+func TestDefaultClientDialsPublicAddressesOnly(t *testing.T) {
+	target := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected push to %s", r.URL)
+	}))
+	defer target.Close()
+
+	store := NewStore(filepath.Join(t.TempDir(), "push.json"))
+	store.checkHost = func(string) error { return nil }
+	b := newBrowser(t)
+	// A name can resolve to loopback after subscribing; the dialer still
+	// refuses it.
+	endpoint := strings.Replace(target.URL, "127.0.0.1", "localhost", 1) + "/rebound"
+	if _, err := store.Subscribe(b.subscription(endpoint, "alpha")); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	result, err := NewSender(store, Options{}).Notify(context.Background(), "alpha", Message{Title: "alpha is live"})
+	if err == nil || !strings.Contains(err.Error(), "non-public address") || result.Failed != 1 {
+		t.Fatalf("expected the loopback push to be refused, got %+v (%v)", result, err)
+	}
+}
@@ -34,5 +34,5 @@ func NewPlatformRow() model.PlatformFormRow {
 func validateSubmission() bool {
 	errs := ValidateSubmitForm(&state.Submit)
 	state.Submit.Errors = errs
-	return !(errs.Name || errs.Description || errs.Languages || len(errs.Platforms) > 0)
+	return !(errs.Name || errs.Description || errs.Languages || errs.ContactEmail || len(errs.Platforms) > 0)
 }
@@ -2,6 +2,7 @@ package forms
 
 import (
 	"fmt"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/mail"
 	"github.com/Its-donkey/Sharpen-live/internal/ui/model"
 	"strings"
 )
@@ -21,6 +22,11 @@ func ValidateSubmitForm(form *model.SubmitFormState) model.SubmitFormErrors {
 	if len(form.Languages) == 0 {
 		errs.Languages = true
 	}
+	if strings.TrimSpace(form.ContactEmail) != "" {
+		if _, err := mail.ParseAddress(form.ContactEmail); err != nil {
+			errs.ContactEmail = true
+		}
+	}
 	for idx, row := range form.Platforms {
 		if strings.TrimSpace(row.ChannelURL) == "" {
 			key := row.ID
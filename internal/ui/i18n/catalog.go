@@ -0,0 +1,156 @@
+// Package i18n holds the UI message catalogue and locale negotiation.
+//
+// Messages are keyed by their English source text, so templates stay
+// readable and anything without a translation falls back to English.
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SourceLocale is the language the templates and server strings are
+// written in.
+const SourceLocale = "en"
+
+// message is one translated entry. Plural entries set One for the singular
+// form; plain entries only set Other.
+type message struct {
+	One   string
+	Other string
+}
+
+func (m *message) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		m.One, m.Other = "", text
+		return nil
+	}
+	var forms struct {
+		One   string `json:"one"`
+		Other string `json:"other"`
+	}
+	if err := json.Unmarshal(data, &forms); err != nil {
+		return fmt.Errorf("message must be a string or {\"one\", \"other\"} object")
+	}
+	m.One, m.Other = forms.One, forms.Other
+	return nil
+}
+
+// Catalog maps locales to their translated messages. The zero value and a
+// nil *Catalog are usable and return the source text unchanged.
+type Catalog struct {
+	messages map[string]map[string]message
+}
+
+// NewCatalog returns an empty catalogue.
+func NewCatalog() *Catalog {
+	return &Catalog{messages: map[string]map[string]message{}}
+}
+
+// Parse merges a locale's JSON translation file into the catalogue. Entries
+// in later files replace earlier ones, which lets a site override the
+// messages it inherits from its theme parents.
+func (c *Catalog) Parse(locale string, data []byte) error {
+	var entries map[string]message
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("decode %s messages: %w", locale, err)
+	}
+	locale = Normalize(locale)
+	if c.messages == nil {
+		c.messages = map[string]map[string]message{}
+	}
+	if c.messages[locale] == nil {
+		c.messages[locale] = map[string]message{}
+	}
+	for key, msg := range entries {
+		c.messages[locale][key] = msg
+	}
+	return nil
+}
+
+// Locales returns the locales that have at least one message, sorted.
+func (c *Catalog) Locales() []string {
+	if c == nil {
+		return nil
+	}
+	out := make([]string, 0, len(c.messages))
+	for locale := range c.messages {
+		out = append(out, locale)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// lookup finds key for locale, trying the bare language ("pt" for "pt-br")
+// before giving up.
+func (c *Catalog) lookup(locale, key string) (message, bool) {
+	if c == nil {
+		return message{}, false
+	}
+	locale = Normalize(locale)
+	for locale != "" {
+		if msg, ok := c.messages[locale][key]; ok {
+			return msg, true
+		}
+		cut := strings.LastIndex(locale, "-")
+		if cut < 0 {
+			break
+		}
+		locale = locale[:cut]
+	}
+	return message{}, false
+}
+
+// Translate returns key in locale, formatted with args using fmt verbs.
+// Untranslated keys are returned as written.
+func (c *Catalog) Translate(locale, key string, args ...any) string {
+	text := key
+	if msg, ok := c.lookup(locale, key); ok && msg.Other != "" {
+		text = msg.Other
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// Plural picks the singular or plural form of a message for n and formats
+// it with n followed by args. one is the English singular and the key;
+// other is the English plural used when there is no translation.
+func (c *Catalog) Plural(locale, one, other string, n int, args ...any) string {
+	text := other
+	if isOne(locale, n) {
+		text = one
+	}
+	if msg, ok := c.lookup(locale, one); ok {
+		switch {
+		case isOne(locale, n) && msg.One != "":
+			text = msg.One
+		case msg.Other != "":
+			text = msg.Other
+		}
+	}
+	return fmt.Sprintf(text, append([]any{n}, args...)...)
+}
+
+// isOne reports whether n takes the singular form in locale. French and
+// Portuguese treat zero as singular; the other supported languages do not.
+func isOne(locale string, n int) bool {
+	switch base(locale) {
+	case "fr", "pt":
+		return n == 0 || n == 1
+	default:
+		return n == 1
+	}
+}
+
+func base(locale string) string {
+	locale = Normalize(locale)
+	if cut := strings.IndexByte(locale, '-'); cut >= 0 {
+		return locale[:cut]
+	}
+	return locale
+}
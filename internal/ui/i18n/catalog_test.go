@@ -0,0 +1,65 @@
+package i18n
+
+import "testing"
+
+func TestCatalogTranslate(t *testing.T) {
+	c := NewCatalog()
+	if err := c.Parse("fr", []byte(`{"Live": "En direct", "Streamers in %s": "Streamers en %s", "%d streamer matches": {"one": "%d streamer correspond", "other": "%d streamers correspondent"}}`)); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if err := c.Parse("fr", []byte(`{"Live": "En live"}`)); err != nil {
+		t.Fatalf("parse override: %v", err)
+	}
+
+	tests := []struct {
+		locale string
+		got    string
+		want   string
+	}{
+		{"fr", c.Translate("fr", "Live"), "En live"},
+		{"fr-CA", c.Translate("fr-CA", "Streamers in %s", "français"), "Streamers en français"},
+		{"fr missing", c.Translate("fr", "Offline"), "Offline"},
+		{"en", c.Translate("en", "Live"), "Live"},
+		{"fr zero", c.Plural("fr", "%d streamer matches", "%d streamers match", 0), "0 streamer correspond"},
+		{"fr many", c.Plural("fr", "%d streamer matches", "%d streamers match", 3), "3 streamers correspondent"},
+		{"en zero", c.Plural("en", "%d streamer matches", "%d streamers match", 0), "0 streamers match"},
+		{"en one", c.Plural("de", "%d streamer matches", "%d streamers match", 1), "1 streamer matches"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Fatalf("%s: expected %q, got %q", tt.locale, tt.want, tt.got)
+		}
+	}
+
+	var nilCatalog *Catalog
+	if got := nilCatalog.Translate("fr", "Page %d", 2); got != "Page 2" {
+		t.Fatalf("expected nil catalogue to format the source text, got %q", got)
+	}
+	if err := c.Parse("es", []byte(`{"Live": 3}`)); err == nil {
+		t.Fatalf("expected invalid message to fail")
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	supported := []string{"en", "fr", "pt-br"}
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"fr-CA,fr;q=0.9,en;q=0.8", "fr"},
+		{"de-DE,de;q=0.9,en;q=0.5", "en"},
+		{"en;q=0.2, fr;q=0.7", "fr"},
+		{"pt-PT", "pt-br"},
+		{"fr;q=0, de", ""},
+		{"*", ""},
+	}
+	for _, tt := range tests {
+		if got := Negotiate(tt.header, supported); got != tt.want {
+			t.Fatalf("%q: expected %q, got %q", tt.header, tt.want, got)
+		}
+	}
+	if got := NativeName("fr-ca"); got != "Français" {
+		t.Fatalf("unexpected native name %q", got)
+	}
+}
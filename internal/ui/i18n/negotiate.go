@@ -0,0 +1,103 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// nativeNames labels locales in their own language for language switchers.
+var nativeNames = map[string]string{
+	"de": "Deutsch",
+	"en": "English",
+	"es": "Español",
+	"fr": "Français",
+	"it": "Italiano",
+	"ja": "日本語",
+	"nl": "Nederlands",
+	"pl": "Polski",
+	"pt": "Português",
+	"sv": "Svenska",
+}
+
+// Normalize lower-cases a language tag and uses hyphens as separators, so
+// "pt_BR" and "pt-br" compare equal and can be used as path prefixes.
+func Normalize(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+}
+
+// NativeName returns the locale's name in its own language, or the tag
+// itself when it is not known.
+func NativeName(locale string) string {
+	locale = Normalize(locale)
+	if name, ok := nativeNames[locale]; ok {
+		return name
+	}
+	if name, ok := nativeNames[base(locale)]; ok {
+		return name
+	}
+	return locale
+}
+
+// Match returns the entry of supported that serves tag: an exact match, then
+// the bare language of tag ("fr" for "fr-ca"), then a regional variant of
+// it. It returns "" when nothing fits.
+func Match(tag string, supported []string) string {
+	tag = Normalize(tag)
+	if tag == "" {
+		return ""
+	}
+	for _, locale := range supported {
+		if Normalize(locale) == tag {
+			return locale
+		}
+	}
+	lang := base(tag)
+	for _, locale := range supported {
+		if Normalize(locale) == lang {
+			return locale
+		}
+	}
+	for _, locale := range supported {
+		if base(locale) == lang {
+			return locale
+		}
+	}
+	return ""
+}
+
+// Negotiate picks the best entry of supported for an Accept-Language header,
+// honouring q-values. It returns "" when the header names none of them.
+func Negotiate(header string, supported []string) string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var prefs []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		prefs = append(prefs, weighted{tag: tag, q: q})
+	}
+	sort.SliceStable(prefs, func(i, j int) bool { return prefs[i].q > prefs[j].q })
+	for _, pref := range prefs {
+		if locale := Match(pref.tag, supported); locale != "" {
+			return locale
+		}
+	}
+	return ""
+}
@@ -1,10 +1,14 @@
 package model
 
+import "time"
+
 // Platform describes a distribution channel that carries a streamer.
 type Platform struct {
 	ID         string `json:"id,omitempty"`
 	Name       string `json:"name"`
 	ChannelURL string `json:"channelUrl"`
+	// Live marks the platform the streamer is currently live on.
+	Live bool `json:"live,omitempty"`
 }
 
 // Streamer represents a Sharpen Live roster entry rendered in the public UI.
@@ -16,6 +20,14 @@ type Streamer struct {
 	StatusLabel string     `json:"statusLabel"`
 	Languages   []string   `json:"languages"`
 	Platforms   []Platform `json:"platforms"`
+	Featured    bool       `json:"featured,omitempty"`
+	Sites       []string   `json:"sites,omitempty"`
+	// SiteDescription marks a description overridden for the current site.
+	SiteDescription bool `json:"-"`
+	// Country is the streamer's ISO 3166-1 alpha-2 country code, if known.
+	Country string `json:"country,omitempty"`
+	// CreatedAt is when the streamer joined the roster.
+	CreatedAt time.Time `json:"-"`
 }
 
 // WrappedStreamers matches the JSON envelope served by the public roster API.
@@ -105,8 +117,10 @@ type SubmitFormErrors struct {
 	Name        bool
 	Description bool
 	Languages   bool
-	Platforms   map[string]PlatformFieldError
-	General     []string
+	// ContactEmail is set when the optional contact address is malformed.
+	ContactEmail bool
+	Platforms    map[string]PlatformFieldError
+	General      []string
 }
 
 // SubmitFormState represents the fully-rendered submission form state.
@@ -116,6 +130,7 @@ type SubmitFormState struct {
 	Description   string
 	Languages     []string
 	Platforms     []PlatformFormRow
+	ContactEmail  string
 	Errors        SubmitFormErrors
 	ResultMessage string
 	ResultState   string
@@ -54,9 +54,9 @@ func (s *server) handleAdminConfig(w http.ResponseWriter, r *http.Request) {
 	msg := r.URL.Query().Get("msg")
 	errMsg := r.URL.Query().Get("err")
 
-	base := s.buildBasePageData(r, "Configuration · Admin", "Platform integration configuration settings", "/admin/config")
+	base := s.buildBasePageData(r, s.t(r, "Configuration · Admin"), s.t(r, "Platform integration configuration settings"), "/admin/config")
 	base.SecondaryAction = &navAction{
-		Label: "Back to admin",
+		Label: s.t(r, "Back to admin"),
 		Href:  "/admin",
 	}
 	base.Robots = "noindex, nofollow"
@@ -70,7 +70,7 @@ func (s *server) handleAdminConfig(w http.ResponseWriter, r *http.Request) {
 	// Load configuration first to populate platform status (shown even when not logged in)
 	cfg, err := config.Load(s.configPath)
 	if err != nil {
-		data.Error = "Failed to load configuration: " + err.Error()
+		data.Error = s.t(r, "Failed to load configuration: %s", err.Error())
 	} else {
 		// Use GLOBAL platform config for display
 		// Global YouTube configuration - defaults to enabled if not set
@@ -82,7 +82,7 @@ func (s *server) handleAdminConfig(w http.ResponseWriter, r *http.Request) {
 			Enabled:      youtubeEnabled,
 			HubURL:       cfg.YouTube.HubURL,
 			CallbackURL:  cfg.YouTube.CallbackURL,
-			APIKey:       maskAPIKey(cfg.YouTube.APIKey),
+			APIKey:       s.t(r, maskAPIKey(cfg.YouTube.APIKey)),
 			LeaseSeconds: cfg.YouTube.LeaseSeconds,
 			Mode:         cfg.YouTube.Mode,
 			Verify:       cfg.YouTube.Verify,
@@ -96,21 +96,21 @@ func (s *server) handleAdminConfig(w http.ResponseWriter, r *http.Request) {
 		data.TwitchConfig = TwitchConfigDisplay{
 			Enabled:        twitchEnabled,
 			CallbackURL:    cfg.Twitch.CallbackURL,
-			ClientID:       maskAPIKey(cfg.Twitch.ClientID),
-			ClientSecret:   maskSecret(cfg.Twitch.ClientSecret),
-			EventSubSecret: maskSecret(cfg.Twitch.EventSubSecret),
+			ClientID:       s.t(r, maskAPIKey(cfg.Twitch.ClientID)),
+			ClientSecret:   s.t(r, maskSecret(cfg.Twitch.ClientSecret)),
+			EventSubSecret: s.t(r, maskSecret(cfg.Twitch.EventSubSecret)),
 		}
 
 		// Facebook configuration (placeholder for now)
 		data.FacebookConfig = PlatformConfigDisplay{
 			Enabled: false,
-			HubURL:  "Not configured",
+			HubURL:  s.t(r, "Not configured"),
 		}
 	}
 
 	token := s.adminTokenFromRequest(r)
 	if token == "" {
-		s.renderConfigPage(w, data)
+		s.renderConfigPage(w, r, data)
 		return
 	}
 
@@ -126,12 +126,12 @@ func (s *server) handleAdminConfig(w http.ResponseWriter, r *http.Request) {
 		data.YouTubeSites = youtubeConfigs
 	}
 
-	s.renderConfigPage(w, data)
+	s.renderConfigPage(w, r, data)
 }
 
-func (s *server) renderConfigPage(w http.ResponseWriter, data configPageData) {
-	tmpl, ok := s.templates["config"]
-	if !ok {
+func (s *server) renderConfigPage(w http.ResponseWriter, r *http.Request, data configPageData) {
+	tmpl := s.template(r, "config")
+	if tmpl == nil {
 		http.Error(w, "config template missing", http.StatusInternalServerError)
 		return
 	}
@@ -140,7 +140,9 @@ func (s *server) renderConfigPage(w http.ResponseWriter, data configPageData) {
 	}
 }
 
-// maskAPIKey masks an API key for display, showing only first/last few characters
+// maskAPIKey masks an API key for display, showing only first/last few
+// characters. Callers pass the result through s.t so "Not set" is translated;
+// masked keys have no catalog entry and come back unchanged.
 func maskAPIKey(apiKey string) string {
 	if apiKey == "" {
 		return "Not set"
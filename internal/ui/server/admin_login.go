@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"os"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -13,6 +12,7 @@ import (
 
 	adminauth "github.com/Its-donkey/Sharpen-live/internal/alert/admin/auth"
 	"github.com/Its-donkey/Sharpen-live/internal/alert/config"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/webhooks"
 	"github.com/Its-donkey/Sharpen-live/internal/ui/model"
 )
 
@@ -38,15 +38,27 @@ type adminPageData struct {
 	OtherSites       []SiteInfo
 	YouTubeSites     []YouTubeSiteConfig
 	IsAlertserver    bool
+	SharedRoster     bool
+	RosterTargets    []SiteInfo
+	SiteSources      []string
+	TemplateSources  []TemplateSource
+	Watch            watchStats
+	PageCache        pageCacheStats
+	Webhooks         []webhooks.Endpoint
+	WebhookLog       []webhooks.LogEntry
+	WebhookPending   int
+	WebhookEvents    []string
+	RuleTest         ruleTest
 }
 
 type adminSubmission struct {
-	ID          string
-	Alias       string
-	Description string
-	Languages   []string
-	PlatformURL string
-	SubmittedAt string
+	ID           string
+	Alias        string
+	Description  string
+	Languages    []string
+	PlatformURL  string
+	SubmittedAt  string
+	ContactEmail string
 }
 
 func (s *server) handleAdmin(w http.ResponseWriter, r *http.Request) {
@@ -57,10 +69,10 @@ func (s *server) handleAdmin(w http.ResponseWriter, r *http.Request) {
 	msg := strings.TrimSpace(r.URL.Query().Get("msg"))
 	errMsg := strings.TrimSpace(r.URL.Query().Get("err"))
 	siteName := s.siteDisplayName()
-	desc := fmt.Sprintf("%s admin dashboard for roster moderation and submissions.", siteName)
-	base := s.buildBasePageData(r, fmt.Sprintf("Admin · %s", siteName), desc, "/admin")
+	desc := s.t(r, "%s admin dashboard for roster moderation and submissions.", siteName)
+	base := s.buildBasePageData(r, s.t(r, "Admin · %s", siteName), desc, "/admin")
 	base.SecondaryAction = &navAction{
-		Label: "Back to site",
+		Label: s.t(r, "Back to site"),
 		Href:  "/",
 	}
 	base.Robots = "noindex, nofollow"
@@ -76,10 +88,29 @@ func (s *server) handleAdmin(w http.ResponseWriter, r *http.Request) {
 	}
 	token := s.adminTokenFromRequest(r)
 	if token == "" {
-		s.renderAdminPage(w, data)
+		s.renderAdminPage(w, r, data)
 		return
 	}
 	data.LoggedIn = true
+	data.SharedRoster = s.sharedRoster
+	data.Watch = s.rosterWatch.stats()
+	data.PageCache = s.pages.stats()
+	data.RuleTest = s.runRuleTest(r, time.Now())
+	if store := s.webhooks.Store(); store != nil {
+		data.WebhookEvents = webhooks.EventTypes
+		data.Webhooks, _ = store.Endpoints()
+		data.WebhookLog, _ = store.Log(webhookLogSize)
+		data.WebhookPending, _ = store.Pending()
+	}
+	for _, source := range s.templateSources {
+		source.Path = workdirRelative(source.Path)
+		data.TemplateSources = append(data.TemplateSources, source)
+	}
+	if data.IsAlertserver {
+		data.SiteSources = s.siteSources()
+	} else {
+		data.RosterTargets = s.rosterTargets()
+	}
 	ctx, cancel := context.WithTimeout(r.Context(), 12*time.Second)
 	defer cancel()
 	if s.adminSubmissions != nil {
@@ -96,6 +127,12 @@ func (s *server) handleAdmin(w http.ResponseWriter, r *http.Request) {
 			data.RosterError = err.Error()
 		} else {
 			data.Streamers = mapStreamerRecords(records)
+			if s.sharedRoster {
+				site := config.RosterSiteKey(s.siteKey)
+				for i, rec := range records {
+					data.Streamers[i].SiteDescription = rec.Overrides[site].Description != nil
+				}
+			}
 			// Sort streamers with online ones at the top
 			sort.Slice(data.Streamers, func(i, j int) bool {
 				statusOrder := map[string]int{"online": 0, "busy": 1, "offline": 2}
@@ -122,7 +159,7 @@ func (s *server) handleAdmin(w http.ResponseWriter, r *http.Request) {
 		})
 		data.YouTubeSites = youtubeConfigs
 	}
-	s.renderAdminPage(w, data)
+	s.renderAdminPage(w, r, data)
 }
 
 func (s *server) handleAdminLogin(w http.ResponseWriter, r *http.Request) {
@@ -131,17 +168,17 @@ func (s *server) handleAdminLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err := r.ParseForm(); err != nil {
-		s.redirectAdmin(w, r, "", "Invalid login form.")
+		s.redirectAdmin(w, r, "", s.t(r, "Invalid login form."))
 		return
 	}
 	email := strings.TrimSpace(r.FormValue("email"))
 	password := strings.TrimSpace(r.FormValue("password"))
 	if email == "" || password == "" {
-		s.redirectAdmin(w, r, "", "Email and password are required.")
+		s.redirectAdmin(w, r, "", s.t(r, "Email and password are required."))
 		return
 	}
 	if s.adminManager == nil {
-		s.redirectAdmin(w, r, "", "Admin login is not configured.")
+		s.redirectAdmin(w, r, "", s.t(r, "Admin login is not configured."))
 		return
 	}
 	token, err := s.adminManager.Login(email, password)
@@ -150,24 +187,24 @@ func (s *server) handleAdminLogin(w http.ResponseWriter, r *http.Request) {
 			"email": email,
 			"error": err.Error(),
 		})
-		s.redirectAdmin(w, r, "", "Invalid credentials.")
+		s.redirectAdmin(w, r, "", s.t(r, "Invalid credentials."))
 		return
 	}
 	s.logger.Info("admin", "login successful", map[string]any{
 		"email": email,
 	})
 	s.setAdminSession(w, r, token)
-	s.redirectAdmin(w, r, "Logged in successfully.", "")
+	s.redirectAdmin(w, r, s.t(r, "Logged in successfully."), "")
 }
 
 func (s *server) handleAdminLogout(w http.ResponseWriter, r *http.Request) {
 	s.clearAdminSession(w)
-	s.redirectAdmin(w, r, "Logged out.", "")
+	s.redirectAdmin(w, r, s.t(r, "Logged out."), "")
 }
 
-func (s *server) renderAdminPage(w http.ResponseWriter, data adminPageData) {
-	tmpl, ok := s.templates["admin"]
-	if !ok {
+func (s *server) renderAdminPage(w http.ResponseWriter, r *http.Request, data adminPageData) {
+	tmpl := s.template(r, "admin")
+	if tmpl == nil {
 		http.Error(w, "admin template missing", http.StatusInternalServerError)
 		return
 	}
@@ -299,16 +336,8 @@ func (v urlValues) encode() string {
 // listSiblingSites returns directories under ui/sites (based on assetsDir) excluding the default-site.
 func listSiblingSites(assetsDir string) []string {
 	root := filepath.Dir(filepath.Clean(assetsDir))
-	entries, err := os.ReadDir(root)
-	if err != nil {
-		return nil
-	}
 	var sites []string
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-		name := entry.Name()
+	for _, name := range themeFiles.subdirs(root) {
 		if strings.EqualFold(name, config.AlertserverKey) || strings.EqualFold(name, "default-site") {
 			continue
 		}
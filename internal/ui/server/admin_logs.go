@@ -75,7 +75,7 @@ func (s *server) handleLogs(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := logsPageData{
-		basePageData: s.buildBasePageData(r, "Logs - "+s.siteDisplayName(), "View application logs", "/logs"),
+		basePageData: s.buildBasePageData(r, s.t(r, "Logs - %s", s.siteDisplayName()), s.t(r, "View application logs"), "/logs"),
 		Entries:      filtered,
 		Limit:        limit,
 		Level:        level,
@@ -84,7 +84,7 @@ func (s *server) handleLogs(w http.ResponseWriter, r *http.Request) {
 		Levels:       mapKeys(levels),
 	}
 
-	tmpl := s.templates["logs"]
+	tmpl := s.template(r, "logs")
 	if tmpl == nil {
 		// Render inline HTML if template not found
 		s.renderLogsHTML(w, data)
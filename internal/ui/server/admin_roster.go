@@ -0,0 +1,151 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/config"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+)
+
+// handleAdminStreamerSites copies or moves a streamer to another site. With a
+// shared roster only the record's site list changes, so the channel keeps a
+// single subscription; separate rosters get a copy of the record instead.
+func (s *server) handleAdminStreamerSites(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/admin", http.StatusSeeOther)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		s.redirectAdmin(w, r, "", s.t(r, "Invalid site request."))
+		return
+	}
+	if s.adminTokenFromRequest(r) == "" {
+		s.redirectAdmin(w, r, "", s.t(r, "Log in to manage streamer sites."))
+		return
+	}
+	id := strings.TrimSpace(r.FormValue("id"))
+	target := strings.TrimSpace(r.FormValue("target"))
+	action := strings.ToLower(strings.TrimSpace(r.FormValue("action")))
+	if id == "" || target == "" {
+		s.redirectAdmin(w, r, "", s.t(r, "Streamer and target site are required."))
+		return
+	}
+	if action != "copy" && action != "move" {
+		s.redirectAdmin(w, r, "", s.t(r, "Unknown site action."))
+		return
+	}
+	targetSite, ok := s.rosterTarget(target)
+	if !ok {
+		s.redirectAdmin(w, r, "", s.t(r, "Unknown site %q.", target))
+		return
+	}
+	baseStore, ok := s.streamersStore.(*streamers.Store)
+	if !ok {
+		s.redirectAdmin(w, r, "", s.t(r, "Site changes are unavailable."))
+		return
+	}
+
+	var err error
+	if s.sharedRoster {
+		err = s.assignSharedRosterSite(baseStore, id, target, action)
+	} else {
+		err = s.copyToSiteRoster(baseStore, id, targetSite, action)
+	}
+	if err != nil {
+		s.logger.Warn("admin", "streamer site change failed", map[string]any{
+			"streamer_id": id,
+			"target":      target,
+			"action":      action,
+			"error":       err.Error(),
+		})
+		s.redirectAdmin(w, r, "", adminStreamersErrorMessage(err))
+		return
+	}
+	s.logger.Info("admin", "streamer site changed", map[string]any{
+		"streamer_id": id,
+		"target":      target,
+		"action":      action,
+	})
+	if action == "move" {
+		s.redirectAdmin(w, r, s.t(r, "Streamer moved to %s.", targetSite.Name), "")
+		return
+	}
+	s.redirectAdmin(w, r, s.t(r, "Streamer copied to %s.", targetSite.Name), "")
+}
+
+func (s *server) assignSharedRosterSite(store *streamers.Store, id, target, action string) error {
+	if _, err := store.Get(id); err != nil {
+		return err
+	}
+	if action == "move" {
+		_, err := store.Shared().MoveToSite(id, config.RosterSiteKey(s.siteKey), target)
+		return err
+	}
+	_, err := store.Shared().CopyToSite(id, target)
+	return err
+}
+
+func (s *server) copyToSiteRoster(store *streamers.Store, id string, target config.SiteConfig, action string) error {
+	record, err := store.Get(id)
+	if err != nil {
+		return err
+	}
+	dataDir, err := filepath.Abs(target.App.Data)
+	if err != nil {
+		return fmt.Errorf("resolve %s data dir: %w", target.Name, err)
+	}
+	targetStore := streamers.SharedStore(filepath.Join(dataDir, "streamers.json"))
+	if targetStore.Path() == store.Path() {
+		return errors.New("target site shares this roster")
+	}
+	record.Status = nil
+	if _, err := targetStore.Append(record); err != nil {
+		return err
+	}
+	if action == "move" {
+		return store.Delete(id)
+	}
+	return nil
+}
+
+// rosterTarget resolves a roster site key to its configuration.
+func (s *server) rosterTarget(key string) (config.SiteConfig, bool) {
+	cfg, err := config.Load(s.configPath)
+	if err != nil {
+		return config.SiteConfig{}, false
+	}
+	for _, site := range config.AllSites(cfg) {
+		if config.RosterSiteKey(site.Key) == key && key != config.RosterSiteKey(s.siteKey) {
+			return site, true
+		}
+	}
+	return config.SiteConfig{}, false
+}
+
+// rosterTargets lists the sites a streamer can be copied or moved to.
+func (s *server) rosterTargets() []SiteInfo {
+	cfg, err := config.Load(s.configPath)
+	if err != nil {
+		return nil
+	}
+	current := config.RosterSiteKey(s.siteKey)
+	var targets []SiteInfo
+	for _, site := range config.AllSites(cfg) {
+		key := config.RosterSiteKey(site.Key)
+		if key == current || strings.EqualFold(key, config.AlertserverKey) {
+			continue
+		}
+		name := strings.TrimSpace(site.Name)
+		if name == "" {
+			name = key
+		}
+		targets = append(targets, SiteInfo{Key: key, Name: name})
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Key < targets[j].Key })
+	return targets
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	adminauth "github.com/Its-donkey/Sharpen-live/internal/alert/admin/auth"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+)
+
+func writeRosterConfig(t *testing.T, dir string) string {
+	t.Helper()
+	path := filepath.Join(dir, "config.json")
+	data := `{
+		"sites": {
+			"sharpen-live": {"name": "Sharpen.Live", "app": {"data": "` + filepath.ToSlash(filepath.Join(dir, "sharpen")) + `"}},
+			"synth-wave": {"name": "synth.wave", "app": {"data": "` + filepath.ToSlash(filepath.Join(dir, "synth")) + `"}}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func postStreamerSites(srv *server, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/admin/streamers/sites", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(&http.Cookie{Name: adminCookieName, Value: "tok"})
+	rr := httptest.NewRecorder()
+	srv.handleAdminStreamerSites(rr, req)
+	return rr
+}
+
+func TestHandleAdminStreamerSitesSharedRoster(t *testing.T) {
+	dir := t.TempDir()
+	shared := streamers.NewStore(filepath.Join(dir, "shared.json"))
+	if _, err := shared.Append(streamers.Record{
+		Streamer: streamers.Streamer{ID: "mover", Alias: "Mover"},
+		Sites:    []string{"sharpen-live"},
+	}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		action    string
+		wantSites []string
+	}{
+		{name: "copy keeps source", action: "copy", wantSites: []string{"sharpen-live", "synth-wave"}},
+		{name: "move drops source", action: "move", wantSites: []string{"synth-wave"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer()
+			srv.adminManager = &stubAdminManager{valid: true, token: adminauth.Token{Value: "tok"}}
+			srv.configPath = writeRosterConfig(t, dir)
+			srv.siteKey = "sharpen-live"
+			srv.sharedRoster = true
+			srv.streamersStore = shared.ForSite("sharpen-live")
+
+			rr := postStreamerSites(srv, url.Values{"id": {"mover"}, "target": {"synth-wave"}, "action": {tt.action}})
+			if rr.Code != http.StatusSeeOther {
+				t.Fatalf("expected redirect, got %d", rr.Code)
+			}
+			if location := rr.Header().Get("Location"); strings.Contains(location, "err=") {
+				t.Fatalf("unexpected error redirect %q", location)
+			}
+			record, err := shared.Get("mover")
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			if strings.Join(record.Sites, ",") != strings.Join(tt.wantSites, ",") {
+				t.Fatalf("expected sites %v, got %v", tt.wantSites, record.Sites)
+			}
+		})
+	}
+}
+
+func TestHandleAdminStreamerSitesSeparateRosters(t *testing.T) {
+	dir := t.TempDir()
+	source := streamers.NewStore(filepath.Join(dir, "sharpen", "streamers.json"))
+	if _, err := source.Append(streamers.Record{Streamer: streamers.Streamer{ID: "copyme", Alias: "Copy Me"}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	srv := newTestServer()
+	srv.adminManager = &stubAdminManager{valid: true, token: adminauth.Token{Value: "tok"}}
+	srv.configPath = writeRosterConfig(t, dir)
+	srv.siteKey = "sharpen-live"
+	srv.streamersStore = source
+
+	rr := postStreamerSites(srv, url.Values{"id": {"copyme"}, "target": {"synth-wave"}, "action": {"move"}})
+	if location := rr.Header().Get("Location"); strings.Contains(location, "err=") {
+		t.Fatalf("unexpected error redirect %q", location)
+	}
+	if _, err := streamers.NewStore(filepath.Join(dir, "synth", "streamers.json")).Get("copyme"); err != nil {
+		t.Fatalf("expected record in target roster: %v", err)
+	}
+	if _, err := source.Get("copyme"); err == nil {
+		t.Fatalf("expected record removed from source after move")
+	}
+
+	rr = postStreamerSites(srv, url.Values{"id": {"copyme"}, "target": {"unknown"}, "action": {"copy"}})
+	if location := rr.Header().Get("Location"); !strings.Contains(location, "err=") {
+		t.Fatalf("expected error for unknown site, got %q", location)
+	}
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/notifier"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/rules"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+)
+
+// twitchStreamTypes are the stream types offered by the rule tester.
+var twitchStreamTypes = []string{"live", "rerun", "premiere", "playlist", "watch_party"}
+
+// ruleTest is the admin rule tester: a rule and a sample live event, and the
+// outcome of running one against the other.
+type ruleTest struct {
+	Rule      string
+	Streamer  string
+	Platform  string
+	Languages string
+	Type      string
+	Title     string
+	Tags      string
+	Time      string
+	Types     []string
+	// Ran is set once the form has been submitted.
+	Ran      bool
+	Error    string
+	Matched  bool
+	Terms    []rules.Result
+	Channels []notifier.Route
+}
+
+// runRuleTest evaluates the sample event from the rule tester form against
+// its rule and the site's announcement channels. It is a GET form, so the
+// sample stays filled in.
+func (s *server) runRuleTest(r *http.Request, now time.Time) ruleTest {
+	query := r.URL.Query()
+	test := ruleTest{
+		Rule:      strings.TrimSpace(query.Get("rule")),
+		Streamer:  strings.TrimSpace(query.Get("rule_streamer")),
+		Platform:  strings.TrimSpace(query.Get("rule_platform")),
+		Languages: strings.TrimSpace(query.Get("rule_languages")),
+		Type:      strings.TrimSpace(query.Get("rule_type")),
+		Title:     strings.TrimSpace(query.Get("rule_title")),
+		Tags:      strings.TrimSpace(query.Get("rule_tags")),
+		Time:      strings.TrimSpace(query.Get("rule_time")),
+		Types:     twitchStreamTypes,
+		Ran:       query.Has("rule"),
+	}
+	if test.Platform == "" {
+		test.Platform = "twitch"
+	}
+	if test.Type == "" {
+		test.Type = "live"
+	}
+	if !test.Ran {
+		return test
+	}
+	a := notifier.Announcement{
+		Alias:      test.Streamer,
+		Platform:   test.Platform,
+		Languages:  splitList(test.Languages),
+		StreamType: test.Type,
+		Title:      test.Title,
+		Tags:       splitList(test.Tags),
+		StartedAt:  now.UTC(),
+	}
+	// The sample time is read in the rule's zone, so it lines up with its
+	// time terms.
+	rule, ruleErr := rules.Parse(test.Rule)
+	if test.Time != "" {
+		clock, err := time.Parse("15:04", test.Time)
+		if err != nil {
+			test.Error = s.t(r, "Sample time must look like 18:30.")
+			return test
+		}
+		day := now.In(rule.Location())
+		a.StartedAt = time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, day.Location()).UTC()
+	}
+	if record, ok := s.ruleTestStreamer(test.Streamer); ok {
+		a.StreamerID = record.Streamer.ID
+		a.Alias = record.Streamer.Alias
+		if len(a.Languages) == 0 {
+			a.Languages = record.Streamer.Languages
+		}
+	}
+	test.Channels = s.announcer.Routes(a)
+	if ruleErr != nil {
+		test.Error = ruleErr.Error()
+		return test
+	}
+	event := a.Event(now)
+	test.Matched = rule.Match(event)
+	test.Terms = rule.Explain(event)
+	return test
+}
+
+// ruleTestStreamer finds the roster streamer the tester sample names by alias
+// or ID.
+func (s *server) ruleTestStreamer(name string) (streamers.Record, bool) {
+	if name == "" || s.streamersStore == nil {
+		return streamers.Record{}, false
+	}
+	records, err := s.streamersStore.List()
+	if err != nil {
+		return streamers.Record{}, false
+	}
+	for _, record := range records {
+		if strings.EqualFold(record.Streamer.Alias, name) || record.Streamer.ID == name {
+			return record, true
+		}
+	}
+	return streamers.Record{}, false
+}
+
+func splitList(value string) []string {
+	var out []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	adminauth "github.com/Its-donkey/Sharpen-live/internal/alert/admin/auth"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/notifier"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+)
+
+func TestAdminRuleTester(t *testing.T) {
+	announcer, err := notifier.New([]notifier.Channel{
+		{Name: "spanish", Kind: notifier.KindDiscord, URL: "https://discord.example/api/webhooks/1/token", Rule: "language:Spanish"},
+		{Name: "main", Kind: notifier.KindSlack, URL: "https://hooks.slack.example/T/B/token", Rule: "-type:rerun"},
+	}, notifier.Options{})
+	if err != nil {
+		t.Fatalf("new announcer: %v", err)
+	}
+	srv := newTestServer()
+	srv.announcer = announcer
+	srv.adminManager = &stubAdminManager{valid: true, token: adminauth.Token{Value: "token"}}
+	srv.streamersStore = &stubStreamersStore{records: []streamers.Record{
+		{Streamer: streamers.Streamer{ID: "a1", Alias: "Alpha", Languages: []string{"Spanish"}}},
+	}}
+	sites := filepath.Join("..", "..", "..", "ui", "sites")
+	templates, _, err := loadTemplates(filepath.Join(sites, "synth-wave", "templates"), filepath.Join(sites, "default-site", "templates"))
+	if err != nil {
+		t.Fatalf("load templates: %v", err)
+	}
+	srv.templates = templates
+
+	get := func(query url.Values) ruleTest {
+		req := httptest.NewRequest(http.MethodGet, "/admin?"+query.Encode(), nil)
+		req.AddCookie(&http.Cookie{Name: adminCookieName, Value: "token"})
+		rr := httptest.NewRecorder()
+		srv.handleAdmin(rr, req)
+		if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "Announcement rules") {
+			t.Fatalf("unexpected admin page %d", rr.Code)
+		}
+		return srv.runRuleTest(req, time.Now())
+	}
+
+	test := get(url.Values{"rule": {"language:Spanish -type:rerun"}, "rule_streamer": {"alpha"}, "rule_type": {"rerun"}})
+	if test.Error != "" || test.Matched {
+		t.Fatalf("expected the rerun to fail the rule, got %+v", test)
+	}
+	if len(test.Terms) != 2 || !test.Terms[0].Matched || test.Terms[1].Matched {
+		t.Fatalf("expected the language from the roster and the type to fail, got %+v", test.Terms)
+	}
+	if len(test.Channels) != 2 || !test.Channels[0].Matched || test.Channels[1].Matched {
+		t.Fatalf("expected only the Spanish channel to announce, got %+v", test.Channels)
+	}
+
+	if test := get(url.Values{"rule": {"colour:red"}}); !strings.Contains(test.Error, "unknown field") {
+		t.Fatalf("expected a parse error, got %+v", test)
+	}
+	if test := get(url.Values{"rule": {""}, "rule_time": {"late"}}); test.Error == "" {
+		t.Fatalf("expected a sample time error, got %+v", test)
+	}
+}
+
+func TestRuleTesterReadsTimeInRuleZone(t *testing.T) {
+	srv := newTestServer()
+	// Midday in January, when Madrid is an hour ahead of UTC.
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	run := func(rule, at string) ruleTest {
+		query := url.Values{"rule": {rule}, "rule_time": {at}}
+		return srv.runRuleTest(httptest.NewRequest(http.MethodGet, "/admin?"+query.Encode(), nil), now)
+	}
+	if test := run("time:18:00-19:00 tz:Europe/Madrid", "18:30"); test.Error != "" || !test.Matched {
+		t.Fatalf("expected 18:30 Madrid time to match, got %+v", test)
+	}
+	if test := run("time:18:00-19:00", "18:30"); !test.Matched {
+		t.Fatalf("expected 18:30 UTC to match without a tz term, got %+v", test)
+	}
+	if test := run("time:17:00-18:00", "18:30"); test.Matched {
+		t.Fatalf("expected 18:30 UTC to miss an earlier window, got %+v", test)
+	}
+}
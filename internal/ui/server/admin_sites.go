@@ -0,0 +1,219 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/config"
+)
+
+// siteKeyPattern keeps scaffolded site keys safe to use as directory names.
+var siteKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// siteScaffold describes a site requested through the admin wizard.
+type siteScaffold struct {
+	Key         string
+	Name        string
+	Description string
+	Source      string
+	Data        string
+	Addr        string
+	Port        string
+	Hosts       []string
+}
+
+// handleAdminSiteCreate scaffolds a new site from an existing one and starts
+// it in the running process. Only the control room owner can create sites.
+func (s *server) handleAdminSiteCreate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/admin", http.StatusSeeOther)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		s.redirectAdmin(w, r, "", s.t(r, "Invalid site request."))
+		return
+	}
+	if s.adminTokenFromRequest(r) == "" {
+		s.redirectAdmin(w, r, "", s.t(r, "Log in to create sites."))
+		return
+	}
+	if !s.isAlertserver() {
+		s.redirectAdmin(w, r, "", s.t(r, "Sites can only be created from the control room."))
+		return
+	}
+	req := siteScaffold{
+		Key:         r.FormValue("key"),
+		Name:        r.FormValue("name"),
+		Description: r.FormValue("description"),
+		Source:      r.FormValue("source"),
+		Data:        r.FormValue("data"),
+		Addr:        r.FormValue("addr"),
+		Port:        r.FormValue("port"),
+		Hosts:       strings.FieldsFunc(r.FormValue("hosts"), func(c rune) bool { return c == ',' || c == ' ' || c == '\n' }),
+	}
+	site, err := s.scaffoldSite(req)
+	if err != nil {
+		s.logger.Warn("admin", "site scaffold failed", map[string]any{
+			"key":    req.Key,
+			"source": req.Source,
+			"error":  err.Error(),
+		})
+		s.redirectAdmin(w, r, "", err.Error())
+		return
+	}
+	listen := joinListenAddr(site.Server.Addr, site.Server.Port)
+	s.logger.Info("admin", "site scaffolded", map[string]any{
+		"key":    site.Key,
+		"source": req.Source,
+		"listen": listen,
+	})
+	if s.launchSite != nil {
+		if err := s.launchSite(site.Key); err != nil {
+			s.logger.Error("admin", "failed to start scaffolded site", err, map[string]any{
+				"key": site.Key,
+			})
+			s.redirectAdmin(w, r, "", s.t(r, "Site %s was saved but failed to start: %v", site.Name, err))
+			return
+		}
+	}
+	s.redirectAdmin(w, r, s.t(r, "Site %s created on %s.", site.Name, listen), "")
+}
+
+// scaffoldSite copies the source site's templates and assets, validates the
+// listen address and records the site in config. Copied files are removed if
+// the config cannot be saved.
+func (s *server) scaffoldSite(req siteScaffold) (config.SiteConfig, error) {
+	key := strings.ToLower(strings.TrimSpace(req.Key))
+	name := strings.TrimSpace(req.Name)
+	source := strings.TrimSpace(req.Source)
+	if !siteKeyPattern.MatchString(key) || strings.EqualFold(key, config.AlertserverKey) || strings.EqualFold(key, "default-site") {
+		return config.SiteConfig{}, errors.New("site key must use lowercase letters, digits and dashes")
+	}
+	if name == "" {
+		return config.SiteConfig{}, errors.New("site name is required")
+	}
+	if !containsString(s.siteSources(), source) {
+		return config.SiteConfig{}, fmt.Errorf("unknown source site %q", source)
+	}
+
+	cfg, err := config.Load(s.configPath)
+	if err != nil {
+		return config.SiteConfig{}, fmt.Errorf("load config: %w", err)
+	}
+	if _, exists := cfg.Sites[key]; exists {
+		return config.SiteConfig{}, fmt.Errorf("site %q already exists", key)
+	}
+
+	addr := strings.TrimSpace(req.Addr)
+	if addr == "" {
+		addr = cfg.Server.Addr
+	}
+	port := strings.TrimSpace(req.Port)
+	if port == "" {
+		return config.SiteConfig{}, errors.New("listen port is required")
+	}
+	if !strings.HasPrefix(port, ":") {
+		port = ":" + port
+	}
+	listen := joinListenAddr(addr, port)
+	if err := checkListenAddr(cfg, listen); err != nil {
+		return config.SiteConfig{}, err
+	}
+
+	sitesRoot := filepath.Dir(filepath.Clean(s.assetsDir))
+	target := filepath.Join(sitesRoot, key)
+	if themeFiles.exists(target) {
+		return config.SiteConfig{}, fmt.Errorf("site directory %s already exists", workdirRelative(target))
+	}
+	if err := themeFiles.copyDir(filepath.Join(sitesRoot, source), target); err != nil {
+		_ = themeFiles.removeAll(target)
+		return config.SiteConfig{}, fmt.Errorf("copy %s: %w", source, err)
+	}
+
+	data := strings.TrimSpace(req.Data)
+	if data == "" {
+		data = filepath.ToSlash(filepath.Join("data", key))
+	}
+	site := config.SiteConfig{
+		Key:         key,
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		Server: config.ServerConfig{
+			Addr:  addr,
+			Port:  port,
+			Hosts: normaliseHosts(req.Hosts),
+		},
+		App: config.AppConfig{
+			Templates: workdirRelative(filepath.Join(target, "templates")),
+			Assets:    workdirRelative(target),
+			Data:      data,
+			Name:      name,
+		},
+	}
+	if cfg.Sites == nil {
+		cfg.Sites = map[string]config.SiteConfig{}
+	}
+	cfg.Sites[key] = site
+	if err := config.Save(cfg, s.configPath); err != nil {
+		_ = os.RemoveAll(target)
+		return config.SiteConfig{}, err
+	}
+	return site, nil
+}
+
+// siteSources lists the site directories a new site can be copied from.
+func (s *server) siteSources() []string {
+	sources := []string{"default-site"}
+	sources = append(sources, listSiblingSites(s.assetsDir)...)
+	sort.Strings(sources[1:])
+	return sources
+}
+
+// checkListenAddr rejects addresses already claimed by a configured site or
+// bound by another process.
+func checkListenAddr(cfg config.Config, listen string) error {
+	for _, site := range config.AllSites(cfg) {
+		if joinListenAddr(site.Server.Addr, site.Server.Port) == listen {
+			name := site.Name
+			if name == "" {
+				name = config.RosterSiteKey(site.Key)
+			}
+			return fmt.Errorf("%s is already used by %s", listen, name)
+		}
+	}
+	ln, err := net.Listen("tcp", listen)
+	if err != nil {
+		return fmt.Errorf("%s is not available: %w", listen, err)
+	}
+	return ln.Close()
+}
+
+// workdirRelative returns path relative to the working directory, matching
+// how config.json paths are resolved, or the absolute path when it is outside.
+func workdirRelative(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	adminauth "github.com/Its-donkey/Sharpen-live/internal/alert/admin/auth"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/config"
+)
+
+func newScaffoldServer(t *testing.T) (*server, string, *[]string) {
+	t.Helper()
+	dir := t.TempDir()
+	for _, site := range []string{"default-site", "synth-wave"} {
+		templates := filepath.Join(dir, "sites", site, "templates")
+		if err := os.MkdirAll(templates, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(templates, "home.tmpl"), []byte(site), 0o644); err != nil {
+			t.Fatalf("write template: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "sites", site, "styles.css"), []byte("body{}"), 0o644); err != nil {
+			t.Fatalf("write styles: %v", err)
+		}
+	}
+	configPath := filepath.Join(dir, "config.json")
+	data := `{"server": {"addr": "127.0.0.1", "port": ":8880"}, "sites": {"synth-wave": {"name": "synth.wave", "server": {"port": ":8881"}}}}`
+	if err := os.WriteFile(configPath, []byte(data), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	var launched []string
+	srv := newTestServer()
+	srv.adminManager = &stubAdminManager{valid: true, token: adminauth.Token{Value: "tok"}}
+	srv.siteKey = config.AlertserverKey
+	srv.assetsDir = filepath.Join(dir, "sites", "default-site")
+	srv.configPath = configPath
+	srv.launchSite = func(key string) error {
+		launched = append(launched, key)
+		return nil
+	}
+	return srv, dir, &launched
+}
+
+func postSiteCreate(srv *server, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/admin/sites/new", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(&http.Cookie{Name: adminCookieName, Value: "tok"})
+	rr := httptest.NewRecorder()
+	srv.handleAdminSiteCreate(rr, req)
+	return rr
+}
+
+func freePort(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	return port
+}
+
+func TestHandleAdminSiteCreateScaffoldsAndLaunches(t *testing.T) {
+	srv, dir, launched := newScaffoldServer(t)
+	port := freePort(t)
+
+	rr := postSiteCreate(srv, url.Values{
+		"key":         {"neon-forge"},
+		"name":        {"Neon Forge"},
+		"description": {"Glowing blades"},
+		"source":      {"synth-wave"},
+		"port":        {port},
+		"hosts":       {"neon.example, www.neon.example"},
+	})
+	if location := rr.Header().Get("Location"); rr.Code != http.StatusSeeOther || strings.Contains(location, "err=") {
+		t.Fatalf("expected success redirect, got %d %q", rr.Code, location)
+	}
+
+	copied, err := os.ReadFile(filepath.Join(dir, "sites", "neon-forge", "templates", "home.tmpl"))
+	if err != nil || string(copied) != "synth-wave" {
+		t.Fatalf("expected synth-wave templates copied, got %q (%v)", copied, err)
+	}
+	cfg, err := config.Load(srv.configPath)
+	if err != nil {
+		t.Fatalf("reload config: %v", err)
+	}
+	site, ok := cfg.Sites["neon-forge"]
+	if !ok {
+		t.Fatalf("expected site saved to config, got %+v", cfg.Sites)
+	}
+	if site.Name != "Neon Forge" || site.Description != "Glowing blades" || site.App.Data != "data/neon-forge" {
+		t.Fatalf("unexpected site config: %+v", site)
+	}
+	if site.Server.Port != ":"+port || len(site.Server.Hosts) != 2 {
+		t.Fatalf("unexpected server config: %+v", site.Server)
+	}
+	if _, ok := cfg.Sites["synth-wave"]; !ok {
+		t.Fatalf("existing site dropped from config")
+	}
+	if len(*launched) != 1 || (*launched)[0] != "neon-forge" {
+		t.Fatalf("expected new site launched, got %v", *launched)
+	}
+}
+
+func TestHandleAdminSiteCreateRejectsInvalidRequests(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer busy.Close()
+	_, busyPort, _ := net.SplitHostPort(busy.Addr().String())
+
+	tests := []struct {
+		name    string
+		form    url.Values
+		siteKey string
+		wantErr string
+	}{
+		{name: "bad key", form: url.Values{"key": {"Neon Forge"}, "name": {"Neon"}, "source": {"synth-wave"}, "port": {"0"}}, wantErr: "site key"},
+		{name: "existing site", form: url.Values{"key": {"synth-wave"}, "name": {"Synth"}, "source": {"default-site"}, "port": {"0"}}, wantErr: "already exists"},
+		{name: "unknown source", form: url.Values{"key": {"neon"}, "name": {"Neon"}, "source": {"../etc"}, "port": {"0"}}, wantErr: "unknown source"},
+		{name: "port used by site", form: url.Values{"key": {"neon"}, "name": {"Neon"}, "source": {"synth-wave"}, "port": {"8881"}}, wantErr: "already used"},
+		{name: "port bound", form: url.Values{"key": {"neon"}, "name": {"Neon"}, "source": {"synth-wave"}, "port": {busyPort}}, wantErr: "not available"},
+		{name: "not control room", siteKey: "synth-wave", form: url.Values{"key": {"neon"}, "name": {"Neon"}, "source": {"synth-wave"}, "port": {"0"}}, wantErr: "control room"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, dir, launched := newScaffoldServer(t)
+			if tt.siteKey != "" {
+				srv.siteKey = tt.siteKey
+				srv.assetsDir = filepath.Join(dir, "sites", tt.siteKey)
+			}
+			rr := postSiteCreate(srv, tt.form)
+			location, _ := url.Parse(rr.Header().Get("Location"))
+			if got := location.Query().Get("err"); !strings.Contains(got, tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %q", tt.wantErr, got)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "sites", "neon")); !os.IsNotExist(err) {
+				t.Fatalf("expected no site directory, got %v", err)
+			}
+			if len(*launched) != 0 {
+				t.Fatalf("expected no launch, got %v", *launched)
+			}
+		})
+	}
+}
@@ -14,7 +14,7 @@ func (s *server) handleAdminStatusCheck(w http.ResponseWriter, r *http.Request)
 	}
 	token := s.adminTokenFromRequest(r)
 	if token == "" {
-		s.redirectAdmin(w, r, "", "Log in to refresh channel status.")
+		s.redirectAdmin(w, r, "", s.t(r, "Log in to refresh channel status."))
 		return
 	}
 	// Check if YouTube is enabled for this site
@@ -22,11 +22,11 @@ func (s *server) handleAdminStatusCheck(w http.ResponseWriter, r *http.Request)
 		s.logger.Info("admin", "YouTube disabled, skipping status check", map[string]any{
 			"siteKey": s.siteKey,
 		})
-		s.redirectAdmin(w, r, "", "YouTube is disabled for this site.")
+		s.redirectAdmin(w, r, "", s.t(r, "YouTube is disabled for this site."))
 		return
 	}
 	if s.statusChecker == nil {
-		s.redirectAdmin(w, r, "", "Status checks unavailable.")
+		s.redirectAdmin(w, r, "", s.t(r, "Status checks unavailable."))
 		return
 	}
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
@@ -37,13 +37,13 @@ func (s *server) handleAdminStatusCheck(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	msg := fmt.Sprintf("Checked %d channel(s): online %d, offline %d, updated %d, failed %d.",
+	msg := s.t(r, "Checked %d channel(s): online %d, offline %d, updated %d, failed %d.",
 		result.Checked, result.Online, result.Offline, result.Updated, result.Failed)
 
 	// If there are failures, provide detailed error information
 	var errMsg string
 	if result.Failed > 0 && len(result.FailureList) > 0 {
-		errMsg = "Status check failures:\n"
+		errMsg = s.t(r, "Status check failures:") + "\n"
 		// Show up to 10 failures in the UI
 		displayCount := len(result.FailureList)
 		if displayCount > 10 {
@@ -56,7 +56,7 @@ func (s *server) handleAdminStatusCheck(w http.ResponseWriter, r *http.Request)
 		}
 		if len(result.FailureList) > 10 {
 			remaining := len(result.FailureList) - 10
-			errMsg += fmt.Sprintf("... and %d more failure(s). Check server logs for complete details.", remaining)
+			errMsg += s.t(r, "... and %d more failure(s). Check server logs for complete details.", remaining)
 		}
 	}
 
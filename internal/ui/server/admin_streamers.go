@@ -2,7 +2,6 @@ package server
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"strings"
 	"time"
@@ -10,6 +9,7 @@ import (
 	"github.com/Its-donkey/Sharpen-live/internal/alert/platforms/youtube/onboarding"
 	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
 	streamersvc "github.com/Its-donkey/Sharpen-live/internal/alert/streamers/service"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/webhooks"
 	"github.com/Its-donkey/Sharpen-live/internal/ui/model"
 	youtubeui "github.com/Its-donkey/Sharpen-live/internal/ui/platforms/youtube"
 )
@@ -20,12 +20,12 @@ func (s *server) handleAdminStreamerUpdate(w http.ResponseWriter, r *http.Reques
 		return
 	}
 	if err := r.ParseForm(); err != nil {
-		s.redirectAdmin(w, r, "", "Invalid update request.")
+		s.redirectAdmin(w, r, "", s.t(r, "Invalid update request."))
 		return
 	}
 	token := s.adminTokenFromRequest(r)
 	if token == "" {
-		s.redirectAdmin(w, r, "", "Log in to edit streamers.")
+		s.redirectAdmin(w, r, "", s.t(r, "Log in to edit streamers."))
 		return
 	}
 	id := strings.TrimSpace(r.FormValue("id"))
@@ -33,12 +33,13 @@ func (s *server) handleAdminStreamerUpdate(w http.ResponseWriter, r *http.Reques
 	description := strings.TrimSpace(r.FormValue("description"))
 	languages := parseLanguagesInput(r.FormValue("languages"))
 	platformURL := strings.TrimSpace(r.FormValue("platform_url"))
+	featured := r.FormValue("featured") == "true"
 	if id == "" || alias == "" || description == "" {
-		s.redirectAdmin(w, r, "", "Name and description are required.")
+		s.redirectAdmin(w, r, "", s.t(r, "Name and description are required."))
 		return
 	}
 	if s.streamerService == nil {
-		s.redirectAdmin(w, r, "", "Streamer service unavailable.")
+		s.redirectAdmin(w, r, "", s.t(r, "Streamer service unavailable."))
 		return
 	}
 	ctx, cancel := context.WithTimeout(r.Context(), 12*time.Second)
@@ -48,6 +49,8 @@ func (s *server) handleAdminStreamerUpdate(w http.ResponseWriter, r *http.Reques
 		Alias:       &alias,
 		Description: &description,
 		Languages:   &languages,
+		Featured:    &featured,
+		SiteOnly:    r.FormValue("site_only") == "true",
 	})
 	if err != nil {
 		s.logger.Warn("admin", "streamer update failed", map[string]any{
@@ -68,12 +71,12 @@ func (s *server) handleAdminStreamerUpdate(w http.ResponseWriter, r *http.Reques
 				"streamerId": id,
 				"siteKey":    s.siteKey,
 			})
-			s.redirectAdmin(w, r, "", "YouTube is disabled for this site. Enable it in settings to update platforms.")
+			s.redirectAdmin(w, r, "", s.t(r, "YouTube is disabled for this site. Enable it in settings to update platforms."))
 			return
 		}
 		baseStore, ok := s.streamersStore.(*streamers.Store)
 		if !ok {
-			s.redirectAdmin(w, r, "", "Platform updates are unavailable.")
+			s.redirectAdmin(w, r, "", s.t(r, "Platform updates are unavailable."))
 			return
 		}
 		record, err := baseStore.Get(id)
@@ -86,7 +89,7 @@ func (s *server) handleAdminStreamerUpdate(w http.ResponseWriter, r *http.Reques
 			currentPlatformURL = youtubeui.ChannelURLFromPlatform(record.Platforms.YouTube)
 		}
 		if strings.EqualFold(strings.TrimSpace(currentPlatformURL), platformURL) {
-			s.redirectAdmin(w, r, "Streamer updated.", "")
+			s.redirectAdmin(w, r, s.t(r, "Streamer updated."), "")
 			return
 		}
 		ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
@@ -100,11 +103,11 @@ func (s *server) handleAdminStreamerUpdate(w http.ResponseWriter, r *http.Reques
 			Store:        baseStore,
 		})
 		if onboardErr != nil {
-			s.redirectAdmin(w, r, "", fmt.Sprintf("Failed to update platform: %v", onboardErr))
+			s.redirectAdmin(w, r, "", s.t(r, "Failed to update platform: %v", onboardErr))
 			return
 		}
 	}
-	s.redirectAdmin(w, r, "Streamer updated.", "")
+	s.redirectAdmin(w, r, s.t(r, "Streamer updated."), "")
 }
 
 func (s *server) handleAdminStreamerDelete(w http.ResponseWriter, r *http.Request) {
@@ -113,23 +116,24 @@ func (s *server) handleAdminStreamerDelete(w http.ResponseWriter, r *http.Reques
 		return
 	}
 	if err := r.ParseForm(); err != nil {
-		s.redirectAdmin(w, r, "", "Invalid delete request.")
+		s.redirectAdmin(w, r, "", s.t(r, "Invalid delete request."))
 		return
 	}
 	token := s.adminTokenFromRequest(r)
 	if token == "" {
-		s.redirectAdmin(w, r, "", "Log in to delete streamers.")
+		s.redirectAdmin(w, r, "", s.t(r, "Log in to delete streamers."))
 		return
 	}
 	id := strings.TrimSpace(r.FormValue("id"))
 	if id == "" {
-		s.redirectAdmin(w, r, "", "Missing streamer id.")
+		s.redirectAdmin(w, r, "", s.t(r, "Missing streamer id."))
 		return
 	}
 	if s.streamerService == nil {
-		s.redirectAdmin(w, r, "", "Streamer service unavailable.")
+		s.redirectAdmin(w, r, "", s.t(r, "Streamer service unavailable."))
 		return
 	}
+	deleted, found := s.streamerRecord(id)
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 	err := s.streamerService.Delete(ctx, streamersvc.DeleteRequest{ID: id})
@@ -144,7 +148,10 @@ func (s *server) handleAdminStreamerDelete(w http.ResponseWriter, r *http.Reques
 	s.logger.Info("admin", "streamer deleted", map[string]any{
 		"streamer_id": id,
 	})
-	s.redirectAdmin(w, r, "Streamer removed.", "")
+	if found {
+		s.emitWebhook(webhooks.EventStreamerDeleted, deleted, "")
+	}
+	s.redirectAdmin(w, r, s.t(r, "Streamer removed."), "")
 }
 
 func parseLanguagesInput(raw string) []string {
@@ -179,7 +186,11 @@ func mapStreamerRecords(records []streamers.Record) []model.Streamer {
 		var platforms []model.Platform
 		if yt := rec.Platforms.YouTube; yt != nil {
 			if url := youtubePlatformURL(yt, rec.Status, isLive); url != "" {
-				platforms = append(platforms, model.Platform{Name: "YouTube", ChannelURL: url})
+				platforms = append(platforms, model.Platform{
+					Name:       "YouTube",
+					ChannelURL: url,
+					Live:       rec.Status != nil && rec.Status.YouTube != nil && rec.Status.YouTube.Live,
+				})
 			}
 		}
 		if tw := rec.Platforms.Twitch; tw != nil {
@@ -187,6 +198,7 @@ func mapStreamerRecords(records []streamers.Record) []model.Streamer {
 				platforms = append(platforms, model.Platform{
 					Name:       "Twitch",
 					ChannelURL: "https://www.twitch.tv/" + username,
+					Live:       rec.Status != nil && rec.Status.Twitch != nil && rec.Status.Twitch.Live,
 				})
 			}
 		}
@@ -195,6 +207,7 @@ func mapStreamerRecords(records []streamers.Record) []model.Streamer {
 				platforms = append(platforms, model.Platform{
 					Name:       "Facebook",
 					ChannelURL: "https://www.facebook.com/" + page,
+					Live:       rec.Status != nil && rec.Status.Facebook != nil && rec.Status.Facebook.Live,
 				})
 			}
 		}
@@ -206,6 +219,10 @@ func mapStreamerRecords(records []streamers.Record) []model.Streamer {
 			StatusLabel: statusLabel,
 			Languages:   append([]string(nil), rec.Streamer.Languages...),
 			Platforms:   platforms,
+			Featured:    rec.Streamer.Featured,
+			Sites:       append([]string(nil), rec.Sites...),
+			Country:     model.CountryCode(rec.Streamer.Country),
+			CreatedAt:   rec.CreatedAt,
 		})
 	}
 	return out
@@ -26,13 +26,13 @@ func (s *server) handleAdminSubmission(w http.ResponseWriter, r *http.Request) {
 	}
 	if err := r.ParseForm(); err != nil {
 		fmt.Printf("ERROR: Failed to parse form: %v\n", err)
-		s.redirectAdmin(w, r, "", "Invalid submission request.")
+		s.redirectAdmin(w, r, "", s.t(r, "Invalid submission request."))
 		return
 	}
 	token := s.adminTokenFromRequest(r)
 	if token == "" {
 		fmt.Printf("WARNING: No admin token found, authentication required\n")
-		s.redirectAdmin(w, r, "", "Log in to moderate submissions.")
+		s.redirectAdmin(w, r, "", s.t(r, "Log in to moderate submissions."))
 		return
 	}
 	fmt.Printf("INFO: Admin token validated\n")
@@ -45,20 +45,21 @@ func (s *server) handleAdminSubmission(w http.ResponseWriter, r *http.Request) {
 
 	if id == "" || (action != "approve" && action != "reject") {
 		fmt.Printf("ERROR: Invalid form values - id or action missing/invalid\n")
-		s.redirectAdmin(w, r, "", "Choose approve or reject for a submission.")
+		s.redirectAdmin(w, r, "", s.t(r, "Choose approve or reject for a submission."))
 		return
 	}
 	if s.adminSubmissions == nil {
 		fmt.Printf("ERROR: Admin submissions service is nil\n")
-		s.redirectAdmin(w, r, "", "Submissions service unavailable.")
+		s.redirectAdmin(w, r, "", s.t(r, "Submissions service unavailable."))
 		return
 	}
 	fmt.Printf("\nINFO: Calling adminSubmissions.Process...\n")
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
-	_, err := s.adminSubmissions.Process(ctx, adminservice.ActionRequest{
+	result, err := s.adminSubmissions.Process(ctx, adminservice.ActionRequest{
 		Action: adminservice.Action(action),
 		ID:     id,
+		Reason: r.FormValue("reason"),
 	})
 	if err != nil {
 		fmt.Printf("\nERROR: adminSubmissions.Process failed: %v\n", err)
@@ -76,22 +77,24 @@ func (s *server) handleAdminSubmission(w http.ResponseWriter, r *http.Request) {
 		"submission_id": id,
 		"action":        action,
 	})
+	s.notifySubmissionDecision(result)
 	fmt.Printf("###################################\n")
 	fmt.Printf("### ADMIN SUBMISSION HANDLER END (success) ###\n")
 	fmt.Printf("###################################\n\n")
-	s.redirectAdmin(w, r, fmt.Sprintf("Submission %s.", pastTense(action)), "")
+	s.redirectAdmin(w, r, s.t(r, "Submission "+pastTense(action)+"."), "")
 }
 
 func mapAdminSubmissions(subs []submissions.Submission) []adminSubmission {
 	out := make([]adminSubmission, 0, len(subs))
 	for _, sub := range subs {
 		out = append(out, adminSubmission{
-			ID:          sub.ID,
-			Alias:       sub.Alias,
-			Description: sub.Description,
-			Languages:   append([]string(nil), sub.Languages...),
-			PlatformURL: sub.PlatformURL,
-			SubmittedAt: sub.SubmittedAt.Format("2006-01-02 15:04 MST"),
+			ID:           sub.ID,
+			Alias:        sub.Alias,
+			Description:  sub.Description,
+			Languages:    append([]string(nil), sub.Languages...),
+			PlatformURL:  sub.PlatformURL,
+			SubmittedAt:  sub.SubmittedAt.Format("2006-01-02 15:04 MST"),
+			ContactEmail: sub.ContactEmail,
 		})
 	}
 	return out
@@ -68,18 +68,18 @@ func (s *server) handleAdminYouTubeSettings(w http.ResponseWriter, r *http.Reque
 	}
 
 	if err := r.ParseForm(); err != nil {
-		s.redirectAdmin(w, r, "", "Invalid request.")
+		s.redirectAdmin(w, r, "", s.t(r, "Invalid request."))
 		return
 	}
 
 	token := s.adminTokenFromRequest(r)
 	if token == "" {
-		s.redirectAdmin(w, r, "", "Log in to modify YouTube settings.")
+		s.redirectAdmin(w, r, "", s.t(r, "Log in to modify YouTube settings."))
 		return
 	}
 
 	if !s.adminManager.Validate(token) {
-		s.redirectAdmin(w, r, "", "Invalid session. Please log in again.")
+		s.redirectAdmin(w, r, "", s.t(r, "Invalid session. Please log in again."))
 		return
 	}
 
@@ -90,7 +90,7 @@ func (s *server) handleAdminYouTubeSettings(w http.ResponseWriter, r *http.Reque
 	// Load config
 	cfg, err := config.Load(s.configPath)
 	if err != nil {
-		s.redirectAdmin(w, r, "", fmt.Sprintf("Failed to load config: %v", err))
+		s.redirectAdmin(w, r, "", s.t(r, "Failed to load config: %v", err))
 		return
 	}
 
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/config"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/notifier"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/webhooks"
+)
+
+// announceTimeout bounds one announcement across all of a site's channels.
+const announceTimeout = 30 * time.Second
+
+// announcementChannels converts the site's configured chat channels.
+func announcementChannels(configs []config.AnnouncementConfig) []notifier.Channel {
+	channels := make([]notifier.Channel, 0, len(configs))
+	for _, cfg := range configs {
+		channels = append(channels, notifier.Channel{
+			Name:     cfg.Name,
+			Kind:     cfg.Type,
+			URL:      cfg.URL,
+			Room:     cfg.Room,
+			Token:    cfg.Token,
+			Template: cfg.Template,
+			Cooldown: time.Duration(cfg.CooldownSeconds) * time.Second,
+			Rule:     cfg.Rule,
+		})
+	}
+	return channels
+}
+
+// announcement describes record's stream on platform for chat channels.
+func (s *server) announcement(record streamers.Record, platform string) notifier.Announcement {
+	a := notifier.Announcement{
+		StreamerID:  record.Streamer.ID,
+		Alias:       feedStreamerName(record),
+		Description: record.Streamer.Description,
+		Platform:    platform,
+		PageURL:     s.absoluteURL(nil, feedStreamerPath(record)),
+		Site:        s.siteName,
+		Languages:   record.Streamer.Languages,
+	}
+	switch platform {
+	case "youtube":
+		a.PlatformName = "YouTube"
+		a.URL = youtubePlatformURL(record.Platforms.YouTube, record.Status, true)
+		a.StreamType = "live"
+		if record.Status != nil && record.Status.YouTube != nil {
+			a.StartedAt = record.Status.YouTube.StartedAt
+			a.Title = record.Status.YouTube.Title
+		}
+	case "twitch":
+		a.PlatformName = "Twitch"
+		if record.Platforms.Twitch != nil {
+			a.URL = twitchChannelURL(record.Platforms.Twitch.Username)
+		}
+		if status := record.Status; status != nil && status.Twitch != nil {
+			a.StartedAt = status.Twitch.StartedAt
+			a.StreamType = status.Twitch.Type
+			a.Title = status.Twitch.Title
+			a.Tags = status.Twitch.Tags
+		}
+	}
+	return a
+}
+
+// announce posts live events to the site's chat channels and marks their
+// messages ended on offline events. It runs in the background so slow chat
+// services cannot hold up status notifications.
+func (s *server) announce(event string, record streamers.Record, platform string) {
+	if s.announcer == nil {
+		return
+	}
+	send := s.announcer.Live
+	switch event {
+	case webhooks.EventStreamerLive:
+	case webhooks.EventStreamerOffline:
+		send = s.announcer.Ended
+	default:
+		return
+	}
+	a := s.announcement(record, platform)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), announceTimeout)
+		defer cancel()
+		if err := send(ctx, a); err != nil {
+			s.logger.Warn("announce", "Failed to send announcement", map[string]any{
+				"event":    event,
+				"streamer": record.Streamer.ID,
+				"error":    err.Error(),
+			})
+		}
+	}()
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/notifier"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+)
+
+func TestTwitchEventsAnnounce(t *testing.T) {
+	posts := make(chan map[string]any, 4)
+	discord := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		raw, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(raw, &body)
+		body["method"] = r.Method
+		posts <- body
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"m1"}`))
+	}))
+	defer discord.Close()
+
+	path := filepath.Join(t.TempDir(), "synth-wave", "streamers.json")
+	store := streamers.NewStore(path)
+	if _, err := store.Append(streamers.Record{
+		Streamer:  streamers.Streamer{ID: "a", Alias: "alpha", Languages: []string{"English"}},
+		Platforms: streamers.Platforms{Twitch: &streamers.TwitchPlatform{Username: "alpha", BroadcasterID: "42"}},
+		Sites:     []string{"synth-wave"},
+	}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	srv := newTestServer()
+	srv.siteKey = "synth-wave"
+	srv.siteName = "synth.wave"
+	srv.streamersStore = store
+	srv.storeCache = map[string]*streamers.Store{}
+	announcer, err := notifier.New([]notifier.Channel{{Kind: notifier.KindDiscord, URL: discord.URL, Rule: "-type:rerun"}}, notifier.Options{})
+	if err != nil {
+		t.Fatalf("notifier: %v", err)
+	}
+	srv.announcer = announcer
+	defer registerSite(srv)()
+
+	next := func() map[string]any {
+		t.Helper()
+		select {
+		case body := <-posts:
+			return body
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for an announcement")
+			return nil
+		}
+	}
+	notify := func(event any) EventSubNotification {
+		raw, _ := json.Marshal(event)
+		return EventSubNotification{Event: raw}
+	}
+
+	srv.handleStreamOnline(notify(StreamOnlineEvent{ID: "stream-1", BroadcasterUserID: "42", Type: "live", StartedAt: time.Now()}))
+	live := next()
+	embed := live["embeds"].([]any)[0].(map[string]any)
+	if live["method"] != http.MethodPost || embed["url"] != "https://www.twitch.tv/alpha" || embed["footer"].(map[string]any)["text"] != "synth.wave · Twitch" {
+		t.Fatalf("unexpected live announcement %v", live)
+	}
+
+	srv.handleStreamOffline(notify(StreamOfflineEvent{BroadcasterUserID: "42"}))
+	if ended := next(); ended["method"] != http.MethodPatch {
+		t.Fatalf("expected the announcement to be edited, got %v", ended)
+	}
+
+	// The channel's rule skips reruns, so the ended message stays as it is.
+	srv.handleStreamOnline(notify(StreamOnlineEvent{ID: "stream-2", BroadcasterUserID: "42", Type: "rerun", StartedAt: time.Now()}))
+	record, err := store.GetByTwitchBroadcasterID("42")
+	if err != nil || record.Status.Twitch.Type != "rerun" {
+		t.Fatalf("expected the stream type to be stored, got %+v (%v)", record.Status, err)
+	}
+	select {
+	case body := <-posts:
+		t.Fatalf("expected the rerun to be skipped, got %v", body)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
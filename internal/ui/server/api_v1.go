@@ -0,0 +1,423 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+)
+
+const (
+	defaultPublicPageSize = 50
+	maxPublicPageSize     = 200
+)
+
+// publicStreamer is the public projection of a roster record served by
+// /api/v1/streamers (see schema/public-streamers.v1.schema.json). It omits
+// subscription secrets, callback details, access tokens and personal
+// contact fields.
+type publicStreamer struct {
+	Streamer  publicProfile   `json:"streamer"`
+	Platforms publicPlatforms `json:"platforms"`
+	Status    publicStatus    `json:"status"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+type publicProfile struct {
+	ID          string   `json:"id"`
+	Alias       string   `json:"alias"`
+	Description string   `json:"description,omitempty"`
+	Country     string   `json:"country,omitempty"`
+	Languages   []string `json:"languages"`
+	Featured    bool     `json:"featured,omitempty"`
+}
+
+type publicPlatforms struct {
+	YouTube  *publicYouTube  `json:"youtube,omitempty"`
+	Twitch   *publicTwitch   `json:"twitch,omitempty"`
+	Facebook *publicFacebook `json:"facebook,omitempty"`
+}
+
+type publicYouTube struct {
+	Handle     string `json:"handle,omitempty"`
+	ChannelID  string `json:"channelId,omitempty"`
+	ChannelURL string `json:"channelUrl,omitempty"`
+}
+
+type publicTwitch struct {
+	Username string `json:"username,omitempty"`
+}
+
+type publicFacebook struct {
+	PageID string `json:"pageId,omitempty"`
+}
+
+type publicStatus struct {
+	Live      bool        `json:"live"`
+	Platforms []string    `json:"platforms,omitempty"`
+	YouTube   *publicLive `json:"youtube,omitempty"`
+	Twitch    *publicLive `json:"twitch,omitempty"`
+	Facebook  *publicLive `json:"facebook,omitempty"`
+}
+
+type publicLive struct {
+	Live      bool       `json:"live"`
+	VideoID   string     `json:"videoId,omitempty"`
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+}
+
+// publicStreamersPage is the /api/v1/streamers response envelope.
+type publicStreamersPage struct {
+	Streamers  []publicStreamer `json:"streamers"`
+	NextCursor string           `json:"nextCursor,omitempty"`
+}
+
+func publicStreamerFromRecord(record streamers.Record) publicStreamer {
+	out := publicStreamer{
+		Streamer: publicProfile{
+			ID:          record.Streamer.ID,
+			Alias:       record.Streamer.Alias,
+			Description: record.Streamer.Description,
+			Country:     record.Streamer.Country,
+			Languages:   append([]string{}, record.Streamer.Languages...),
+			Featured:    record.Streamer.Featured,
+		},
+		CreatedAt: record.CreatedAt,
+		UpdatedAt: record.UpdatedAt,
+	}
+	if yt := record.Platforms.YouTube; yt != nil {
+		out.Platforms.YouTube = &publicYouTube{Handle: yt.Handle, ChannelID: yt.ChannelID, ChannelURL: yt.ChannelURL}
+	}
+	if tw := record.Platforms.Twitch; tw != nil {
+		out.Platforms.Twitch = &publicTwitch{Username: tw.Username}
+	}
+	if fb := record.Platforms.Facebook; fb != nil {
+		out.Platforms.Facebook = &publicFacebook{PageID: fb.PageID}
+	}
+	if status := record.Status; status != nil {
+		out.Status.Live = status.Live
+		out.Status.Platforms = append([]string(nil), status.Platforms...)
+		if yt := status.YouTube; yt != nil {
+			out.Status.YouTube = &publicLive{Live: yt.Live, VideoID: yt.VideoID, StartedAt: nonZeroTime(yt.StartedAt)}
+		}
+		if tw := status.Twitch; tw != nil {
+			out.Status.Twitch = &publicLive{Live: tw.Live, StartedAt: nonZeroTime(tw.StartedAt)}
+		}
+		if fb := status.Facebook; fb != nil {
+			out.Status.Facebook = &publicLive{Live: fb.Live, VideoID: fb.VideoID, StartedAt: nonZeroTime(fb.StartedAt)}
+		}
+	}
+	return out
+}
+
+func nonZeroTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// streamerQuery holds the filters, ordering and page requested from
+// /api/v1/streamers.
+type streamerQuery struct {
+	language string
+	platform string
+	live     *bool
+	sort     string
+	limit    int
+	cursor   *streamerCursor
+}
+
+// streamerCursor marks the last item of a page. It records the sort it was
+// issued for so it cannot be replayed against a different ordering.
+type streamerCursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	ID   string `json:"id"`
+}
+
+var errInvalidQuery = errors.New("invalid query")
+
+func parseStreamerQuery(values url.Values) (streamerQuery, error) {
+	q := streamerQuery{
+		language: strings.TrimSpace(values.Get("language")),
+		platform: strings.ToLower(strings.TrimSpace(values.Get("platform"))),
+		sort:     strings.TrimSpace(values.Get("sort")),
+		limit:    defaultPublicPageSize,
+	}
+	switch q.platform {
+	case "", "youtube", "twitch", "facebook":
+	default:
+		return q, fmt.Errorf("%w: platform must be youtube, twitch or facebook", errInvalidQuery)
+	}
+	if raw := strings.TrimSpace(values.Get("live")); raw != "" {
+		live, err := strconv.ParseBool(raw)
+		if err != nil {
+			return q, fmt.Errorf("%w: live must be true or false", errInvalidQuery)
+		}
+		q.live = &live
+	}
+	if q.sort == "" {
+		q.sort = "alias"
+	}
+	switch strings.TrimPrefix(q.sort, "-") {
+	case "alias", "created", "updated":
+	default:
+		return q, fmt.Errorf("%w: sort must be alias, created or updated, optionally prefixed with -", errInvalidQuery)
+	}
+	if raw := strings.TrimSpace(values.Get("limit")); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxPublicPageSize {
+			return q, fmt.Errorf("%w: limit must be between 1 and %d", errInvalidQuery, maxPublicPageSize)
+		}
+		q.limit = limit
+	}
+	if raw := strings.TrimSpace(values.Get("cursor")); raw != "" {
+		cursor, err := decodeStreamerCursor(raw)
+		if err != nil || cursor.Sort != q.sort {
+			return q, fmt.Errorf("%w: cursor does not match this query", errInvalidQuery)
+		}
+		q.cursor = &cursor
+	}
+	return q, nil
+}
+
+func (q streamerQuery) matches(record streamers.Record) bool {
+	if q.language != "" {
+		found := false
+		for _, lang := range record.Streamer.Languages {
+			if strings.EqualFold(strings.TrimSpace(lang), q.language) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	switch q.platform {
+	case "youtube":
+		if record.Platforms.YouTube == nil {
+			return false
+		}
+	case "twitch":
+		if record.Platforms.Twitch == nil {
+			return false
+		}
+	case "facebook":
+		if record.Platforms.Facebook == nil {
+			return false
+		}
+	}
+	if q.live != nil {
+		live := record.Status != nil && record.Status.Live
+		if live != *q.live {
+			return false
+		}
+	}
+	return true
+}
+
+// sortKey returns a string that orders records lexically for the query's sort.
+func (q streamerQuery) sortKey(record streamers.Record) string {
+	switch strings.TrimPrefix(q.sort, "-") {
+	case "created":
+		return record.CreatedAt.UTC().Format("2006-01-02T15:04:05.000000000Z")
+	case "updated":
+		return record.UpdatedAt.UTC().Format("2006-01-02T15:04:05.000000000Z")
+	default:
+		return strings.ToLower(strings.TrimSpace(record.Streamer.Alias))
+	}
+}
+
+// page filters and orders records, then returns the slice after the cursor.
+// Ties on the sort key are broken by streamer ID so pages never overlap.
+func (q streamerQuery) page(records []streamers.Record) publicStreamersPage {
+	type keyed struct {
+		key    string
+		record streamers.Record
+	}
+	var items []keyed
+	for _, record := range records {
+		if q.matches(record) {
+			items = append(items, keyed{key: q.sortKey(record), record: record})
+		}
+	}
+	desc := strings.HasPrefix(q.sort, "-")
+	less := func(aKey, aID, bKey, bID string) bool {
+		if aKey != bKey {
+			return (aKey < bKey) != desc
+		}
+		if aID == bID {
+			return false
+		}
+		return (aID < bID) != desc
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return less(items[i].key, items[i].record.Streamer.ID, items[j].key, items[j].record.Streamer.ID)
+	})
+
+	start := 0
+	if q.cursor != nil {
+		start = sort.Search(len(items), func(i int) bool {
+			return less(q.cursor.Key, q.cursor.ID, items[i].key, items[i].record.Streamer.ID)
+		})
+	}
+	end := len(items)
+	if q.limit > 0 && start+q.limit < end {
+		end = start + q.limit
+	}
+
+	page := publicStreamersPage{Streamers: make([]publicStreamer, 0, end-start)}
+	for _, item := range items[start:end] {
+		page.Streamers = append(page.Streamers, publicStreamerFromRecord(item.record))
+	}
+	if end < len(items) {
+		last := items[end-1]
+		page.NextCursor = encodeStreamerCursor(streamerCursor{Sort: q.sort, Key: last.key, ID: last.record.Streamer.ID})
+	}
+	return page
+}
+
+func encodeStreamerCursor(cursor streamerCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeStreamerCursor(raw string) (streamerCursor, error) {
+	var cursor streamerCursor
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return cursor, err
+	}
+	err = json.Unmarshal(data, &cursor)
+	return cursor, err
+}
+
+// handleAPIStreamers serves GET /api/v1/streamers.
+func (s *server) handleAPIStreamers(w http.ResponseWriter, r *http.Request) {
+	if !allowRead(w, r) {
+		return
+	}
+	query, err := parseStreamerQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	records, ok := s.listPublicRecords(w)
+	if !ok {
+		return
+	}
+	writeCachedJSON(w, r, query.page(records))
+}
+
+// handleAPIStreamer serves GET /api/v1/streamers/{id}.
+func (s *server) handleAPIStreamer(w http.ResponseWriter, r *http.Request) {
+	if !allowRead(w, r) {
+		return
+	}
+	id := strings.TrimSpace(r.PathValue("id"))
+	records, ok := s.listPublicRecords(w)
+	if !ok {
+		return
+	}
+	for _, record := range records {
+		if strings.EqualFold(record.Streamer.ID, id) {
+			writeCachedJSON(w, r, publicStreamerFromRecord(record))
+			return
+		}
+	}
+	http.Error(w, "streamer not found", http.StatusNotFound)
+}
+
+// serveStreamersJSON is the deprecated /streamers.json alias. It returns the
+// whole roster in the /api/v1/streamers shape without pagination.
+func (s *server) serveStreamersJSON(w http.ResponseWriter, r *http.Request) {
+	if !allowRead(w, r) {
+		return
+	}
+	records, ok := s.listPublicRecords(w)
+	if !ok {
+		return
+	}
+	w.Header().Set("Deprecation", "true")
+	w.Header().Set("Link", `</api/v1/streamers>; rel="successor-version"`)
+	writeCachedJSON(w, r, streamerQuery{sort: "alias"}.page(records))
+}
+
+func (s *server) listPublicRecords(w http.ResponseWriter) ([]streamers.Record, bool) {
+	if s.streamersStore == nil {
+		http.Error(w, "streamers store unavailable", http.StatusInternalServerError)
+		return nil, false
+	}
+	records, err := s.streamersStore.List()
+	if err != nil {
+		http.Error(w, "failed to load streamers", http.StatusInternalServerError)
+		return nil, false
+	}
+	return records, true
+}
+
+func allowRead(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return true
+	}
+	w.Header().Set("Allow", "GET, HEAD")
+	http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	return false
+}
+
+// writeCachedJSON encodes payload with a content hash ETag and answers
+// matching If-None-Match requests with 304 Not Modified.
+func writeCachedJSON(w http.ResponseWriter, r *http.Request, payload any) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	writeCachedBody(w, r, "application/json", append(body, '\n'))
+}
+
+// writeCachedBody writes body with a content hash ETag, answering matching
+// If-None-Match requests with 304 Not Modified. Cache-Control defaults to
+// no-cache unless the caller already set it.
+func writeCachedBody(w http.ResponseWriter, r *http.Request, contentType string, body []byte) {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	if w.Header().Get("Cache-Control") == "" {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(body)
+}
+
+// etagMatches implements the weak comparison used for If-None-Match.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
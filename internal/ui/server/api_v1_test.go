@@ -0,0 +1,224 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+)
+
+func apiTestRecords() []streamers.Record {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []streamers.Record{
+		{
+			Streamer: streamers.Streamer{ID: "c", Alias: "Charlie", Email: "c@example.com", FirstName: "Carl", Languages: []string{"English"}},
+			Platforms: streamers.Platforms{
+				YouTube: &streamers.YouTubePlatform{Handle: "@charlie", ChannelID: "UC1", HubSecret: "hub-secret", WebSubSecret: "websub-secret"},
+			},
+			Status:    &streamers.Status{Live: true, Platforms: []string{"youtube"}, YouTube: &streamers.YouTubeStatus{Live: true, VideoID: "vid"}},
+			CreatedAt: base.Add(3 * time.Hour),
+		},
+		{
+			Streamer: streamers.Streamer{ID: "a", Alias: "alpha", Languages: []string{"French"}},
+			Platforms: streamers.Platforms{
+				Twitch: &streamers.TwitchPlatform{Username: "alpha", BroadcasterID: "42", EventSubOnlineID: "sub"},
+			},
+			CreatedAt: base.Add(1 * time.Hour),
+		},
+		{
+			Streamer: streamers.Streamer{ID: "b", Alias: "Bravo", Languages: []string{"english"}},
+			Platforms: streamers.Platforms{
+				Facebook: &streamers.FacebookPlatform{PageID: "page", AccessToken: "fb-token"},
+			},
+			CreatedAt: base.Add(2 * time.Hour),
+		},
+	}
+}
+
+func getAPI(t *testing.T, srv *server, target string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/streamers", srv.handleAPIStreamers)
+	mux.HandleFunc("/api/v1/streamers/{id}", srv.handleAPIStreamer)
+	mux.HandleFunc("/streamers.json", srv.serveStreamersJSON)
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	return rr
+}
+
+func decodePage(t *testing.T, rr *httptest.ResponseRecorder) publicStreamersPage {
+	t.Helper()
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var page publicStreamersPage
+	if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return page
+}
+
+func pageIDs(page publicStreamersPage) string {
+	ids := make([]string, 0, len(page.Streamers))
+	for _, s := range page.Streamers {
+		ids = append(ids, s.Streamer.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestAPIStreamersOmitsPrivateFields(t *testing.T) {
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: apiTestRecords()}
+
+	for _, target := range []string{"/api/v1/streamers", "/api/v1/streamers/c", "/streamers.json"} {
+		rr := getAPI(t, srv, target, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", target, rr.Code)
+		}
+		body := rr.Body.String()
+		for _, private := range []string{"c@example.com", "Carl", "hub-secret", "websub-secret", "fb-token", "broadcasterId", "eventsub", "sites"} {
+			if strings.Contains(body, private) {
+				t.Fatalf("%s: response leaks %q: %s", target, private, body)
+			}
+		}
+	}
+}
+
+func TestAPIStreamersFiltersAndSorts(t *testing.T) {
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: apiTestRecords()}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", "a,b,c"},
+		{"?sort=-alias", "c,b,a"},
+		{"?sort=created", "a,b,c"},
+		{"?sort=-created", "c,b,a"},
+		{"?language=ENGLISH", "b,c"},
+		{"?platform=twitch", "a"},
+		{"?live=true", "c"},
+		{"?live=false&language=english", "b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			page := decodePage(t, getAPI(t, srv, "/api/v1/streamers"+tt.query, nil))
+			if got := pageIDs(page); got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAPIStreamersRejectsBadQueries(t *testing.T) {
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: apiTestRecords()}
+
+	for _, query := range []string{"?platform=kick", "?live=maybe", "?sort=email", "?limit=0", "?limit=500", "?cursor=not-a-cursor"} {
+		if rr := getAPI(t, srv, "/api/v1/streamers"+query, nil); rr.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", query, rr.Code)
+		}
+	}
+}
+
+func TestAPIStreamersCursorPagination(t *testing.T) {
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: apiTestRecords()}
+
+	var seen []string
+	target := "/api/v1/streamers?sort=-created&limit=2"
+	for i := 0; i < 3; i++ {
+		page := decodePage(t, getAPI(t, srv, target, nil))
+		seen = append(seen, pageIDs(page))
+		if page.NextCursor == "" {
+			break
+		}
+		target = "/api/v1/streamers?sort=-created&limit=2&cursor=" + page.NextCursor
+	}
+	if got := strings.Join(seen, "|"); got != "c,b|a" {
+		t.Fatalf("expected pages c,b|a, got %s", got)
+	}
+
+	first := decodePage(t, getAPI(t, srv, "/api/v1/streamers?limit=1", nil))
+	if rr := getAPI(t, srv, "/api/v1/streamers?sort=created&cursor="+first.NextCursor, nil); rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected cursor from another sort to be rejected, got %d", rr.Code)
+	}
+}
+
+func TestAPIStreamersConditionalGet(t *testing.T) {
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: apiTestRecords()}
+
+	rr := getAPI(t, srv, "/api/v1/streamers", nil)
+	etag := rr.Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("expected ETag header")
+	}
+	rr = getAPI(t, srv, "/api/v1/streamers", http.Header{"If-None-Match": {`"other", W/` + etag}})
+	if rr.Code != http.StatusNotModified || rr.Body.Len() != 0 {
+		t.Fatalf("expected empty 304, got %d with %q", rr.Code, rr.Body.String())
+	}
+	rr = getAPI(t, srv, "/api/v1/streamers?live=true", http.Header{"If-None-Match": {etag}})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 for a different representation, got %d", rr.Code)
+	}
+}
+
+func TestAPIStreamerByID(t *testing.T) {
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: apiTestRecords()}
+
+	rr := getAPI(t, srv, "/api/v1/streamers/c", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var got publicStreamer
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.Streamer.Alias != "Charlie" || got.Platforms.YouTube == nil || got.Platforms.YouTube.ChannelID != "UC1" {
+		t.Fatalf("unexpected streamer: %+v", got)
+	}
+	if got.Status.YouTube == nil || got.Status.YouTube.VideoID != "vid" || got.Status.YouTube.StartedAt != nil {
+		t.Fatalf("unexpected status: %+v", got.Status)
+	}
+
+	if rr := getAPI(t, srv, "/api/v1/streamers/missing", nil); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+}
+
+func TestStreamersJSONIsDeprecatedAlias(t *testing.T) {
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: apiTestRecords()}
+
+	rr := getAPI(t, srv, "/streamers.json", nil)
+	page := decodePage(t, rr)
+	if got := pageIDs(page); got != "a,b,c" || page.NextCursor != "" {
+		t.Fatalf("expected full roster, got %s (cursor %q)", got, page.NextCursor)
+	}
+	if rr.Header().Get("Deprecation") == "" || !strings.Contains(rr.Header().Get("Link"), "/api/v1/streamers") {
+		t.Fatalf("expected deprecation headers, got %v", rr.Header())
+	}
+}
+
+func TestAPIStreamersRejectsWrites(t *testing.T) {
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: apiTestRecords()}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/streamers", nil)
+	rr := httptest.NewRecorder()
+	srv.handleAPIStreamers(rr, req)
+	if rr.Code != http.StatusMethodNotAllowed || rr.Header().Get("Allow") == "" {
+		t.Fatalf("expected 405 with Allow, got %d", rr.Code)
+	}
+}
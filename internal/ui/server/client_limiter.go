@@ -0,0 +1,61 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// clientLimiter allows each client a fixed number of requests per window,
+// keyed by the connecting address. A nil limiter allows everything.
+type clientLimiter struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	clients map[string]*clientWindow
+	// sweepAt is when windows that have ended are next dropped.
+	sweepAt time.Time
+}
+
+type clientWindow struct {
+	start time.Time
+	count int
+}
+
+func newClientLimiter(limit int, window time.Duration) *clientLimiter {
+	return &clientLimiter{limit: limit, window: window, now: time.Now, clients: make(map[string]*clientWindow)}
+}
+
+// allow records a request from r's client and reports whether it is within
+// the limit.
+func (l *clientLimiter) allow(r *http.Request) bool {
+	if l == nil {
+		return true
+	}
+	client := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(client); err == nil {
+		client = host
+	}
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !now.Before(l.sweepAt) {
+		for key, w := range l.clients {
+			if now.Sub(w.start) >= l.window {
+				delete(l.clients, key)
+			}
+		}
+		l.sweepAt = now.Add(l.window)
+	}
+	w, ok := l.clients[client]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &clientWindow{start: now}
+		l.clients[client] = w
+	}
+	w.count++
+	return w.count <= l.limit
+}
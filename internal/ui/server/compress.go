@@ -0,0 +1,168 @@
+package server
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// minCompressSize is the smallest declared body worth compressing.
+const minCompressSize = 512
+
+var gzipWriters = sync.Pool{
+	New: func() any { return gzip.NewWriter(io.Discard) },
+}
+
+// compressibleType reports whether a response of contentType benefits from
+// compression. Images other than SVG are already compressed, and event
+// streams are left alone so proxies deliver each message as it is flushed.
+func compressibleType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "text/"):
+		return mediaType != "text/event-stream"
+	case mediaType == "image/svg+xml":
+		return true
+	case strings.HasSuffix(mediaType, "+json"), strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/javascript", "application/xml", "application/manifest+json":
+		return true
+	}
+	return false
+}
+
+// acceptsGzip reports whether the Accept-Encoding header allows gzip. An
+// explicit gzip entry takes precedence over the * wildcard.
+func acceptsGzip(header string) bool {
+	gzipOK, wildcardOK := false, false
+	var sawGzip, sawWildcard bool
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		allowed := true
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if value, err := strconv.ParseFloat(q, 64); err == nil && value <= 0 {
+				allowed = false
+			}
+		}
+		if coding == "gzip" {
+			sawGzip, gzipOK = true, allowed
+		} else {
+			sawWildcard, wildcardOK = true, allowed
+		}
+	}
+	if sawGzip {
+		return gzipOK
+	}
+	return sawWildcard && wildcardOK
+}
+
+// compressHandler gzips text responses for clients that accept it. Whether a
+// response is compressed is decided when its headers are written, from the
+// Content-Type (sniffed from the first write when unset) and Content-Length.
+// WebSocket upgrades and HEAD requests pass through untouched.
+func compressHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead || r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cw := &compressWriter{ResponseWriter: w, accept: acceptsGzip(r.Header.Get("Accept-Encoding"))}
+		defer cw.Close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+type compressWriter struct {
+	http.ResponseWriter
+	accept      bool
+	wroteHeader bool
+	gz          *gzip.Writer
+}
+
+func (c *compressWriter) WriteHeader(status int) {
+	if c.wroteHeader {
+		return
+	}
+	c.wroteHeader = true
+	header := c.Header()
+	// Partial content is left alone: its Content-Range counts bytes of the
+	// uncompressed body.
+	eligible := status >= http.StatusOK &&
+		status != http.StatusNoContent &&
+		status != http.StatusPartialContent &&
+		status != http.StatusNotModified &&
+		header.Get("Content-Encoding") == "" &&
+		header.Get("Content-Range") == "" &&
+		compressibleType(header.Get("Content-Type"))
+	if eligible {
+		header.Add("Vary", "Accept-Encoding")
+	}
+	if length, err := strconv.Atoi(header.Get("Content-Length")); err == nil && length < minCompressSize {
+		eligible = false
+	}
+	if eligible && c.accept {
+		header.Del("Content-Length")
+		// Byte ranges of the compressed body cannot be served.
+		header.Del("Accept-Ranges")
+		header.Set("Content-Encoding", "gzip")
+		// The compressed body is a different representation of the resource.
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+		c.gz = gzipWriters.Get().(*gzip.Writer)
+		c.gz.Reset(c.ResponseWriter)
+	}
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *compressWriter) Write(b []byte) (int, error) {
+	if !c.wroteHeader {
+		if c.Header().Get("Content-Type") == "" {
+			c.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		c.WriteHeader(http.StatusOK)
+	}
+	if c.gz != nil {
+		return c.gz.Write(b)
+	}
+	return c.ResponseWriter.Write(b)
+}
+
+// Flush sends any buffered compressed data before flushing the connection.
+func (c *compressWriter) Flush() {
+	if c.gz != nil {
+		_ = c.gz.Flush()
+	}
+	if flusher, ok := c.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (c *compressWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+// Close finishes the gzip stream and returns the writer to the pool.
+func (c *compressWriter) Close() {
+	if c.gz == nil {
+		return
+	}
+	_ = c.gz.Close()
+	c.gz.Reset(io.Discard)
+	gzipWriters.Put(c.gz)
+	c.gz = nil
+}
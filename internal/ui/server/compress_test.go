@@ -0,0 +1,148 @@
+package server
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompressHandler(t *testing.T) {
+	page := strings.Repeat("<p>streamer</p>", 100)
+	tests := []struct {
+		name        string
+		method      string
+		accept      string
+		contentType string
+		length      bool
+		body        string
+		wantGzip    bool
+		wantVary    bool
+	}{
+		{name: "html", accept: "gzip, deflate, br", contentType: "text/html; charset=utf-8", body: page, wantGzip: true, wantVary: true},
+		{name: "sniffed", accept: "gzip", body: page, wantGzip: true, wantVary: true},
+		{name: "not accepted", accept: "br", contentType: "text/css", body: page, wantVary: true},
+		{name: "refused", accept: "gzip;q=0", contentType: "text/css", body: page, wantVary: true},
+		{name: "small", accept: "gzip", contentType: "application/json", length: true, body: `{"ok":true}`, wantVary: true},
+		{name: "image", accept: "gzip", contentType: "image/png", body: page},
+		{name: "event stream", accept: "gzip", contentType: "text/event-stream", body: page},
+		{name: "head", method: http.MethodHead, accept: "gzip", contentType: "text/html"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := compressHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				if tt.length {
+					w.Header().Set("Content-Length", strconv.Itoa(len(tt.body)))
+				}
+				w.Header().Set("ETag", `"abc"`)
+				_, _ = io.WriteString(w, tt.body)
+			}))
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, "/", nil)
+			req.Header.Set("Accept-Encoding", tt.accept)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			gzipped := rr.Header().Get("Content-Encoding") == "gzip"
+			if gzipped != tt.wantGzip {
+				t.Fatalf("expected gzip=%v, got headers %v", tt.wantGzip, rr.Header())
+			}
+			if vary := strings.Contains(rr.Header().Get("Vary"), "Accept-Encoding"); vary != tt.wantVary {
+				t.Fatalf("expected Vary on Accept-Encoding=%v, got %q", tt.wantVary, rr.Header().Get("Vary"))
+			}
+			if !gzipped {
+				if rr.Body.String() != tt.body || rr.Header().Get("ETag") != `"abc"` {
+					t.Fatalf("expected untouched response, got %q %q", rr.Body.String(), rr.Header().Get("ETag"))
+				}
+				return
+			}
+			if rr.Header().Get("ETag") != `W/"abc"` || rr.Header().Get("Content-Length") != "" {
+				t.Fatalf("expected weak ETag and no Content-Length, got %v", rr.Header())
+			}
+			zr, err := gzip.NewReader(rr.Body)
+			if err != nil {
+				t.Fatalf("gzip reader: %v", err)
+			}
+			data, err := io.ReadAll(zr)
+			if err != nil || string(data) != tt.body {
+				t.Fatalf("expected body to round-trip, got %q (%v)", data, err)
+			}
+		})
+	}
+}
+
+func TestCompressHandlerFlushes(t *testing.T) {
+	handler := compressHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = io.WriteString(w, "first")
+		http.NewResponseController(w).Flush()
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if !rr.Flushed {
+		t.Fatalf("expected flush to reach the underlying writer")
+	}
+	zr, err := gzip.NewReader(rr.Body)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	if data, _ := io.ReadAll(zr); string(data) != "first" {
+		t.Fatalf("unexpected body %q", data)
+	}
+}
+
+func TestCompressHandlerSkipsRanges(t *testing.T) {
+	page := strings.Repeat("<p>streamer</p>", 100)
+	handler := compressHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		http.ServeContent(w, r, "index.html", time.Time{}, strings.NewReader(page))
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Range", "bytes=0-99")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusPartialContent {
+		t.Fatalf("expected 206, got %d", rr.Code)
+	}
+	if enc := rr.Header().Get("Content-Encoding"); enc != "" {
+		t.Fatalf("expected partial content to stay uncompressed, got %q", enc)
+	}
+	if rr.Header().Get("Content-Range") != "bytes 0-99/"+strconv.Itoa(len(page)) || rr.Body.String() != page[:100] {
+		t.Fatalf("unexpected range response %q %q", rr.Header().Get("Content-Range"), rr.Body.String())
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := map[string]bool{
+		"":               false,
+		"gzip":           true,
+		"*":              true,
+		"br":             false,
+		"gzip;q=0":       false,
+		"*;q=0":          false,
+		"*;q=0, gzip":    true,
+		"gzip, *;q=0":    true,
+		"gzip;q=0, *":    false,
+		"br, *;q=0.5":    true,
+		"GZIP;q=0.8, br": true,
+	}
+	for header, want := range tests {
+		if got := acceptsGzip(header); got != want {
+			t.Errorf("acceptsGzip(%q) = %v, want %v", header, got, want)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/mail"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/subscribers"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/webhooks"
+)
+
+const (
+	// emailTimeout bounds one SMTP delivery.
+	emailTimeout = 30 * time.Second
+	// emailLiveCooldown stops a flapping stream emailing a subscriber twice.
+	emailLiveCooldown = time.Hour
+	// emailDigestEvery is how often digest subscribers hear who streamed.
+	emailDigestEvery = 24 * time.Hour
+	// emailDigestPoll is how often the digest runner checks whether one is due.
+	emailDigestPoll = 10 * time.Minute
+)
+
+type emailPageData struct {
+	basePageData
+	Heading     string
+	Message     string
+	Action      string
+	ActionLabel string
+}
+
+// emailAlertsEnabled reports whether viewers can subscribe by email.
+func (s *server) emailAlertsEnabled() bool {
+	return s.mailer != nil && s.subscribers != nil
+}
+
+// emailLink returns the signed confirmation or unsubscribe URL for sub.
+func (s *server) emailLink(r *http.Request, action string, sub subscribers.Subscription) (string, error) {
+	sig, err := s.subscribers.Sign(action, sub.ID)
+	if err != nil {
+		return "", err
+	}
+	query := url.Values{"id": {sub.ID}, "sig": {sig}}
+	return s.absoluteURL(r, "/email/"+action+"?"+query.Encode()), nil
+}
+
+// sendSubscriberEmail mails sub with a one-click unsubscribe link in the
+// body and in the List-Unsubscribe headers (RFC 8058).
+func (s *server) sendSubscriberEmail(ctx context.Context, r *http.Request, sub subscribers.Subscription, subject, text string) error {
+	unsubscribe, err := s.emailLink(r, subscribers.ActionUnsubscribe, sub)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, mail.Message{
+		To:      sub.Email,
+		Subject: subject,
+		Text:    text + "\n\n" + s.t(r, "Unsubscribe: %s", unsubscribe) + "\n",
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + unsubscribe + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	})
+}
+
+// handleEmailSubscribe starts a double opt-in subscription to a streamer's
+// live alerts or the daily digest. The response is the same whether or not
+// the address was already subscribed, so it cannot be used to probe lists.
+func (s *server) handleEmailSubscribe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.emailAlertsEnabled() {
+		http.Error(w, "email alerts are unavailable", http.StatusNotFound)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	email, err := mail.ParseAddress(r.FormValue("email"))
+	if err != nil {
+		s.renderEmailPage(w, r, http.StatusBadRequest, s.t(r, "Email alerts"), s.t(r, "Enter a valid email address."), "", "")
+		return
+	}
+	kind := r.FormValue("kind")
+	var record streamers.Record
+	if kind != subscribers.KindDigest {
+		kind = subscribers.KindStreamer
+		var ok bool
+		if record, ok = s.streamerRecord(strings.TrimSpace(r.FormValue("streamer"))); !ok {
+			http.Error(w, "unknown streamer", http.StatusBadRequest)
+			return
+		}
+	}
+
+	sub, send, err := s.subscribers.Subscribe(email, kind, record.Streamer.ID)
+	if errors.Is(err, subscribers.ErrTooMany) {
+		s.renderEmailPage(w, r, http.StatusTooManyRequests, s.t(r, "Email alerts"), s.t(r, "This address has too many alert subscriptions."), "", "")
+		return
+	}
+	if err != nil {
+		s.logger.Warn("email", "Failed to save email subscription", map[string]any{"error": err.Error()})
+		http.Error(w, "failed to save subscription", http.StatusInternalServerError)
+		return
+	}
+	if send {
+		confirm, err := s.emailLink(r, subscribers.ActionConfirm, sub)
+		if err == nil {
+			var subject, text string
+			if kind == subscribers.KindDigest {
+				subject = s.t(r, "Confirm your daily %s digest", s.siteDisplayName())
+				text = s.t(r, "Confirm that you want a daily email listing who streamed on %s:", s.siteDisplayName())
+			} else {
+				subject = s.t(r, "Confirm alerts for %s", feedStreamerName(record))
+				text = s.t(r, "Confirm that you want an email when %s goes live on %s:", feedStreamerName(record), s.siteDisplayName())
+			}
+			text += "\n\n" + confirm + "\n\n" + s.t(r, "If you did not ask for this, ignore this email. Nothing is sent until you confirm.")
+			ctx, cancel := context.WithTimeout(r.Context(), emailTimeout)
+			err = s.sendSubscriberEmail(ctx, r, sub, subject, text)
+			cancel()
+		}
+		if err != nil {
+			s.logger.Warn("email", "Failed to send confirmation email", map[string]any{
+				"subscription": sub.ID,
+				"error":        err.Error(),
+			})
+			s.renderEmailPage(w, r, http.StatusBadGateway, s.t(r, "Email alerts"), s.t(r, "We could not send the confirmation email. Please try again later."), "", "")
+			return
+		}
+	}
+	s.renderEmailPage(w, r, http.StatusOK, s.t(r, "Check your inbox"), s.t(r, "We sent a confirmation link to %s. Alerts start once you follow it.", email), "", "")
+}
+
+// handleEmailConfirm completes a double opt-in from the emailed link.
+func (s *server) handleEmailConfirm(w http.ResponseWriter, r *http.Request) {
+	if !s.emailAlertsEnabled() {
+		http.Error(w, "email alerts are unavailable", http.StatusNotFound)
+		return
+	}
+	id := r.URL.Query().Get("id")
+	if !s.subscribers.Verify(subscribers.ActionConfirm, id, r.URL.Query().Get("sig")) {
+		s.renderEmailPage(w, r, http.StatusForbidden, s.t(r, "Email alerts"), s.t(r, "This link is invalid."), "", "")
+		return
+	}
+	sub, err := s.subscribers.Confirm(id)
+	if err != nil {
+		s.renderEmailPage(w, r, http.StatusNotFound, s.t(r, "Email alerts"), s.t(r, "This link has expired. Subscribe again to get a new one."), "", "")
+		return
+	}
+	message := s.t(r, "You will get an email each day listing who streamed.")
+	if sub.Kind == subscribers.KindStreamer {
+		name := sub.StreamerID
+		if record, ok := s.streamerRecord(sub.StreamerID); ok {
+			name = feedStreamerName(record)
+		}
+		message = s.t(r, "You will get an email when %s goes live.", name)
+	}
+	s.renderEmailPage(w, r, http.StatusOK, s.t(r, "You're subscribed"), message, "", "")
+}
+
+// handleEmailUnsubscribe removes a subscription from a signed link. GET
+// asks for confirmation so link scanners cannot unsubscribe anyone; POST,
+// including RFC 8058 one-click requests from mail clients, unsubscribes.
+func (s *server) handleEmailUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	if !s.emailAlertsEnabled() {
+		http.Error(w, "email alerts are unavailable", http.StatusNotFound)
+		return
+	}
+	id, sig := r.URL.Query().Get("id"), r.URL.Query().Get("sig")
+	if !s.subscribers.Verify(subscribers.ActionUnsubscribe, id, sig) {
+		s.renderEmailPage(w, r, http.StatusForbidden, s.t(r, "Email alerts"), s.t(r, "This link is invalid."), "", "")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		s.renderEmailPage(w, r, http.StatusOK, s.t(r, "Unsubscribe"), s.t(r, "Stop these email alerts?"), r.URL.RequestURI(), s.t(r, "Unsubscribe"))
+	case http.MethodPost:
+		if _, err := s.subscribers.Unsubscribe(id); err != nil && !errors.Is(err, subscribers.ErrNotFound) {
+			s.logger.Warn("email", "Failed to unsubscribe", map[string]any{"subscription": id, "error": err.Error()})
+			http.Error(w, "failed to unsubscribe", http.StatusInternalServerError)
+			return
+		}
+		s.renderEmailPage(w, r, http.StatusOK, s.t(r, "You're unsubscribed"), s.t(r, "You will not get these email alerts any more."), "", "")
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *server) renderEmailPage(w http.ResponseWriter, r *http.Request, status int, heading, message, action, actionLabel string) {
+	page := s.buildBasePageData(r, fmt.Sprintf("%s - %s", heading, s.siteDisplayName()), s.t(r, s.siteDescription), r.URL.Path)
+	page.Robots = "noindex"
+	tmpl := s.template(r, "email")
+	if tmpl == nil {
+		http.Error(w, message, status)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_ = tmpl.ExecuteTemplate(w, "email", emailPageData{
+		basePageData: page,
+		Heading:      heading,
+		Message:      message,
+		Action:       action,
+		ActionLabel:  actionLabel,
+	})
+}
+
+// emailLive emails the confirmed followers of a streamer that went live and
+// remembers the event for the digest.
+func (s *server) emailLive(event string, record streamers.Record, platform string) {
+	if !s.emailAlertsEnabled() || event != webhooks.EventStreamerLive {
+		return
+	}
+	a := s.announcement(record, platform)
+	if err := s.subscribers.RecordActivity(subscribers.Activity{
+		StreamerID: record.Streamer.ID,
+		Alias:      a.Alias,
+		Platform:   a.PlatformName,
+		URL:        a.PageURL,
+	}); err != nil {
+		s.logger.Warn("email", "Failed to record digest activity", map[string]any{"error": err.Error()})
+	}
+	followers, err := s.subscribers.Followers(record.Streamer.ID)
+	if err != nil || len(followers) == 0 {
+		return
+	}
+	subject := s.t(nil, "%s is live on %s", a.Alias, a.PlatformName)
+	text := subject + "\n\n" + s.t(nil, "Watch: %s", a.URL) + "\n" + s.t(nil, "Profile: %s", a.PageURL)
+	go func() {
+		for _, sub := range followers {
+			now := time.Now()
+			if sub.LastSent.After(sub.ConfirmedAt) && now.Sub(sub.LastSent) < emailLiveCooldown {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), emailTimeout)
+			err := s.sendSubscriberEmail(ctx, nil, sub, subject, text)
+			cancel()
+			if err != nil {
+				s.logger.Warn("email", "Failed to send live alert", map[string]any{
+					"subscription": sub.ID,
+					"streamer":     record.Streamer.ID,
+					"error":        err.Error(),
+				})
+				continue
+			}
+			_ = s.subscribers.MarkSent(sub.ID, now)
+		}
+	}()
+}
+
+// runEmailDigest sends the daily digest until ctx is cancelled.
+func (s *server) runEmailDigest(ctx context.Context) {
+	ticker := time.NewTicker(emailDigestPoll)
+	defer ticker.Stop()
+	for {
+		s.sendEmailDigest(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendEmailDigest mails digest subscribers the streams since the last
+// digest once one is due. Days without streams send nothing.
+func (s *server) sendEmailDigest(ctx context.Context) {
+	subs, activity, due, err := s.subscribers.TakeDigest(emailDigestEvery)
+	if err != nil {
+		s.logger.Warn("email", "Failed to load digest", map[string]any{"error": err.Error()})
+		return
+	}
+	if !due || len(subs) == 0 || len(activity) == 0 {
+		return
+	}
+	lines := make([]string, 0, len(activity))
+	for _, a := range activity {
+		lines = append(lines, fmt.Sprintf("- %s (%s, %s UTC) %s", a.Alias, a.Platform, a.At.Format("Jan 2 15:04"), a.URL))
+	}
+	subject := s.t(nil, "Who streamed on %s", s.siteDisplayName())
+	text := s.t(nil, "These streamers went live on %s since the last digest:", s.siteDisplayName()) + "\n\n" + strings.Join(lines, "\n")
+	for _, sub := range subs {
+		sendCtx, cancel := context.WithTimeout(ctx, emailTimeout)
+		err := s.sendSubscriberEmail(sendCtx, nil, sub, subject, text)
+		cancel()
+		if err != nil {
+			s.logger.Warn("email", "Failed to send digest", map[string]any{
+				"subscription": sub.ID,
+				"error":        err.Error(),
+			})
+		}
+	}
+}
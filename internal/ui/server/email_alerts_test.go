@@ -0,0 +1,121 @@
+package server
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/mail"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/mail/mailtest"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/subscribers"
+)
+
+var emailLinkPattern = regexp.MustCompile(`https?://\S+/email/(confirm|unsubscribe)\?\S+`)
+
+// emailTestLink returns the request URI of the emailed link for action.
+func emailTestLink(t *testing.T, body, action string) string {
+	t.Helper()
+	for _, match := range emailLinkPattern.FindAllStringSubmatch(body, -1) {
+		if match[1] != action {
+			continue
+		}
+		link, err := url.Parse(match[0])
+		if err != nil {
+			t.Fatalf("parse %s link: %v", action, err)
+		}
+		return link.RequestURI()
+	}
+	t.Fatalf("no %s link in %q", action, body)
+	return ""
+}
+
+func TestEmailAlertsDoubleOptIn(t *testing.T) {
+	smtp := mailtest.NewServer(t)
+	mailer, err := mail.NewSMTP(mail.SMTPOptions{Addr: smtp.Addr, From: "alerts@sharpen.live"})
+	if err != nil {
+		t.Fatalf("new smtp: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "synth-wave", "streamers.json")
+	store := streamers.NewStore(path)
+	if _, err := store.Append(streamers.Record{
+		Streamer:  streamers.Streamer{ID: "a", Alias: "alpha", Languages: []string{"English"}},
+		Platforms: streamers.Platforms{Twitch: &streamers.TwitchPlatform{Username: "alpha", BroadcasterID: "42"}},
+		Sites:     []string{"synth-wave"},
+	}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	srv := newTestServer()
+	srv.templates["email"] = template.Must(template.New("email").Parse("{{.Heading}}: {{.Message}}"))
+	srv.siteKey = "synth-wave"
+	srv.streamersStore = store
+	srv.storeCache = map[string]*streamers.Store{}
+	srv.mailer = mailer
+	srv.subscribers = subscribers.NewStore(filepath.Join(t.TempDir(), "subscribers.json"))
+	defer registerSite(srv)()
+
+	form := url.Values{"email": {"Viewer@Example.com"}, "streamer": {"a"}, "kind": {"streamer"}}
+	req := httptest.NewRequest(http.MethodPost, "/email/subscribe", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr := httptest.NewRecorder()
+	srv.handleEmailSubscribe(rr, req)
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "viewer@example.com") {
+		t.Fatalf("unexpected subscribe response %d %s", rr.Code, rr.Body.String())
+	}
+	messages := smtp.Wait(1, 5*time.Second)
+	if len(messages) != 1 || messages[0].To[0] != "viewer@example.com" {
+		t.Fatalf("expected a confirmation email, got %+v", messages)
+	}
+	if followers, _ := srv.subscribers.Followers("a"); len(followers) != 0 {
+		t.Fatalf("alerts must wait for confirmation, got %+v", followers)
+	}
+
+	confirm := emailTestLink(t, messages[0].Body, subscribers.ActionConfirm)
+	rr = httptest.NewRecorder()
+	srv.handleEmailConfirm(rr, httptest.NewRequest(http.MethodGet, confirm+"0", nil))
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected a tampered link to be rejected, got %d", rr.Code)
+	}
+	rr = httptest.NewRecorder()
+	srv.handleEmailConfirm(rr, httptest.NewRequest(http.MethodGet, confirm, nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("confirm: %d %s", rr.Code, rr.Body.String())
+	}
+
+	raw, _ := json.Marshal(StreamOnlineEvent{ID: "stream-1", BroadcasterUserID: "42", StartedAt: time.Now()})
+	srv.handleStreamOnline(EventSubNotification{Event: raw})
+	messages = smtp.Wait(2, 5*time.Second)
+	if len(messages) != 2 {
+		t.Fatalf("expected a live alert, got %+v", messages)
+	}
+	alert := messages[1]
+	if alert.Header.Get("Subject") != "alpha is live on Twitch" {
+		t.Fatalf("unexpected subject %q", alert.Header.Get("Subject"))
+	}
+	unsubscribe := emailTestLink(t, alert.Body, subscribers.ActionUnsubscribe)
+	if header := alert.Header.Get("List-Unsubscribe"); !strings.HasSuffix(header, unsubscribe+">") {
+		t.Fatalf("expected a List-Unsubscribe header for %s, got %q", unsubscribe, header)
+	}
+	if alert.Header.Get("List-Unsubscribe-Post") != "List-Unsubscribe=One-Click" {
+		t.Fatalf("expected one-click unsubscribe, got %v", alert.Header)
+	}
+
+	rr = httptest.NewRecorder()
+	srv.handleEmailUnsubscribe(rr, httptest.NewRequest(http.MethodGet, unsubscribe, nil))
+	if followers, _ := srv.subscribers.Followers("a"); rr.Code != http.StatusOK || len(followers) != 1 {
+		t.Fatalf("GET must only ask for confirmation, got %d %+v", rr.Code, followers)
+	}
+	rr = httptest.NewRecorder()
+	srv.handleEmailUnsubscribe(rr, httptest.NewRequest(http.MethodPost, unsubscribe, strings.NewReader("List-Unsubscribe=One-Click")))
+	if followers, _ := srv.subscribers.Followers("a"); rr.Code != http.StatusOK || len(followers) != 0 {
+		t.Fatalf("expected the one-click POST to unsubscribe, got %d %+v", rr.Code, followers)
+	}
+}
@@ -0,0 +1,381 @@
+package server
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+)
+
+// feedItemLimit caps the number of entries in each syndication feed.
+const feedItemLimit = 50
+
+// feedItem is a format-neutral feed entry rendered as RSS, Atom or JSON Feed.
+type feedItem struct {
+	ID        string
+	Title     string
+	Link      string
+	Summary   string
+	Published time.Time
+	Updated   time.Time
+}
+
+// siteFeed is a format-neutral feed.
+type siteFeed struct {
+	Title       string
+	Description string
+	HomeURL     string
+	SelfURL     string
+	Updated     time.Time
+	Items       []feedItem
+}
+
+// handleFeed serves /feeds/{live,new}.{rss,atom,json}. Both feeds are built
+// from the site's roster, so they only list streamers visible on this site.
+func (s *server) handleFeed(w http.ResponseWriter, r *http.Request) {
+	if !allowRead(w, r) {
+		return
+	}
+	name, format, ok := strings.Cut(r.PathValue("feed"), ".")
+	if !ok || (format != "rss" && format != "atom" && format != "json") {
+		http.NotFound(w, r)
+		return
+	}
+
+	var build func(*http.Request, []streamers.Record) []feedItem
+	var title, description string
+	switch name {
+	case "live":
+		build = s.liveFeedItems
+		title = s.siteName + " - live now"
+		description = "Streamers going live on " + s.siteName + "."
+	case "new":
+		build = s.newFeedItems
+		title = s.siteName + " - new streamers"
+		description = "Streamers recently added to " + s.siteName + "."
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	records, ok := s.listPublicRecords(w)
+	if !ok {
+		return
+	}
+	feed := siteFeed{
+		Title:       title,
+		Description: description,
+		HomeURL:     s.absoluteURL(r, "/"),
+		SelfURL:     s.absoluteURL(r, r.URL.Path),
+		Items:       build(r, records),
+	}
+	for _, item := range feed.Items {
+		if item.Updated.After(feed.Updated) {
+			feed.Updated = item.Updated
+		}
+	}
+	if feed.Updated.IsZero() {
+		// An empty feed last changed when the roster did.
+		if info, err := os.Stat(s.streamersStore.Path()); err == nil {
+			feed.Updated = info.ModTime()
+		}
+	}
+
+	var (
+		body        []byte
+		contentType string
+		err         error
+	)
+	switch format {
+	case "rss":
+		body, err = feed.rss()
+		contentType = "application/rss+xml; charset=utf-8"
+	case "atom":
+		body, err = feed.atom()
+		contentType = "application/atom+xml; charset=utf-8"
+	default:
+		body, err = feed.jsonFeed()
+		contentType = "application/feed+json; charset=utf-8"
+	}
+	if err != nil {
+		http.Error(w, "failed to encode feed", http.StatusInternalServerError)
+		return
+	}
+	if !feed.Updated.IsZero() {
+		w.Header().Set("Last-Modified", feed.Updated.UTC().Format(http.TimeFormat))
+	}
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	writeCachedBody(w, r, contentType, body)
+}
+
+// liveFeedItems returns one entry per platform a streamer is live on, newest
+// stream first. Entry IDs include the start time so each stream is a new item.
+func (s *server) liveFeedItems(r *http.Request, records []streamers.Record) []feedItem {
+	var items []feedItem
+	for _, rec := range records {
+		status := rec.Status
+		if status == nil {
+			continue
+		}
+		name := feedStreamerName(rec)
+		page := s.absoluteURL(r, feedStreamerPath(rec))
+		add := func(platform, link string, started time.Time) {
+			if started.IsZero() {
+				started = rec.UpdatedAt
+			}
+			if link == "" {
+				link = page
+			}
+			items = append(items, feedItem{
+				ID:        fmt.Sprintf("%s#live-%s-%d", page, strings.ToLower(platform), started.Unix()),
+				Title:     fmt.Sprintf("%s is live on %s", name, platform),
+				Link:      link,
+				Summary:   strings.TrimSpace(rec.Streamer.Description),
+				Published: started,
+				Updated:   started,
+			})
+		}
+		before := len(items)
+		if yt := status.YouTube; yt != nil && yt.Live {
+			add("YouTube", youtubePlatformURL(rec.Platforms.YouTube, status, true), yt.StartedAt)
+		}
+		if tw := status.Twitch; tw != nil && tw.Live {
+			link := ""
+			if rec.Platforms.Twitch != nil {
+				link = twitchChannelURL(rec.Platforms.Twitch.Username)
+			}
+			add("Twitch", link, tw.StartedAt)
+		}
+		if fb := status.Facebook; fb != nil && fb.Live {
+			link := ""
+			if rec.Platforms.Facebook != nil {
+				link = facebookPageURL(rec.Platforms.Facebook.PageID)
+			}
+			add("Facebook", link, fb.StartedAt)
+		}
+		if len(items) == before && status.Live {
+			add(feedPlatformLabel(status.Platforms), "", time.Time{})
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Published.After(items[j].Published)
+	})
+	if len(items) > feedItemLimit {
+		items = items[:feedItemLimit]
+	}
+	return items
+}
+
+// newFeedItems returns the most recently approved streamers. Records are
+// created when a submission is approved, so CreatedAt is the approval time.
+func (s *server) newFeedItems(r *http.Request, records []streamers.Record) []feedItem {
+	sorted := append([]streamers.Record(nil), records...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+	})
+	if len(sorted) > feedItemLimit {
+		sorted = sorted[:feedItemLimit]
+	}
+	items := make([]feedItem, 0, len(sorted))
+	for _, rec := range sorted {
+		page := s.absoluteURL(r, feedStreamerPath(rec))
+		updated := rec.UpdatedAt
+		if updated.Before(rec.CreatedAt) {
+			updated = rec.CreatedAt
+		}
+		items = append(items, feedItem{
+			ID:        page,
+			Title:     fmt.Sprintf("%s joined %s", feedStreamerName(rec), s.siteName),
+			Link:      page,
+			Summary:   strings.TrimSpace(rec.Streamer.Description),
+			Published: rec.CreatedAt,
+			Updated:   updated,
+		})
+	}
+	return items
+}
+
+func feedStreamerName(rec streamers.Record) string {
+	if alias := strings.TrimSpace(rec.Streamer.Alias); alias != "" {
+		return alias
+	}
+	return rec.Streamer.ID
+}
+
+func feedStreamerPath(rec streamers.Record) string {
+	return "/streamers/" + url.PathEscape(feedStreamerName(rec))
+}
+
+func feedPlatformLabel(platforms []string) string {
+	if len(platforms) == 0 {
+		return "stream"
+	}
+	return strings.Join(platforms, ", ")
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Self          rssLink   `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description,omitempty"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+func (f siteFeed) rss() ([]byte, error) {
+	doc := rssDocument{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:       f.Title,
+			Link:        f.HomeURL,
+			Description: f.Description,
+			Self:        rssLink{Href: f.SelfURL, Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+	if !f.Updated.IsZero() {
+		doc.Channel.LastBuildDate = f.Updated.UTC().Format(time.RFC1123Z)
+	}
+	for _, item := range f.Items {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{Value: item.ID},
+			PubDate:     item.Published.UTC().Format(time.RFC1123Z),
+			Description: item.Summary,
+		})
+	}
+	return encodeFeedXML(doc)
+}
+
+type atomDocument struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string   `xml:"id"`
+	Title     string   `xml:"title"`
+	Link      atomLink `xml:"link"`
+	Published string   `xml:"published"`
+	Updated   string   `xml:"updated"`
+	Summary   string   `xml:"summary,omitempty"`
+}
+
+func (f siteFeed) atom() ([]byte, error) {
+	doc := atomDocument{
+		ID:      f.SelfURL,
+		Title:   f.Title,
+		Updated: f.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.SelfURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: f.HomeURL, Rel: "alternate", Type: "text/html"},
+		},
+	}
+	for _, item := range f.Items {
+		doc.Entries = append(doc.Entries, atomEntry{
+			ID:        item.ID,
+			Title:     item.Title,
+			Link:      atomLink{Href: item.Link, Rel: "alternate"},
+			Published: item.Published.UTC().Format(time.RFC3339),
+			Updated:   item.Updated.UTC().Format(time.RFC3339),
+			Summary:   item.Summary,
+		})
+	}
+	return encodeFeedXML(doc)
+}
+
+func encodeFeedXML(doc any) ([]byte, error) {
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+type jsonFeedDocument struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string `json:"id"`
+	URL           string `json:"url"`
+	Title         string `json:"title"`
+	ContentText   string `json:"content_text"`
+	DatePublished string `json:"date_published"`
+	DateModified  string `json:"date_modified"`
+}
+
+func (f siteFeed) jsonFeed() ([]byte, error) {
+	doc := jsonFeedDocument{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		Description: f.Description,
+		HomePageURL: f.HomeURL,
+		FeedURL:     f.SelfURL,
+		Items:       make([]jsonFeedItem, 0, len(f.Items)),
+	}
+	for _, item := range f.Items {
+		text := item.Summary
+		if text == "" {
+			text = item.Title
+		}
+		doc.Items = append(doc.Items, jsonFeedItem{
+			ID:            item.ID,
+			URL:           item.Link,
+			Title:         item.Title,
+			ContentText:   text,
+			DatePublished: item.Published.UTC().Format(time.RFC3339),
+			DateModified:  item.Updated.UTC().Format(time.RFC3339),
+		})
+	}
+	return json.Marshal(doc)
+}
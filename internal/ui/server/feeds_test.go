@@ -0,0 +1,125 @@
+package server
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+)
+
+func feedTestRecords() []streamers.Record {
+	records := apiTestRecords()
+	started := time.Date(2025, 2, 1, 18, 0, 0, 0, time.UTC)
+	records[0].Status.YouTube.StartedAt = started
+	records[0].UpdatedAt = started.Add(time.Minute)
+	records[1].Status = &streamers.Status{Live: true, Twitch: &streamers.TwitchStatus{Live: true, StartedAt: started.Add(time.Hour)}}
+	return records
+}
+
+func getFeed(t *testing.T, srv *server, target string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/feeds/{feed}", srv.handleFeed)
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestLiveFeedFormats(t *testing.T) {
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: feedTestRecords()}
+
+	rr := getFeed(t, srv, "/feeds/live.json", nil)
+	if rr.Code != http.StatusOK || !strings.HasPrefix(rr.Header().Get("Content-Type"), "application/feed+json") {
+		t.Fatalf("unexpected response %d %q", rr.Code, rr.Header().Get("Content-Type"))
+	}
+	var doc jsonFeedDocument
+	if err := json.Unmarshal(rr.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(doc.Items) != 2 {
+		t.Fatalf("expected two live items, got %+v", doc.Items)
+	}
+	if doc.Items[0].Title != "alpha is live on Twitch" || doc.Items[0].URL != "https://www.twitch.tv/alpha" {
+		t.Fatalf("expected newest stream first, got %+v", doc.Items[0])
+	}
+	if doc.Items[1].URL != "https://www.youtube.com/watch?v=vid" || doc.Items[1].DatePublished != "2025-02-01T18:00:00Z" {
+		t.Fatalf("expected YouTube watch link and start time, got %+v", doc.Items[1])
+	}
+	if got := rr.Header().Get("Last-Modified"); got != "Sat, 01 Feb 2025 19:00:00 GMT" {
+		t.Fatalf("expected Last-Modified from newest item, got %q", got)
+	}
+
+	rr = getFeed(t, srv, "/feeds/live.atom", nil)
+	var atom atomDocument
+	if err := xml.Unmarshal(rr.Body.Bytes(), &atom); err != nil {
+		t.Fatalf("decode atom: %v", err)
+	}
+	if atom.Updated != "2025-02-01T19:00:00Z" || len(atom.Entries) != 2 {
+		t.Fatalf("unexpected atom feed %+v", atom)
+	}
+
+	rr = getFeed(t, srv, "/feeds/live.rss", nil)
+	var rss rssDocument
+	if err := xml.Unmarshal(rr.Body.Bytes(), &rss); err != nil {
+		t.Fatalf("decode rss: %v", err)
+	}
+	if len(rss.Channel.Items) != 2 || rss.Channel.Items[1].PubDate != "Sat, 01 Feb 2025 18:00:00 +0000" {
+		t.Fatalf("unexpected rss feed %+v", rss.Channel)
+	}
+	if strings.Contains(rr.Body.String(), "c@example.com") || strings.Contains(rr.Body.String(), "hub-secret") {
+		t.Fatalf("feed leaked private fields: %s", rr.Body.String())
+	}
+}
+
+func TestNewFeedOrdersByApproval(t *testing.T) {
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: feedTestRecords()}
+
+	rr := getFeed(t, srv, "/feeds/new.json", nil)
+	var doc jsonFeedDocument
+	if err := json.Unmarshal(rr.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	var titles []string
+	for _, item := range doc.Items {
+		titles = append(titles, item.Title)
+	}
+	want := "Charlie joined Sharpen.Live,Bravo joined Sharpen.Live,alpha joined Sharpen.Live"
+	if got := strings.Join(titles, ","); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if doc.Items[0].DateModified != "2025-02-01T18:01:00Z" || doc.Items[1].DateModified != "2025-01-01T02:00:00Z" {
+		t.Fatalf("expected modified dates from UpdatedAt or CreatedAt, got %+v", doc.Items)
+	}
+}
+
+func TestFeedRevalidationAndUnknownFeeds(t *testing.T) {
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: feedTestRecords()}
+
+	rr := getFeed(t, srv, "/feeds/new.atom", nil)
+	etag := rr.Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("expected ETag")
+	}
+	rr = getFeed(t, srv, "/feeds/new.atom", http.Header{"If-None-Match": {etag}})
+	if rr.Code != http.StatusNotModified {
+		t.Fatalf("expected 304, got %d", rr.Code)
+	}
+
+	for _, target := range []string{"/feeds/live.xml", "/feeds/old.rss", "/feeds/live"} {
+		if rr := getFeed(t, srv, target, nil); rr.Code != http.StatusNotFound {
+			t.Fatalf("%s: expected 404, got %d", target, rr.Code)
+		}
+	}
+}
@@ -12,15 +12,7 @@ import (
 func applyDefaults(opts Options, site config.SiteConfig) Options {
 	fallbackApp := config.AlertserverAppConfig()
 	if opts.Listen == "" {
-		addr := strings.TrimSpace(site.Server.Addr)
-		port := strings.TrimSpace(site.Server.Port)
-		if addr != "" || port != "" {
-			if port != "" && !strings.HasPrefix(port, ":") {
-				opts.Listen = addr + ":" + port
-			} else {
-				opts.Listen = addr + port
-			}
-		}
+		opts.Listen = joinListenAddr(site.Server.Addr, site.Server.Port)
 		if opts.Listen == "" {
 			opts.Listen = "127.0.0.1:4173"
 		}
@@ -52,6 +44,28 @@ func applyDefaults(opts Options, site config.SiteConfig) Options {
 	return opts
 }
 
+// joinListenAddr combines a config addr/port pair into a listen address,
+// accepting ports with or without the leading colon.
+func joinListenAddr(addr, port string) string {
+	addr = strings.TrimSpace(addr)
+	port = strings.TrimSpace(port)
+	if addr == "" && port == "" {
+		return ""
+	}
+	if port != "" && !strings.HasPrefix(port, ":") {
+		return addr + ":" + port
+	}
+	return addr + port
+}
+
+// listenAddr mirrors http.Server's default of ":http" for an empty address.
+func listenAddr(listen string) string {
+	if listen == "" {
+		return ":http"
+	}
+	return listen
+}
+
 func switchToAlertserver(cfg config.Config, opts Options) (config.SiteConfig, Options) {
 	fallback := config.Alertserver(cfg)
 	opts.Site = fallback.Key
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// immutableCacheControl is sent for asset URLs carrying their content
+	// hash, which change whenever the file does.
+	immutableCacheControl = "public, max-age=31536000, immutable"
+	// assetCacheControl is sent for unversioned asset URLs.
+	assetCacheControl = "public, max-age=300"
+)
+
+// assetVersions caches the content hash of theme files by path. Entries are
+// recomputed when the file on disk changes size or modification time.
+var assetVersions = struct {
+	sync.Mutex
+	entries map[string]assetVersion
+}{entries: map[string]assetVersion{}}
+
+type assetVersion struct {
+	modTime time.Time
+	size    int64
+	hash    string
+}
+
+// assetHash returns a short content hash of the theme file at path, or ""
+// when it cannot be read.
+func assetHash(path string) string {
+	var stamp assetVersion
+	if info, err := themeFiles.stat(path); err == nil {
+		stamp.modTime, stamp.size = info.ModTime(), info.Size()
+	}
+	assetVersions.Lock()
+	cached, ok := assetVersions.entries[path]
+	assetVersions.Unlock()
+	if ok && cached.modTime.Equal(stamp.modTime) && cached.size == stamp.size {
+		return cached.hash
+	}
+
+	data, err := themeFiles.readFile(path)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	stamp.hash = hex.EncodeToString(sum[:6])
+
+	assetVersions.Lock()
+	assetVersions.entries[path] = stamp
+	assetVersions.Unlock()
+	return stamp.hash
+}
+
+// assetURL appends the content hash of the site asset served at urlPath
+// (e.g. "/styles.css") so templates link to a URL that can be cached forever.
+func (s *server) assetURL(urlPath string) string {
+	hash := assetHash(s.resolveAsset(strings.TrimPrefix(urlPath, "/")))
+	if hash == "" {
+		return urlPath
+	}
+	return urlPath + "?v=" + hash
+}
+
+// setAssetCacheHeaders sets the ETag and Cache-Control for the theme file at
+// path. Requests whose v parameter matches the current hash are immutable.
+func setAssetCacheHeaders(w http.ResponseWriter, r *http.Request, path string) {
+	hash := assetHash(path)
+	if hash == "" {
+		return
+	}
+	w.Header().Set("ETag", `"`+hash+`"`)
+	if r.URL.Query().Get("v") == hash {
+		w.Header().Set("Cache-Control", immutableCacheControl)
+		return
+	}
+	w.Header().Set("Cache-Control", assetCacheControl)
+}
+
+// rosterNotModified sets Last-Modified on a page rendered from the roster and
+// answers 304 when the client's copy is current, reporting whether it did.
+// Pages also change when the site is restarted with new templates or
+// translations, so the roster's time never predates the server's start.
+func (s *server) rosterNotModified(w http.ResponseWriter, r *http.Request) bool {
+	if s.streamersStore == nil {
+		return false
+	}
+	modTime, err := fileModTime(s.streamersStore.Path())
+	if err != nil {
+		return false
+	}
+	if s.startedAt.After(modTime) {
+		modTime = s.startedAt
+	}
+	modTime = modTime.UTC().Truncate(time.Second)
+	w.Header().Set("Last-Modified", modTime.Format(http.TimeFormat))
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || modTime.After(since) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
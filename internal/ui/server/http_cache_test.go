@@ -0,0 +1,105 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAssetFingerprints(t *testing.T) {
+	dir := t.TempDir()
+	styles := filepath.Join(dir, "styles.css")
+	if err := os.WriteFile(styles, []byte("body { color: red; }"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	srv := newTestServer()
+	srv.assetDirs = []string{dir}
+
+	versioned := srv.assetURL("/styles.css")
+	hash, ok := strings.CutPrefix(versioned, "/styles.css?v=")
+	if !ok || len(hash) != 12 {
+		t.Fatalf("expected content-hash URL, got %q", versioned)
+	}
+	if page := srv.buildBasePageData(httptest.NewRequest(http.MethodGet, "/", nil), "", "", "/"); page.StylesheetPath != versioned {
+		t.Fatalf("expected page data to link the versioned stylesheet, got %q", page.StylesheetPath)
+	}
+
+	handler := srv.assetHandler("styles.css", "text/css")
+	tests := []struct {
+		name        string
+		target      string
+		ifNoneMatch string
+		wantStatus  int
+		wantCache   string
+	}{
+		{name: "versioned", target: versioned, wantStatus: http.StatusOK, wantCache: immutableCacheControl},
+		{name: "plain", target: "/styles.css", wantStatus: http.StatusOK, wantCache: assetCacheControl},
+		{name: "stale version", target: "/styles.css?v=000000000000", wantStatus: http.StatusOK, wantCache: assetCacheControl},
+		{name: "revalidated", target: "/styles.css", ifNoneMatch: `"` + hash + `"`, wantStatus: http.StatusNotModified, wantCache: assetCacheControl},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		if tt.ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code != tt.wantStatus || rr.Header().Get("Cache-Control") != tt.wantCache || rr.Header().Get("ETag") != `"`+hash+`"` {
+			t.Fatalf("%s: got %d %v", tt.name, rr.Code, rr.Header())
+		}
+	}
+
+	if err := os.WriteFile(styles, []byte("body { color: blue; }"), 0o644); err != nil {
+		t.Fatalf("rewrite: %v", err)
+	}
+	if next := srv.assetURL("/styles.css"); next == versioned {
+		t.Fatalf("expected a new hash after the file changed")
+	}
+	if got := srv.assetURL("/missing.js"); got != "/missing.js" {
+		t.Fatalf("expected missing assets to stay unversioned, got %q", got)
+	}
+}
+
+func TestRosterPagesConditionalGET(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "streamers.json")
+	if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	modTime := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: apiTestRecords(), path: path}
+
+	get := func(since string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if since != "" {
+			req.Header.Set("If-Modified-Since", since)
+		}
+		rr := httptest.NewRecorder()
+		srv.handleHome(rr, req)
+		return rr
+	}
+
+	rr := get("")
+	lastModified := rr.Header().Get("Last-Modified")
+	if rr.Code != http.StatusOK || lastModified != modTime.Format(http.TimeFormat) || rr.Header().Get("Cache-Control") != "no-cache" {
+		t.Fatalf("expected Last-Modified from the roster, got %d %v", rr.Code, rr.Header())
+	}
+	if rr = get(lastModified); rr.Code != http.StatusNotModified || rr.Body.Len() != 0 {
+		t.Fatalf("expected 304 for a current copy, got %d", rr.Code)
+	}
+	if rr = get(modTime.Add(-time.Minute).Format(http.TimeFormat)); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 for an older copy, got %d", rr.Code)
+	}
+
+	srv.startedAt = modTime.Add(time.Hour)
+	if rr = get(lastModified); rr.Code != http.StatusOK || rr.Header().Get("Last-Modified") != srv.startedAt.Format(http.TimeFormat) {
+		t.Fatalf("expected a restart to invalidate cached pages, got %d %v", rr.Code, rr.Header())
+	}
+}
@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/config"
+	"github.com/Its-donkey/Sharpen-live/internal/ui/i18n"
+)
+
+// localeCookie remembers the language a visitor picked from a prefixed URL.
+const localeCookie = "lang"
+
+type localeContextKey struct{}
+
+type localeAlternate struct {
+	Hreflang string
+	URL      string
+}
+
+type localeLink struct {
+	Label   string
+	Href    string
+	Current bool
+}
+
+// siteLocales returns the site's UI locales with the default first. Sites
+// that configure none are served in the source language only.
+func siteLocales(app config.AppConfig) []string {
+	def := i18n.Normalize(app.DefaultLocale)
+	if def == "" && len(app.Locales) > 0 {
+		def = i18n.Normalize(app.Locales[0])
+	}
+	if def == "" {
+		def = i18n.SourceLocale
+	}
+	locales := []string{def}
+	for _, locale := range app.Locales {
+		locale = i18n.Normalize(locale)
+		if locale != "" && !containsString(locales, locale) {
+			locales = append(locales, locale)
+		}
+	}
+	return locales
+}
+
+// loadCatalog reads locales/<locale>.json from each template directory,
+// starting with the last (default-site) so a site's own translations win.
+func loadCatalog(dirs ...string) (*i18n.Catalog, error) {
+	catalog := i18n.NewCatalog()
+	for i := len(dirs) - 1; i >= 0; i-- {
+		dir := filepath.Join(dirs[i], "locales")
+		for _, name := range themeFiles.files(dir) {
+			locale, ok := strings.CutSuffix(name, ".json")
+			if !ok {
+				continue
+			}
+			data, err := themeFiles.readFile(filepath.Join(dir, name))
+			if err != nil {
+				return nil, fmt.Errorf("read %s translations: %w", locale, err)
+			}
+			if err := catalog.Parse(locale, data); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return catalog, nil
+}
+
+// loadSiteTemplates parses one template set per locale, each with t, tn and
+// statusLabel bound to its language. The first locale's sources are returned.
+func loadSiteTemplates(locales []string, dirs ...string) (map[string]map[string]*template.Template, []TemplateSource, *i18n.Catalog, error) {
+	catalog, err := loadCatalog(dirs...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sets := make(map[string]map[string]*template.Template, len(locales))
+	var sources []TemplateSource
+	for i, locale := range locales {
+		set, src, err := loadLocaleTemplates(catalog, locale, dirs...)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if i == 0 {
+			sources = src
+		}
+		sets[locale] = set
+	}
+	return sets, sources, catalog, nil
+}
+
+// defaultLocale is served at unprefixed URLs when nothing else is negotiated.
+func (s *server) defaultLocale() string {
+	if len(s.locales) > 0 {
+		return s.locales[0]
+	}
+	return i18n.SourceLocale
+}
+
+// locale returns the language chosen for r by localeHandler.
+func (s *server) locale(r *http.Request) string {
+	if r != nil {
+		if locale, ok := r.Context().Value(localeContextKey{}).(string); ok && locale != "" {
+			return locale
+		}
+	}
+	return s.defaultLocale()
+}
+
+// t translates a server-side message into the request's language.
+func (s *server) t(r *http.Request, key string, args ...any) string {
+	return s.catalog.Translate(s.locale(r), key, args...)
+}
+
+// template returns the named template for the request's language, falling
+// back to the default set.
+func (s *server) template(r *http.Request, name string) *template.Template {
+	if set := s.localeTemplates[s.locale(r)]; set[name] != nil {
+		return set[name]
+	}
+	return s.templates[name]
+}
+
+// localePath prefixes path with locale unless it is the default.
+func (s *server) localePath(locale, path string) string {
+	if locale == s.defaultLocale() || len(s.locales) < 2 {
+		return path
+	}
+	return "/" + locale + path
+}
+
+// splitLocalePrefix reports whether path starts with a supported locale and
+// returns the path without it.
+func (s *server) splitLocalePrefix(path string) (string, string, bool) {
+	segment, rest, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	locale := i18n.Normalize(segment)
+	if segment == "" || !containsString(s.locales, locale) {
+		return "", "", false
+	}
+	return locale, "/" + rest, true
+}
+
+// localeHandler picks the language for each request: a /<locale>/ path
+// prefix first (remembered in a cookie), then that cookie, then
+// Accept-Language. Prefixed requests are served by the unprefixed handler;
+// the default locale's prefix redirects to the plain URL.
+func (s *server) localeHandler(next http.Handler) http.Handler {
+	if len(s.locales) < 2 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if locale, rest, ok := s.splitLocalePrefix(r.URL.Path); ok {
+			http.SetCookie(w, &http.Cookie{
+				Name:     localeCookie,
+				Value:    locale,
+				Path:     "/",
+				MaxAge:   365 * 24 * 60 * 60,
+				SameSite: http.SameSiteLaxMode,
+			})
+			if locale == s.defaultLocale() {
+				target := rest
+				if r.URL.RawQuery != "" {
+					target += "?" + r.URL.RawQuery
+				}
+				http.Redirect(w, r, target, http.StatusFound)
+				return
+			}
+			prefixed := r.Clone(context.WithValue(r.Context(), localeContextKey{}, locale))
+			prefixed.URL.Path = rest
+			prefixed.URL.RawPath = ""
+			w.Header().Set("Content-Language", locale)
+			next.ServeHTTP(w, prefixed)
+			return
+		}
+
+		locale := s.defaultLocale()
+		if cookie, err := r.Cookie(localeCookie); err == nil && i18n.Match(cookie.Value, s.locales) != "" {
+			locale = i18n.Match(cookie.Value, s.locales)
+		} else if negotiated := i18n.Negotiate(r.Header.Get("Accept-Language"), s.locales); negotiated != "" {
+			locale = negotiated
+		}
+		w.Header().Add("Vary", "Accept-Language, Cookie")
+		w.Header().Set("Content-Language", locale)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), localeContextKey{}, locale)))
+	})
+}
+
+// localeAlternates lists the hreflang URLs of path in every locale, plus
+// x-default for the unprefixed URL.
+func (s *server) localeAlternates(r *http.Request, path string) []localeAlternate {
+	if len(s.locales) < 2 {
+		return nil
+	}
+	out := make([]localeAlternate, 0, len(s.locales)+1)
+	for _, locale := range s.locales {
+		out = append(out, localeAlternate{Hreflang: locale, URL: s.absoluteURL(r, s.localePath(locale, path))})
+	}
+	return append(out, localeAlternate{Hreflang: "x-default", URL: s.absoluteURL(r, path)})
+}
+
+// localeLinks builds the language switcher. Links always carry the prefix
+// so following one stores the choice, even for the default locale.
+func (s *server) localeLinks(r *http.Request, path string) []localeLink {
+	if len(s.locales) < 2 {
+		return nil
+	}
+	current := s.locale(r)
+	out := make([]localeLink, 0, len(s.locales))
+	for _, locale := range s.locales {
+		out = append(out, localeLink{Label: i18n.NativeName(locale), Href: "/" + locale + path, Current: locale == current})
+	}
+	return out
+}
@@ -0,0 +1,217 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	adminauth "github.com/Its-donkey/Sharpen-live/internal/alert/admin/auth"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/config"
+)
+
+func localeTestServer(t *testing.T) *server {
+	t.Helper()
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: apiTestRecords()}
+	srv.locales = []string{"en", "fr", "de"}
+	sites := filepath.Join("..", "..", "..", "ui", "sites")
+	sets, _, catalog, err := loadSiteTemplates(srv.locales, filepath.Join(sites, "synth-wave", "templates"), filepath.Join(sites, "default-site", "templates"))
+	if err != nil {
+		t.Fatalf("load templates: %v", err)
+	}
+	srv.localeTemplates = sets
+	srv.templates = sets["en"]
+	srv.catalog = catalog
+	return srv
+}
+
+func serveLocalized(srv *server, req *http.Request) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/{$}", srv.handleHome)
+	mux.HandleFunc("/sitemap.xml", srv.handleSitemap)
+	rr := httptest.NewRecorder()
+	srv.localeHandler(mux).ServeHTTP(rr, req)
+	return rr
+}
+
+func TestSiteLocales(t *testing.T) {
+	tests := []struct {
+		name string
+		app  config.AppConfig
+		want []string
+	}{
+		{"unset", config.AppConfig{}, []string{"en"}},
+		{"list", config.AppConfig{Locales: []string{"en", "FR", "fr", "pt_BR"}}, []string{"en", "fr", "pt-br"}},
+		{"default", config.AppConfig{Locales: []string{"en", "fr"}, DefaultLocale: "fr"}, []string{"fr", "en"}},
+	}
+	for _, tt := range tests {
+		if got := siteLocales(tt.app); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestLocaleNegotiation(t *testing.T) {
+	srv := localeTestServer(t)
+
+	tests := []struct {
+		name     string
+		target   string
+		language string
+		cookie   string
+		want     string
+	}{
+		{"default", "/", "", "", "en"},
+		{"accept-language", "/", "fr-CA,fr;q=0.9,en;q=0.5", "", "fr"},
+		{"unsupported", "/", "ja", "", "en"},
+		{"cookie wins", "/", "fr", "de", "de"},
+		{"prefix wins", "/fr/", "de", "de", "fr"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		if tt.language != "" {
+			req.Header.Set("Accept-Language", tt.language)
+		}
+		if tt.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: localeCookie, Value: tt.cookie})
+		}
+		rr := serveLocalized(srv, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", tt.name, rr.Code)
+		}
+		if got := rr.Header().Get("Content-Language"); got != tt.want {
+			t.Fatalf("%s: expected Content-Language %q, got %q", tt.name, tt.want, got)
+		}
+		if !strings.Contains(rr.Body.String(), `<html lang="`+tt.want+`"`) {
+			t.Fatalf("%s: expected html lang %q", tt.name, tt.want)
+		}
+	}
+
+	rr := serveLocalized(srv, httptest.NewRequest(http.MethodGet, "/", nil))
+	if vary := rr.Header().Get("Vary"); !strings.Contains(vary, "Accept-Language") {
+		t.Fatalf("expected unprefixed pages to vary on Accept-Language, got %q", vary)
+	}
+
+	rr = serveLocalized(srv, httptest.NewRequest(http.MethodGet, "/fr/", nil))
+	cookies := rr.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != localeCookie || cookies[0].Value != "fr" {
+		t.Fatalf("expected prefixed request to remember fr, got %+v", cookies)
+	}
+
+	rr = serveLocalized(srv, httptest.NewRequest(http.MethodGet, "/en/?page=2", nil))
+	if rr.Code != http.StatusFound || rr.Header().Get("Location") != "/?page=2" {
+		t.Fatalf("expected default prefix to redirect to the plain URL, got %d %q", rr.Code, rr.Header().Get("Location"))
+	}
+}
+
+func TestLocalizedHomePage(t *testing.T) {
+	srv := localeTestServer(t)
+
+	rr := serveLocalized(srv, httptest.NewRequest(http.MethodGet, "/fr/", nil))
+	body := rr.Body.String()
+	for _, want := range []string{
+		"Rejoindre la vague",
+		"Tous droits réservés.",
+		`<link rel="canonical" href="http://example.com/fr/">`,
+		`<link rel="alternate" hreflang="de" href="http://example.com/de/">`,
+		`<link rel="alternate" hreflang="x-default" href="http://example.com/">`,
+		`<a href="/en/">English</a>`,
+		`<a href="/fr/" aria-current="true">Français</a>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected French page to contain %q, got %s", want, body)
+		}
+	}
+
+	rr = serveLocalized(srv, httptest.NewRequest(http.MethodGet, "/", nil))
+	if body := rr.Body.String(); !strings.Contains(body, "Join the Wave") || !strings.Contains(body, `<link rel="canonical" href="http://example.com/">`) {
+		t.Fatalf("expected English page at the plain URL, got %s", body)
+	}
+}
+
+func TestSitemapListsLocaleAlternates(t *testing.T) {
+	srv := localeTestServer(t)
+
+	rr := serveLocalized(srv, httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil))
+	body := rr.Body.String()
+	for _, want := range []string{
+		`xmlns:xhtml="http://www.w3.org/1999/xhtml"`,
+		"<loc>http://example.com/fr/streamers/Charlie</loc>",
+		`<xhtml:link rel="alternate" hreflang="de" href="http://example.com/de/streamers/Charlie"></xhtml:link>`,
+		`<xhtml:link rel="alternate" hreflang="x-default" href="http://example.com/streamers/Charlie"></xhtml:link>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected sitemap to contain %q, got %s", want, body)
+		}
+	}
+}
+
+func TestAdminFlashIsLocalized(t *testing.T) {
+	srv := localeTestServer(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/login", strings.NewReader("email=&password="))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(&http.Cookie{Name: localeCookie, Value: "fr"})
+	rr := httptest.NewRecorder()
+	srv.localeHandler(http.HandlerFunc(srv.handleAdminLogin)).ServeHTTP(rr, req)
+	if location := rr.Header().Get("Location"); !strings.Contains(location, "L%E2%80%99e-mail") {
+		t.Fatalf("expected French flash message, got %q", location)
+	}
+}
+
+func TestAdminPageIsLocalized(t *testing.T) {
+	srv := localeTestServer(t)
+	srv.adminManager = &stubAdminManager{token: adminauth.Token{Value: "tok"}, valid: true}
+	srv.adminSubmissions = &stubAdminSubmissions{}
+
+	render := func(loggedIn bool) string {
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		req.AddCookie(&http.Cookie{Name: localeCookie, Value: "fr"})
+		if loggedIn {
+			req.AddCookie(&http.Cookie{Name: adminCookieName, Value: "tok"})
+		}
+		rr := httptest.NewRecorder()
+		srv.localeHandler(http.HandlerFunc(srv.handleAdmin)).ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		return rr.Body.String()
+	}
+	if body := render(false); !strings.Contains(body, "Mot de passe") || strings.Contains(body, ">Password<") {
+		t.Fatalf("expected a French login form, got %q", body)
+	}
+	body := render(true)
+	for _, want := range []string{"Propositions en attente", "Se déconnecter", "Retour au site"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %q on the French admin page", want)
+		}
+	}
+}
+
+func TestWidgetLocaleParameter(t *testing.T) {
+	srv := localeTestServer(t)
+	tests := []struct {
+		target string
+		cookie string
+		want   string
+	}{
+		{"/widget?limit=1", "", "more on"},
+		{"/widget?limit=1&locale=fr", "", "de plus sur"},
+		{"/widget?limit=1&locale=fr-CA&language=English", "de", "de plus sur"},
+		{"/widget?limit=1&locale=xx", "fr", "de plus sur"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		if tt.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: localeCookie, Value: tt.cookie})
+		}
+		rr := httptest.NewRecorder()
+		srv.localeHandler(http.HandlerFunc(srv.handleWidget)).ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), tt.want) {
+			t.Fatalf("%s: expected %q, got %d %s", tt.target, tt.want, rr.Code, rr.Body.String())
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Its-donkey/Sharpen-live/internal/ui/model"
+)
+
+const (
+	oembedDefaultWidth  = 400
+	oembedDefaultHeight = 140
+	oembedMinWidth      = 200
+	// socialImageWidth and socialImageHeight are the bundled og-image.png size.
+	socialImageWidth  = 1200
+	socialImageHeight = 630
+)
+
+// oembedResponse is a rich oEmbed 1.0 response.
+type oembedResponse struct {
+	Version         string `json:"version"`
+	Type            string `json:"type"`
+	Title           string `json:"title"`
+	AuthorName      string `json:"author_name"`
+	AuthorURL       string `json:"author_url"`
+	ProviderName    string `json:"provider_name"`
+	ProviderURL     string `json:"provider_url"`
+	CacheAge        int    `json:"cache_age"`
+	HTML            string `json:"html"`
+	Width           int    `json:"width"`
+	Height          int    `json:"height"`
+	ThumbnailURL    string `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth  int    `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight int    `json:"thumbnail_height,omitempty"`
+}
+
+// handleOEmbed serves /oembed?url=...&format=json for streamer profile URLs
+// on this site. Only JSON is offered; other formats get 501 as the oEmbed
+// spec requires, and URLs for other hosts or unknown streamers get 404.
+func (s *server) handleOEmbed(w http.ResponseWriter, r *http.Request) {
+	if !allowRead(w, r) {
+		return
+	}
+	query := r.URL.Query()
+	if format := query.Get("format"); format != "" && format != "json" {
+		http.Error(w, "only the json format is supported", http.StatusNotImplemented)
+		return
+	}
+	raw := strings.TrimSpace(query.Get("url"))
+	if raw == "" {
+		http.Error(w, "url is required", http.StatusBadRequest)
+		return
+	}
+	target, err := url.Parse(raw)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || !s.servesHost(r, target.Host) {
+		http.NotFound(w, r)
+		return
+	}
+	// Streamer pages in other languages advertise their /<locale>/ URL.
+	locale, targetPath := s.defaultLocale(), target.Path
+	if prefixed, rest, ok := s.splitLocalePrefix(targetPath); ok {
+		locale, targetPath = prefixed, rest
+	}
+	alias, ok := strings.CutPrefix(strings.TrimSuffix(targetPath, "/"), "/streamers/")
+	if !ok || alias == "" || strings.Contains(alias, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	streamer, err := s.findStreamer(alias)
+	if err != nil {
+		http.Error(w, "failed to load streamer", http.StatusInternalServerError)
+		return
+	}
+	if streamer.ID == "" && streamer.Name == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	width := oembedDefaultWidth
+	if maxWidth, err := strconv.Atoi(query.Get("maxwidth")); err == nil && maxWidth > 0 {
+		width = max(min(width, maxWidth), oembedMinWidth)
+	}
+	height := oembedDefaultHeight
+	if maxHeight, err := strconv.Atoi(query.Get("maxheight")); err == nil && maxHeight > 0 {
+		height = min(height, maxHeight)
+	}
+
+	path := "/streamers/" + url.PathEscape(streamer.Name)
+	page := s.absoluteURL(r, s.localePath(locale, path))
+	title := streamer.Name
+	if streamer.Status == "online" {
+		title = fmt.Sprintf("%s is %s", streamer.Name, badgeMessage(streamer))
+	}
+	resp := oembedResponse{
+		Version:      "1.0",
+		Type:         "rich",
+		Title:        title,
+		AuthorName:   streamer.Name,
+		AuthorURL:    page,
+		ProviderName: s.siteName,
+		ProviderURL:  s.absoluteURL(r, "/"),
+		CacheAge:     60,
+		HTML:         oembedHTML(streamer, page, s.absoluteURL(r, path+"/badge.svg"), width),
+		Width:        width,
+		Height:       height,
+	}
+	if thumb := liveYouTubeThumbnail(streamer); thumb != "" {
+		resp.ThumbnailURL, resp.ThumbnailWidth, resp.ThumbnailHeight = thumb, 480, 360
+	} else if social := s.socialImageURL(r); social != "" {
+		resp.ThumbnailURL, resp.ThumbnailWidth, resp.ThumbnailHeight = social, socialImageWidth, socialImageHeight
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	writeCachedJSON(w, r, resp)
+}
+
+// servesHost reports whether host is one this site answers for: the request's
+// own host, the canonical host, or any of server.hosts.
+func (s *server) servesHost(r *http.Request, host string) bool {
+	host = normaliseHost(host)
+	if host == "" {
+		return false
+	}
+	if host == normaliseHost(r.Host) || host == normaliseHost(s.primaryHost) {
+		return true
+	}
+	return containsString(s.hosts, host)
+}
+
+// oembedHTML is the embed snippet: the streamer's name linking to their
+// profile, the live badge and the description.
+func oembedHTML(streamer model.Streamer, page, badge string, width int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<blockquote class="sharpen-live-embed" style="max-width:%dpx;margin:0;padding:12px 16px;border-left:4px solid %s;font-family:system-ui,sans-serif">`, width, badgeColour(streamer.Status))
+	fmt.Fprintf(&b, `<a href="%s" target="_blank" rel="noopener"><strong>%s</strong></a>`, html.EscapeString(page), html.EscapeString(streamer.Name))
+	fmt.Fprintf(&b, ` <img src="%s" alt="%s" height="20" style="vertical-align:middle">`, html.EscapeString(badge), html.EscapeString(badgeMessage(streamer)))
+	if desc := strings.TrimSpace(streamer.Description); desc != "" {
+		fmt.Fprintf(&b, `<p style="margin:8px 0 0">%s</p>`, html.EscapeString(truncateWithEllipsis(desc, 200)))
+	}
+	b.WriteString(`</blockquote>`)
+	return b.String()
+}
+
+// liveYouTubeThumbnail returns the current broadcast's thumbnail when the
+// streamer is live on YouTube with a known video.
+func liveYouTubeThumbnail(streamer model.Streamer) string {
+	for _, p := range streamer.Platforms {
+		if p.Name != "YouTube" || !p.Live {
+			continue
+		}
+		watch, err := url.Parse(p.ChannelURL)
+		if err != nil {
+			return ""
+		}
+		if vid := watch.Query().Get("v"); vid != "" {
+			return "https://i.ytimg.com/vi/" + url.PathEscape(vid) + "/hqdefault.jpg"
+		}
+	}
+	return ""
+}
+
+// oembedDiscoveryURL is the oEmbed endpoint for a page, advertised with
+// <link rel="alternate" type="application/json+oembed">.
+func (s *server) oembedDiscoveryURL(r *http.Request, pageURL string) string {
+	return s.absoluteURL(r, "/oembed?format=json&url="+url.QueryEscape(pageURL))
+}
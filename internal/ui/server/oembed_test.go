@@ -0,0 +1,133 @@
+package server
+
+import (
+	"encoding/json"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func getOEmbed(t *testing.T, srv *server, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	srv.handleOEmbed(rr, httptest.NewRequest(http.MethodGet, target, nil))
+	return rr
+}
+
+func TestOEmbedRichResponse(t *testing.T) {
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: apiTestRecords()}
+
+	rr := getOEmbed(t, srv, "/oembed?format=json&maxwidth=300&url="+url.QueryEscape("https://example.com/streamers/Charlie"))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp oembedResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Version != "1.0" || resp.Type != "rich" || resp.Width != 300 || resp.Height != oembedDefaultHeight {
+		t.Fatalf("unexpected envelope %+v", resp)
+	}
+	if resp.Title != "Charlie is live on YouTube" || resp.AuthorURL != "http://example.com/streamers/Charlie" {
+		t.Fatalf("expected live title and profile URL, got %+v", resp)
+	}
+	if resp.ThumbnailURL != "https://i.ytimg.com/vi/vid/hqdefault.jpg" {
+		t.Fatalf("expected live stream thumbnail, got %q", resp.ThumbnailURL)
+	}
+	if !strings.Contains(resp.HTML, `src="http://example.com/streamers/Charlie/badge.svg"`) || strings.Contains(resp.HTML, "c@example.com") {
+		t.Fatalf("unexpected embed html %s", resp.HTML)
+	}
+
+	rr = getOEmbed(t, srv, "/oembed?url="+url.QueryEscape("http://example.com/streamers/a"))
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Title != "alpha" || resp.ThumbnailURL != "http://example.com/og-image.png" || resp.ThumbnailWidth != socialImageWidth {
+		t.Fatalf("expected offline streamer with social image, got %+v", resp)
+	}
+}
+
+func TestOEmbedLocalePrefixedURL(t *testing.T) {
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: apiTestRecords()}
+	srv.locales = []string{"en", "fr"}
+
+	rr := getOEmbed(t, srv, "/oembed?url="+url.QueryEscape("https://example.com/fr/streamers/Charlie"))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp oembedResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.AuthorURL != "http://example.com/fr/streamers/Charlie" {
+		t.Fatalf("expected the French profile URL, got %q", resp.AuthorURL)
+	}
+	if rr := getOEmbed(t, srv, "/oembed?url="+url.QueryEscape("https://example.com/xx/streamers/Charlie")); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected an unsupported prefix to 404, got %d", rr.Code)
+	}
+}
+
+func TestOEmbedValidatesURL(t *testing.T) {
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: apiTestRecords()}
+	srv.hosts = []string{"sharpen.live"}
+
+	tests := []struct {
+		name   string
+		target string
+		want   int
+	}{
+		{"missing url", "/oembed?format=json", http.StatusBadRequest},
+		{"xml", "/oembed?format=xml&url=https%3A%2F%2Fexample.com%2Fstreamers%2FCharlie", http.StatusNotImplemented},
+		{"foreign host", "/oembed?url=https%3A%2F%2Fevil.example%2Fstreamers%2FCharlie", http.StatusNotFound},
+		{"non-http scheme", "/oembed?url=javascript%3Aalert(1)", http.StatusNotFound},
+		{"not a profile", "/oembed?url=https%3A%2F%2Fexample.com%2Fsubmit", http.StatusNotFound},
+		{"badge path", "/oembed?url=https%3A%2F%2Fexample.com%2Fstreamers%2FCharlie%2Fbadge.svg", http.StatusNotFound},
+		{"unknown streamer", "/oembed?url=https%3A%2F%2Fexample.com%2Fstreamers%2Fnobody", http.StatusNotFound},
+		{"configured host", "/oembed?url=https%3A%2F%2FSharpen.Live%2Fstreamers%2FCharlie%2F", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rr := getOEmbed(t, srv, tt.target); rr.Code != tt.want {
+				t.Fatalf("expected %d, got %d: %s", tt.want, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestStreamerPageAdvertisesOEmbed(t *testing.T) {
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: apiTestRecords()}
+	sites := filepath.Join("..", "..", "..", "ui", "sites")
+	templates, _, err := loadTemplates(filepath.Join(sites, "sharpen-live", "templates"), filepath.Join(sites, "default-site", "templates"))
+	if err != nil {
+		t.Fatalf("load templates: %v", err)
+	}
+	srv.templates = templates
+
+	rr := httptest.NewRecorder()
+	srv.handleStreamer(rr, httptest.NewRequest(http.MethodGet, "/streamers/Charlie", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := html.UnescapeString(rr.Body.String())
+	want := `<link rel="alternate" type="application/json+oembed" href="http://example.com/oembed?format=json&url=http%3A%2F%2Fexample.com%2Fstreamers%2FCharlie"`
+	if !strings.Contains(body, want) {
+		t.Fatalf("expected oEmbed discovery link, got %s", body)
+	}
+	if !strings.Contains(body, `<img src="http://example.com/streamers/Charlie/badge.svg"`) {
+		t.Fatalf("expected badge embed code on the profile page")
+	}
+
+	rr = httptest.NewRecorder()
+	srv.handleHome(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if strings.Contains(rr.Body.String(), "json+oembed") {
+		t.Fatalf("home page should not advertise oEmbed")
+	}
+}
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// pageCacheLimit bounds the rendered pages kept per site; the oldest entry
+// is dropped first.
+const pageCacheLimit = 256
+
+// pageCache keeps rendered public pages keyed by locale, path and query.
+// Entries are stamped with the roster file they were rendered from and
+// discarded once it changes, which also catches writes made by other sites
+// sharing the roster. Templates are loaded when the site's server is built,
+// so a reload starts with an empty cache.
+type pageCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedPage
+	order   []string
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// pageCacheStats is the hit/miss counter shown on the admin dashboard.
+type pageCacheStats struct {
+	Entries int
+	Hits    uint64
+	Misses  uint64
+}
+
+type rosterStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+type cachedPage struct {
+	stamp  rosterStamp
+	header http.Header
+	body   []byte
+}
+
+func newPageCache() *pageCache {
+	return &pageCache{entries: map[string]*cachedPage{}}
+}
+
+func (c *pageCache) get(key string, stamp rosterStamp) *cachedPage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	page := c.entries[key]
+	if page == nil || page.stamp != stamp {
+		return nil
+	}
+	return page
+}
+
+func (c *pageCache) put(key string, page *cachedPage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		c.order = append(c.order, key)
+	}
+	c.entries[key] = page
+	for len(c.order) > pageCacheLimit {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+func (c *pageCache) stats() pageCacheStats {
+	if c == nil {
+		return pageCacheStats{}
+	}
+	c.mu.Lock()
+	entries := len(c.entries)
+	c.mu.Unlock()
+	return pageCacheStats{Entries: entries, Hits: c.hits.Load(), Misses: c.misses.Load()}
+}
+
+// rosterStamp identifies the current roster file contents.
+func (s *server) rosterStamp() (rosterStamp, bool) {
+	if s.streamersStore == nil {
+		return rosterStamp{}, false
+	}
+	info, err := os.Stat(s.streamersStore.Path())
+	if err != nil {
+		return rosterStamp{}, false
+	}
+	return rosterStamp{modTime: info.ModTime(), size: info.Size()}, true
+}
+
+// cachePage serves next from the page cache when its roster is unchanged.
+// Only successful GETs are stored; logged-in admins always get a fresh
+// render, as do sites without a roster file.
+func (s *server) cachePage(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.pages == nil || r.Method != http.MethodGet || s.adminTokenFromRequest(r) != "" {
+			next(w, r)
+			return
+		}
+		stamp, ok := s.rosterStamp()
+		if !ok {
+			next(w, r)
+			return
+		}
+		key := s.siteKey + "\x00" + s.locale(r) + "\x00" + r.URL.Path + "?" + r.URL.RawQuery
+		if page := s.pages.get(key, stamp); page != nil {
+			s.pages.hits.Add(1)
+			page.write(w, r)
+			return
+		}
+		s.pages.misses.Add(1)
+
+		rec := &pageRecorder{header: http.Header{}, status: http.StatusOK}
+		next(rec, r)
+		if rec.status == http.StatusOK {
+			s.pages.put(key, &cachedPage{stamp: stamp, header: rec.header, body: rec.body.Bytes()})
+		}
+		for name, values := range rec.header {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(rec.status)
+		_, _ = w.Write(rec.body.Bytes())
+	}
+}
+
+// write replays the page, answering If-Modified-Since like the handlers do.
+func (p *cachedPage) write(w http.ResponseWriter, r *http.Request) {
+	for name, values := range p.header {
+		w.Header()[name] = values
+	}
+	if modTime, err := http.ParseTime(p.header.Get("Last-Modified")); err == nil {
+		if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !modTime.After(since) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(p.body)
+}
+
+// pageRecorder buffers a handler's response so it can be cached.
+type pageRecorder struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (p *pageRecorder) Header() http.Header {
+	return p.header
+}
+
+func (p *pageRecorder) WriteHeader(status int) {
+	if p.wroteHeader {
+		return
+	}
+	p.wroteHeader = true
+	p.status = status
+}
+
+func (p *pageRecorder) Write(b []byte) (int, error) {
+	p.WriteHeader(http.StatusOK)
+	return p.body.Write(b)
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	adminauth "github.com/Its-donkey/Sharpen-live/internal/alert/admin/auth"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+)
+
+// pageCacheTestServer renders the synth-wave theme from a real roster file
+// holding count streamers.
+func pageCacheTestServer(tb testing.TB, count int) (*server, *streamers.Store) {
+	tb.Helper()
+	store := streamers.NewStore(filepath.Join(tb.TempDir(), "streamers.json"))
+	for i := 0; i < count; i++ {
+		_, err := store.Append(streamers.Record{Streamer: streamers.Streamer{
+			ID:          fmt.Sprintf("s%03d", i),
+			Alias:       fmt.Sprintf("Streamer%03d", i),
+			Description: "Late night synthwave sets.",
+			Languages:   []string{"English"},
+		}})
+		if err != nil {
+			tb.Fatalf("append: %v", err)
+		}
+	}
+	srv := newTestServer()
+	srv.streamersStore = store
+	sites := filepath.Join("..", "..", "..", "ui", "sites")
+	templates, _, err := loadTemplates(filepath.Join(sites, "synth-wave", "templates"), filepath.Join(sites, "default-site", "templates"))
+	if err != nil {
+		tb.Fatalf("load templates: %v", err)
+	}
+	srv.templates = templates
+	srv.pages = newPageCache()
+	return srv, store
+}
+
+func TestPageCache(t *testing.T) {
+	srv, store := pageCacheTestServer(t, 3)
+	handler := srv.cachePage(srv.handleHome)
+	get := func(target string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+		return rr
+	}
+
+	first := get("/")
+	second := get("/")
+	if first.Code != http.StatusOK || second.Body.String() != first.Body.String() || second.Header().Get("Last-Modified") == "" {
+		t.Fatalf("expected the cached page to match the render, got %d", second.Code)
+	}
+	if stats := srv.pages.stats(); stats.Hits != 1 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Fatalf("unexpected stats after a repeat request: %+v", stats)
+	}
+
+	get("/?q=Streamer001")
+	if stats := srv.pages.stats(); stats.Misses != 2 || stats.Entries != 2 {
+		t.Fatalf("expected the query to be part of the key: %+v", stats)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-Modified-Since", first.Header().Get("Last-Modified"))
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+	if rr.Code != http.StatusNotModified || rr.Body.Len() != 0 {
+		t.Fatalf("expected a cached 304, got %d", rr.Code)
+	}
+
+	if _, err := store.Append(streamers.Record{Streamer: streamers.Streamer{ID: "new", Alias: "Newcomer", Languages: []string{"English"}}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	hits := srv.pages.stats().Hits
+	if rr := get("/"); !strings.Contains(rr.Body.String(), "Newcomer") || srv.pages.stats().Hits != hits {
+		t.Fatalf("expected a roster write to invalidate the page")
+	}
+
+	srv.adminManager = &stubAdminManager{valid: true, token: adminauth.Token{Value: "tok"}}
+	before := srv.pages.stats()
+	get("/", &http.Cookie{Name: adminCookieName, Value: "tok"})
+	if after := srv.pages.stats(); after.Hits != before.Hits || after.Misses != before.Misses {
+		t.Fatalf("expected logged-in admins to bypass the cache: %+v", after)
+	}
+}
+
+func TestPageCacheSkipsErrors(t *testing.T) {
+	srv, _ := pageCacheTestServer(t, 1)
+	handler := srv.cachePage(srv.handleStreamer)
+	for i := 0; i < 2; i++ {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest(http.MethodGet, "/streamers/missing", nil))
+		if rr.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rr.Code)
+		}
+	}
+	if stats := srv.pages.stats(); stats.Entries != 0 || stats.Misses != 2 {
+		t.Fatalf("expected error responses to stay uncached: %+v", stats)
+	}
+}
+
+func BenchmarkHandleHome(b *testing.B) {
+	for _, bc := range []struct {
+		name  string
+		cache bool
+	}{
+		{"uncached", false},
+		{"cached", true},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			srv, _ := pageCacheTestServer(b, 200)
+			handler := srv.handleHome
+			if bc.cache {
+				handler = srv.cachePage(srv.handleHome)
+			}
+			// FailNow must not be called from RunParallel's goroutines, so
+			// failures are counted and checked once they finish.
+			var failed atomic.Int64
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					rr := httptest.NewRecorder()
+					handler(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+					if rr.Code != http.StatusOK {
+						failed.Add(1)
+					}
+				}
+			})
+			if n := failed.Load(); n > 0 {
+				b.Fatalf("%d requests did not return 200", n)
+			}
+		})
+	}
+}
@@ -111,11 +111,13 @@ func (s *server) buildBasePageData(r *http.Request, title, description, canonica
 		title = s.siteName
 	}
 
-	canonical := s.absoluteURL(r, canonicalPath)
+	locale := s.locale(r)
+	canonical := s.absoluteURL(r, s.localePath(locale, canonicalPath))
 
 	return basePageData{
 		PageTitle:       title,
-		StylesheetPath:  s.stylesPath,
+		StylesheetPath:  s.assetURL(s.stylesPath),
+		ScriptPath:      s.assetURL("/submit.js"),
 		SubmitLink:      "/#submit",
 		CurrentYear:     s.currentYear,
 		SiteName:        s.siteName,
@@ -124,6 +126,9 @@ func (s *server) buildBasePageData(r *http.Request, title, description, canonica
 		SocialImage:     s.socialImageURL(r),
 		OGType:          "website",
 		Robots:          "",
+		Locale:          locale,
+		Alternates:      s.localeAlternates(r, canonicalPath),
+		LocaleLinks:     s.localeLinks(r, canonicalPath),
 		FallbackErrors:  s.fallbackErrors,
 	}
 }
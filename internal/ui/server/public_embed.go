@@ -0,0 +1,199 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Its-donkey/Sharpen-live/internal/ui/i18n"
+	"github.com/Its-donkey/Sharpen-live/internal/ui/model"
+)
+
+const (
+	widgetDefaultLimit = 10
+	widgetMaxLimit     = 50
+)
+
+// widgetThemes are the accepted values of the widget's theme parameter;
+// "site" keeps the site's own stylesheet colours.
+var widgetThemes = map[string]bool{"site": true, "light": true, "dark": true}
+
+// badgeColours maps streamer status to the badge's right-hand colour.
+var badgeColours = map[string]string{
+	"online":  "#16a34a",
+	"busy":    "#ca8a04",
+	"offline": "#64748b",
+}
+
+// serveStreamerBadge renders /streamers/{alias}/badge.svg, a shields-style
+// badge showing whether the streamer is live and on which platform.
+func (s *server) serveStreamerBadge(w http.ResponseWriter, r *http.Request, alias string) {
+	streamer, err := s.findStreamer(alias)
+	if err != nil {
+		http.Error(w, "failed to load streamer", http.StatusInternalServerError)
+		return
+	}
+	if streamer.ID == "" && streamer.Name == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	writeCachedBody(w, r, "image/svg+xml; charset=utf-8", renderBadge(s.siteName, badgeMessage(streamer), badgeColour(streamer.Status)))
+}
+
+// badgeMessage describes the streamer's state, naming the live platforms or,
+// when offline, the platforms they stream on.
+func badgeMessage(streamer model.Streamer) string {
+	var live, all []string
+	for _, p := range streamer.Platforms {
+		all = append(all, p.Name)
+		if p.Live {
+			live = append(live, p.Name)
+		}
+	}
+	if streamer.Status == "online" {
+		if len(live) == 0 {
+			return "live"
+		}
+		return "live on " + strings.Join(live, ", ")
+	}
+	label := strings.ToLower(streamer.StatusLabel)
+	if label == "" {
+		label = "offline"
+	}
+	if len(all) == 0 {
+		return label
+	}
+	return label + " · " + strings.Join(all, ", ")
+}
+
+func badgeColour(status string) string {
+	if colour, ok := badgeColours[status]; ok {
+		return colour
+	}
+	return badgeColours["offline"]
+}
+
+// renderBadge draws a two-part flat badge. Text widths are estimated from the
+// character count, which is close enough for the 11px sans-serif used.
+func renderBadge(label, message, colour string) []byte {
+	if strings.TrimSpace(label) == "" {
+		label = "Sharpen.Live"
+	}
+	textWidth := func(s string) int { return len([]rune(s))*7 + 10 }
+	lw, mw := textWidth(label), textWidth(message)
+	total := lw + mw
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`, total, html.EscapeString(label), html.EscapeString(message))
+	fmt.Fprintf(&b, `<title>%s: %s</title>`, html.EscapeString(label), html.EscapeString(message))
+	fmt.Fprintf(&b, `<clipPath id="r"><rect width="%d" height="20" rx="3"/></clipPath>`, total)
+	fmt.Fprintf(&b, `<g clip-path="url(#r)"><rect width="%d" height="20" fill="#1e293b"/><rect x="%d" width="%d" height="20" fill="%s"/></g>`, lw, lw, mw, colour)
+	b.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,DejaVu Sans,sans-serif" font-size="11">`)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, lw/2, html.EscapeString(label))
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, lw+mw/2, html.EscapeString(message))
+	b.WriteString(`</g></svg>`)
+	return b.Bytes()
+}
+
+// handleWidget serves /widget, a compact roster meant to be embedded in an
+// iframe on partner sites. Query parameters: theme (site, light or dark),
+// locale (the widget's UI language, one of the site's locales), language
+// (only streamers speaking it), live (1 to list live streamers only) and
+// limit.
+func (s *server) handleWidget(w http.ResponseWriter, r *http.Request) {
+	if !allowRead(w, r) {
+		return
+	}
+	query := r.URL.Query()
+	// The embedding page picks the widget's language; the frame's own cookie
+	// and Accept-Language only apply when it does not.
+	if locale := i18n.Match(query.Get("locale"), s.locales); locale != "" {
+		r = r.WithContext(context.WithValue(r.Context(), localeContextKey{}, locale))
+		w.Header().Set("Content-Language", locale)
+	}
+	theme := strings.ToLower(strings.TrimSpace(query.Get("theme")))
+	if !widgetThemes[theme] {
+		theme = "site"
+	}
+	language := strings.TrimSpace(query.Get("language"))
+	liveOnly := query.Get("live") == "1" || query.Get("live") == "true"
+	limit := widgetDefaultLimit
+	if raw := query.Get("limit"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+			limit = min(n, widgetMaxLimit)
+		}
+	}
+
+	roster, rosterErr := s.fetchRoster(r.Context())
+	var listed []model.Streamer
+	for _, streamer := range roster {
+		if liveOnly && streamer.Status != "online" {
+			continue
+		}
+		if language != "" && !containsFold(streamer.Languages, language) {
+			continue
+		}
+		listed = append(listed, streamer)
+	}
+	// Live streamers lead; fetchRoster already put featured ones first.
+	sort.SliceStable(listed, func(i, j int) bool {
+		return listed[i].Status == "online" && listed[j].Status != "online"
+	})
+	total := len(listed)
+	if len(listed) > limit {
+		listed = listed[:limit]
+	}
+
+	data := struct {
+		SiteName       string
+		SiteURL        string
+		Locale         string
+		StylesheetPath string
+		Theme          string
+		Language       string
+		Streamers      []model.Streamer
+		More           int
+		Error          string
+	}{
+		SiteName:       s.siteName,
+		SiteURL:        s.absoluteURL(r, s.localePath(s.locale(r), "/")),
+		Locale:         s.locale(r),
+		StylesheetPath: s.assetURL(s.stylesPath),
+		Theme:          theme,
+		Language:       language,
+		Streamers:      listed,
+		More:           total - len(listed),
+		Error:          rosterErr,
+	}
+
+	tmpl := s.template(r, "widget")
+	if tmpl == nil {
+		http.Error(w, "template missing", http.StatusInternalServerError)
+		return
+	}
+	var body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&body, "widget", data); err != nil {
+		http.Error(w, "failed to render page", http.StatusInternalServerError)
+		return
+	}
+	// The widget is meant to be framed by other sites.
+	w.Header().Set("Content-Security-Policy", "frame-ancestors *")
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	writeCachedBody(w, r, "text/html; charset=utf-8", body.Bytes())
+}
+
+func containsFold(values []string, target string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), target) {
+			return true
+		}
+	}
+	return false
+}
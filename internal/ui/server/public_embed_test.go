@@ -0,0 +1,115 @@
+package server
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Its-donkey/Sharpen-live/internal/ui/model"
+)
+
+func TestStreamerBadge(t *testing.T) {
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: apiTestRecords()}
+
+	tests := []struct {
+		path    string
+		code    int
+		message string
+		colour  string
+	}{
+		{"/streamers/charlie/badge.svg", http.StatusOK, "live on YouTube", badgeColours["online"]},
+		{"/streamers/a/badge.svg", http.StatusOK, "offline · Twitch", badgeColours["offline"]},
+		{"/streamers/nobody/badge.svg", http.StatusNotFound, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			srv.handleStreamer(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rr.Code != tt.code {
+				t.Fatalf("expected %d, got %d", tt.code, rr.Code)
+			}
+			if tt.code != http.StatusOK {
+				return
+			}
+			if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "image/svg+xml") {
+				t.Fatalf("unexpected content type %q", ct)
+			}
+			if cc := rr.Header().Get("Cache-Control"); cc != "public, max-age=60" {
+				t.Fatalf("expected short cache, got %q", cc)
+			}
+			body := rr.Body.String()
+			if err := xml.Unmarshal(rr.Body.Bytes(), new(struct{})); err != nil {
+				t.Fatalf("badge is not well-formed XML: %v", err)
+			}
+			if !strings.Contains(body, "Sharpen.Live: "+tt.message) || !strings.Contains(body, tt.colour) {
+				t.Fatalf("expected %q in %s, got %s", tt.message, tt.colour, body)
+			}
+		})
+	}
+}
+
+func TestBadgeMessageEscapesNames(t *testing.T) {
+	body := string(renderBadge(`<b>&"`, badgeMessage(model.Streamer{Status: "busy", StatusLabel: "Workshop"}), "#000"))
+	if strings.Contains(body, "<b>") || !strings.Contains(body, "&lt;b&gt;&amp;") || !strings.Contains(body, ": workshop") {
+		t.Fatalf("unexpected badge %s", body)
+	}
+}
+
+func TestWidgetFiltersAndThemes(t *testing.T) {
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: apiTestRecords()}
+	templates, _, err := loadTemplates(filepath.Join("..", "..", "..", "ui", "sites", "default-site", "templates"))
+	if err != nil {
+		t.Fatalf("load templates: %v", err)
+	}
+	srv.templates["widget"] = templates["widget"]
+
+	tests := []struct {
+		name    string
+		target  string
+		want    []string
+		notWant []string
+	}{
+		{"default", "/widget", []string{"Charlie", "alpha", "Bravo", `class="widget widget-site"`, `rel="stylesheet"`}, nil},
+		{"language", "/widget?language=english&theme=dark", []string{"Charlie", "Bravo", "widget-dark"}, []string{"alpha", `rel="stylesheet"`}},
+		{"live only", "/widget?live=1", []string{"Charlie"}, []string{"alpha", "Bravo"}},
+		{"limit", "/widget?limit=1&theme=bogus", []string{"Charlie", "+2 more", "widget-site"}, []string{"Bravo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			srv.handleWidget(rr, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+			}
+			if csp := rr.Header().Get("Content-Security-Policy"); csp != "frame-ancestors *" {
+				t.Fatalf("expected widget to allow framing, got %q", csp)
+			}
+			body := rr.Body.String()
+			for _, want := range tt.want {
+				if !strings.Contains(body, want) {
+					t.Fatalf("expected %q in widget", want)
+				}
+			}
+			for _, unwanted := range tt.notWant {
+				if strings.Contains(body, unwanted) {
+					t.Fatalf("did not expect %q in widget", unwanted)
+				}
+			}
+		})
+	}
+	if !strings.Contains(mustWidget(t, srv, "/widget"), `href="https://www.youtube.com/watch?v=vid"`) {
+		t.Fatalf("expected live platform link in widget")
+	}
+}
+
+func mustWidget(t *testing.T, srv *server, target string) string {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	srv.handleWidget(rr, httptest.NewRequest(http.MethodGet, target, nil))
+	return rr.Body.String()
+}
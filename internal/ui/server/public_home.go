@@ -6,6 +6,7 @@ import (
 	"html/template"
 	"net/http"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/Its-donkey/Sharpen-live/internal/alert/config"
@@ -34,14 +35,27 @@ func (s *server) homeStructuredData(homeURL string) template.JS {
 
 func (s *server) assetHandler(name, contentType string) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		path := filepath.Join(s.assetsDir, name)
+		path := s.resolveAsset(name)
 		if contentType != "" {
 			w.Header().Set("Content-Type", contentType)
 		}
-		http.ServeFile(w, r, path)
+		setAssetCacheHeaders(w, r, path)
+		themeFiles.serve(w, r, path)
 	})
 }
 
+// resolveAsset returns the first theme directory's copy of name, falling back
+// to the site's own assets directory.
+func (s *server) resolveAsset(name string) string {
+	for _, dir := range s.assetDirs {
+		path := filepath.Join(dir, name)
+		if themeFiles.exists(path) {
+			return path
+		}
+	}
+	return filepath.Join(s.assetsDir, name)
+}
+
 func (s *server) siteDisplayName() string {
 	if name := strings.TrimSpace(s.siteName); name != "" {
 		return name
@@ -49,24 +63,24 @@ func (s *server) siteDisplayName() string {
 	return "Sharpen.Live"
 }
 
-func (s *server) homePageTitle() string {
+func (s *server) homePageTitle(r *http.Request) string {
 	name := s.siteDisplayName()
 	switch {
 	case strings.EqualFold(s.siteKey, config.AlertserverKey) || strings.EqualFold(name, config.AlertserverKey):
-		return "Site unavailable - review configuration"
+		return s.t(r, "Site unavailable - review configuration")
 	case strings.EqualFold(s.siteKey, "synth-wave") || strings.EqualFold(name, "synth.wave"):
-		return name + " - Live synthwave streams"
+		return s.t(r, "%s - Live synthwave streams", name)
 	default:
-		return name + " - Live knife sharpening streams"
+		return s.t(r, "%s - Live knife sharpening streams", name)
 	}
 }
 
-func (s *server) submitPageTitle() string {
+func (s *server) submitPageTitle(r *http.Request) string {
 	name := s.siteDisplayName()
 	if strings.EqualFold(s.siteKey, config.AlertserverKey) || strings.EqualFold(name, config.AlertserverKey) {
-		return "Submit a streamer"
+		return s.t(r, "Submit a streamer")
 	}
-	return "Submit a streamer - " + name
+	return s.t(r, "Submit a streamer - %s", name)
 }
 
 func (s *server) streamerPageTitle(streamerName string) string {
@@ -83,22 +97,47 @@ func (s *server) handleHome(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
+	if s.rosterNotModified(w, r) {
+		return
+	}
 
 	ctx := r.Context()
 	state, rosterErr := s.fetchRoster(ctx)
 
 	submit := defaultSubmitState(r)
-	page := s.buildBasePageData(r, s.homePageTitle(), s.siteDescription, "/")
+	query := parseRosterQuery(r.URL.Query())
+	page := s.buildBasePageData(r, s.rosterPageTitle(r, query), s.t(r, s.siteDescription), query.url(query.Page))
+	if query.filtered() {
+		// Search and filter results are reachable from the form but only the
+		// plain roster pages are indexed (see sitemap.xml).
+		page.Robots = "noindex, follow"
+	}
 	s.renderHomeWithRoster(w, r, page, state, rosterErr, submit)
 }
 
+// rosterPageTitle is the home page title, noting search results and pages
+// after the first so each roster URL has a distinct title.
+func (s *server) rosterPageTitle(r *http.Request, q rosterQuery) string {
+	title := s.homePageTitle(r)
+	if q.filtered() {
+		title = s.t(r, "Find streamers - %s", s.siteDisplayName())
+		if q.Search != "" {
+			title = s.t(r, "Search: %s - %s", q.Search, s.siteDisplayName())
+		}
+	}
+	if q.Page > 1 {
+		title += " - " + s.t(r, "Page %d", q.Page)
+	}
+	return title
+}
+
 func (s *server) handleSubmit(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	title := s.submitPageTitle()
+	title := s.submitPageTitle(r)
 	switch r.Method {
 	case http.MethodGet:
 		state := defaultSubmitState(r)
-		page := s.buildBasePageData(r, title, s.siteDescription, "/submit")
+		page := s.buildBasePageData(r, title, s.t(r, s.siteDescription), "/submit")
 		s.renderHome(w, r, page, state)
 	case http.MethodPost:
 		state, removedRows, err := parseSubmitForm(r)
@@ -115,20 +154,21 @@ func (s *server) handleSubmit(w http.ResponseWriter, r *http.Request) {
 		if hasSubmitErrors(state.Errors) {
 			ensureSubmitDefaults(&state)
 			w.WriteHeader(http.StatusUnprocessableEntity)
-			page := s.buildBasePageData(r, title, s.siteDescription, "/submit")
+			page := s.buildBasePageData(r, title, s.t(r, s.siteDescription), "/submit")
 			s.renderHome(w, r, page, state)
 			return
 		}
 
 		youtubeui.MaybeEnrichMetadata(ctx, &state, http.DefaultClient)
-		if _, err := submitStreamer(ctx, s.streamerService, state); err != nil {
+		submission, _, err := submitStreamer(ctx, s.streamerService, state)
+		if err != nil {
 			s.logger.Warn("submission", "streamer submission failed", map[string]any{
 				"name":  state.Name,
 				"error": err.Error(),
 			})
-			state.Errors.General = append(state.Errors.General, "failed to submit streamer, please try again")
+			state.Errors.General = append(state.Errors.General, s.t(r, "failed to submit streamer, please try again"))
 			ensureSubmitDefaults(&state)
-			page := s.buildBasePageData(r, title, s.siteDescription, "/submit")
+			page := s.buildBasePageData(r, title, s.t(r, s.siteDescription), "/submit")
 			s.renderHome(w, r, page, state)
 			return
 		}
@@ -138,6 +178,7 @@ func (s *server) handleSubmit(w http.ResponseWriter, r *http.Request) {
 			"description": state.Description,
 			"languages":   state.Languages,
 		})
+		s.notifySubmissionReceived(r, submission)
 		http.Redirect(w, r, "/?submitted=1", http.StatusSeeOther)
 	default:
 		w.Header().Set("Allow", "GET, POST")
@@ -152,6 +193,13 @@ func (s *server) renderHome(w http.ResponseWriter, r *http.Request, page basePag
 
 func (s *server) renderHomeWithRoster(w http.ResponseWriter, r *http.Request, page basePageData, state []model.Streamer, rosterErr string, submit model.SubmitFormState) {
 	page.StructuredData = s.homeStructuredData(s.absoluteURL(r, "/"))
+	query := parseRosterQuery(r.URL.Query())
+	listed, total := query.apply(state)
+	roster := newRosterView(query, state, total)
+	if query.Page > roster.Pages {
+		http.NotFound(w, r)
+		return
+	}
 	submitView := submitFormView{
 		State:           submit,
 		LanguageOptions: forms.AvailableLanguageOptions(submit.Languages),
@@ -162,17 +210,19 @@ func (s *server) renderHomeWithRoster(w http.ResponseWriter, r *http.Request, pa
 		basePageData
 		Roster      []model.Streamer
 		Streamers   []model.Streamer
+		RosterView  rosterView
 		RosterError string
 		Submit      submitFormView
 	}{
 		basePageData: page,
 		Roster:       state,
-		Streamers:    state,
+		Streamers:    listed,
+		RosterView:   roster,
 		RosterError:  rosterErr,
 		Submit:       submitView,
 	}
 
-	tmpl := s.templates["home"]
+	tmpl := s.template(r, "home")
 	if tmpl == nil {
 		http.Error(w, "template missing", http.StatusInternalServerError)
 		return
@@ -190,5 +240,10 @@ func (s *server) fetchRoster(ctx context.Context) ([]model.Streamer, string) {
 	if err != nil {
 		return nil, "failed to load roster"
 	}
-	return mapStreamerRecords(records), ""
+	roster := mapStreamerRecords(records)
+	// Featured streamers lead the roster; everyone else keeps file order.
+	sort.SliceStable(roster, func(i, j int) bool {
+		return roster[i].Featured && !roster[j].Featured
+	})
+	return roster, ""
 }
@@ -0,0 +1,241 @@
+package server
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Its-donkey/Sharpen-live/internal/ui/forms"
+	"github.com/Its-donkey/Sharpen-live/internal/ui/model"
+)
+
+// landingGroup is the set of streamers behind one /languages/{lang} or
+// /countries/{code} page.
+type landingGroup struct {
+	Slug      string
+	Name      string
+	Streamers []model.Streamer
+}
+
+// languageSlug turns a stored language value ("Scottish Gaelic") into its
+// landing page path segment ("scottish-gaelic").
+func languageSlug(language string) string {
+	return strings.Join(strings.Fields(strings.ToLower(language)), "-")
+}
+
+// languageGroups groups the roster by language, keyed by slug. Languages
+// with no listed streamers never appear, so they get no landing page.
+func languageGroups(roster []model.Streamer) map[string]*landingGroup {
+	groups := map[string]*landingGroup{}
+	for _, streamer := range roster {
+		seen := map[string]bool{}
+		for _, lang := range streamer.Languages {
+			slug := languageSlug(lang)
+			if slug == "" || seen[slug] {
+				continue
+			}
+			seen[slug] = true
+			group := groups[slug]
+			if group == nil {
+				group = &landingGroup{Slug: slug, Name: strings.TrimSpace(lang)}
+				groups[slug] = group
+			}
+			group.Streamers = append(group.Streamers, streamer)
+		}
+	}
+	return groups
+}
+
+// countryGroups groups the roster by ISO country code, keyed by the
+// lower-case code used in /countries/{code}.
+func countryGroups(roster []model.Streamer) map[string]*landingGroup {
+	groups := map[string]*landingGroup{}
+	for _, streamer := range roster {
+		if streamer.Country == "" {
+			continue
+		}
+		slug := strings.ToLower(streamer.Country)
+		group := groups[slug]
+		if group == nil {
+			group = &landingGroup{Slug: slug, Name: model.CountryNames[streamer.Country]}
+			groups[slug] = group
+		}
+		group.Streamers = append(group.Streamers, streamer)
+	}
+	return groups
+}
+
+// sortedLandingGroups returns groups ordered by name for sitemaps and indexes.
+func sortedLandingGroups(groups map[string]*landingGroup) []*landingGroup {
+	out := make([]*landingGroup, 0, len(groups))
+	for _, group := range groups {
+		out = append(out, group)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func (s *server) handleLanguagePage(w http.ResponseWriter, r *http.Request) {
+	s.serveLandingPage(w, r, "/languages/", languageSlug(r.PathValue("lang")), languageGroups, func(group *landingGroup) (string, string, string) {
+		label := forms.DisplayLanguage(group.Name)
+		return s.t(r, "Streamers in %s", group.Name),
+			s.t(r, "%s streamers", label),
+			s.t(r, "Browse %d %s streamers who stream in %s, with live status across YouTube, Twitch and Facebook.", len(group.Streamers), s.siteDisplayName(), group.Name)
+	})
+}
+
+func (s *server) handleCountryPage(w http.ResponseWriter, r *http.Request) {
+	s.serveLandingPage(w, r, "/countries/", strings.ToLower(strings.TrimSpace(r.PathValue("code"))), countryGroups, func(group *landingGroup) (string, string, string) {
+		return s.t(r, "Streamers from %s", group.Name),
+			s.t(r, "Streamers from %s", group.Name),
+			s.t(r, "Browse %d %s streamers based in %s, with live status across YouTube, Twitch and Facebook.", len(group.Streamers), s.siteDisplayName(), group.Name)
+	})
+}
+
+// serveLandingPage renders one language or country page. Non-canonical slugs
+// redirect to the canonical path and unknown or empty groups are 404s.
+func (s *server) serveLandingPage(w http.ResponseWriter, r *http.Request, prefix, slug string, groupBy func([]model.Streamer) map[string]*landingGroup, describe func(*landingGroup) (title, heading, description string)) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.rosterNotModified(w, r) {
+		return
+	}
+
+	roster, rosterErr := s.fetchRoster(r.Context())
+	if rosterErr != "" {
+		http.Error(w, rosterErr, http.StatusInternalServerError)
+		return
+	}
+	group := groupBy(roster)[slug]
+	if group == nil {
+		http.NotFound(w, r)
+		return
+	}
+	path := prefix + url.PathEscape(slug)
+	if r.URL.EscapedPath() != path {
+		target := s.localePath(s.locale(r), path)
+		if r.URL.RawQuery != "" {
+			target += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+		return
+	}
+
+	q := rosterQuery{Sort: rosterSorts[0].Value, Page: 1}
+	if page, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && page > 1 {
+		q.Page = page
+	}
+	listed, total := q.apply(group.Streamers)
+	pages := rosterPageCount(total)
+	if q.Page > pages {
+		http.NotFound(w, r)
+		return
+	}
+	pageURL := func(n int) string {
+		if n <= 1 {
+			return path
+		}
+		return path + "?page=" + strconv.Itoa(n)
+	}
+
+	title, heading, description := describe(group)
+	if q.Page > 1 {
+		title += " - " + s.t(r, "Page %d", q.Page)
+	}
+	page := s.buildBasePageData(r, title+" - "+s.siteDisplayName(), description, pageURL(q.Page))
+	page.StructuredData = s.landingStructuredData(r, heading, listed, total, (q.Page-1)*rosterPageSize)
+
+	data := struct {
+		basePageData
+		Heading   string
+		Streamers []model.Streamer
+		Total     int
+		Page      int
+		Pages     int
+		PrevURL   string
+		NextURL   string
+	}{
+		basePageData: page,
+		Heading:      heading,
+		Streamers:    listed,
+		Total:        total,
+		Page:         q.Page,
+		Pages:        pages,
+	}
+	if q.Page > 1 {
+		data.PrevURL = pageURL(q.Page - 1)
+	}
+	if q.Page < pages {
+		data.NextURL = pageURL(q.Page + 1)
+	}
+
+	tmpl := s.template(r, "listing")
+	if tmpl == nil {
+		http.Error(w, "template missing", http.StatusInternalServerError)
+		return
+	}
+	if err := tmpl.ExecuteTemplate(w, "listing", data); err != nil {
+		http.Error(w, "failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// landingStructuredData describes a landing page as a schema.org ItemList of
+// streamer profile URLs; offset is the position of the first listed streamer.
+func (s *server) landingStructuredData(r *http.Request, name string, listed []model.Streamer, total, offset int) template.JS {
+	items := make([]map[string]any, 0, len(listed))
+	for i, streamer := range listed {
+		items = append(items, map[string]any{
+			"@type":    "ListItem",
+			"position": offset + i + 1,
+			"name":     streamer.Name,
+			"url":      s.absoluteURL(r, "/streamers/"+url.PathEscape(streamer.Name)),
+		})
+	}
+	payload, err := json.Marshal(map[string]any{
+		"@context":        "https://schema.org",
+		"@type":           "ItemList",
+		"name":            name,
+		"numberOfItems":   total,
+		"itemListElement": items,
+	})
+	if err != nil {
+		return ""
+	}
+	return template.JS(payload)
+}
+
+// landingSitemapEntries lists the paths of every language and country page
+// (and their later pages) that currently has streamers.
+func landingSitemapEntries(roster []model.Streamer) []urlEntry {
+	var entries []urlEntry
+	for _, set := range []struct {
+		prefix string
+		groups map[string]*landingGroup
+	}{
+		{"/languages/", languageGroups(roster)},
+		{"/countries/", countryGroups(roster)},
+	} {
+		for _, group := range sortedLandingGroups(set.groups) {
+			path := set.prefix + url.PathEscape(group.Slug)
+			for page := 1; page <= rosterPageCount(len(group.Streamers)); page++ {
+				loc := path
+				if page > 1 {
+					loc += "?page=" + strconv.Itoa(page)
+				}
+				entries = append(entries, urlEntry{
+					Loc:        loc,
+					ChangeFreq: "daily",
+					Priority:   "0.7",
+				})
+			}
+		}
+	}
+	return entries
+}
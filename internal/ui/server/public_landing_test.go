@@ -0,0 +1,139 @@
+package server
+
+import (
+	"encoding/json"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+)
+
+func landingTestServer(t *testing.T) *server {
+	t.Helper()
+	records := apiTestRecords()
+	records[0].Streamer.Country = "gb"
+	records[1].Streamer.Country = "France"
+	records[2].Streamer.Country = "UK"
+	records = append(records, streamers.Record{Streamer: streamers.Streamer{ID: "d", Alias: "Delta", Languages: []string{"Scottish Gaelic"}, Country: "Atlantis"}})
+
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: records}
+	sites := filepath.Join("..", "..", "..", "ui", "sites")
+	templates, _, err := loadTemplates(filepath.Join(sites, "synth-wave", "templates"), filepath.Join(sites, "default-site", "templates"))
+	if err != nil {
+		t.Fatalf("load templates: %v", err)
+	}
+	srv.templates = templates
+	return srv
+}
+
+func serveLanding(srv *server, target string) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/languages/{lang}", srv.handleLanguagePage)
+	mux.HandleFunc("/countries/{code}", srv.handleCountryPage)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, target, nil))
+	return rr
+}
+
+var ldJSON = regexp.MustCompile(`(?s)<script type="application/ld\+json">(.*?)</script>`)
+
+func TestLanguageLandingPage(t *testing.T) {
+	srv := landingTestServer(t)
+
+	rr := serveLanding(srv, "/languages/english")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "<title>Streamers in English - Sharpen.Live</title>") || !strings.Contains(body, `<link rel="canonical" href="http://example.com/languages/english">`) {
+		t.Fatalf("expected language title and canonical URL, got %s", body)
+	}
+	if !strings.Contains(body, "Browse 2 Sharpen.Live streamers who stream in English") {
+		t.Fatalf("expected meta description with streamer count")
+	}
+	if !strings.Contains(body, "Charlie") || !strings.Contains(body, "Bravo") || strings.Contains(body, "alpha") {
+		t.Fatalf("expected only English-speaking streamers")
+	}
+
+	match := ldJSON.FindStringSubmatch(body)
+	if match == nil {
+		t.Fatalf("expected JSON-LD")
+	}
+	var list struct {
+		Type  string `json:"@type"`
+		Count int    `json:"numberOfItems"`
+		Items []struct {
+			Position int    `json:"position"`
+			URL      string `json:"url"`
+		} `json:"itemListElement"`
+	}
+	if err := json.Unmarshal([]byte(html.UnescapeString(match[1])), &list); err != nil {
+		t.Fatalf("decode JSON-LD: %v (%s)", err, match[1])
+	}
+	if list.Type != "ItemList" || list.Count != 2 || len(list.Items) != 2 || list.Items[0].Position != 1 || list.Items[0].URL != "http://example.com/streamers/Charlie" {
+		t.Fatalf("unexpected ItemList %+v", list)
+	}
+
+	if rr := serveLanding(srv, "/languages/scottish-gaelic"); rr.Code != http.StatusOK {
+		t.Fatalf("expected multi-word language page, got %d", rr.Code)
+	}
+	if rr := serveLanding(srv, "/languages/English"); rr.Code != http.StatusMovedPermanently || rr.Header().Get("Location") != "/languages/english" {
+		t.Fatalf("expected redirect to canonical slug, got %d %q", rr.Code, rr.Header().Get("Location"))
+	}
+	if rr := serveLanding(srv, "/languages/klingon"); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a language without streamers, got %d", rr.Code)
+	}
+}
+
+func TestLandingRedirectKeepsLocalePrefix(t *testing.T) {
+	srv := landingTestServer(t)
+	srv.locales = []string{"en", "fr"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/languages/{lang}", srv.handleLanguagePage)
+
+	rr := httptest.NewRecorder()
+	srv.localeHandler(mux).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/fr/languages/English?page=2", nil))
+	if rr.Code != http.StatusMovedPermanently || rr.Header().Get("Location") != "/fr/languages/english?page=2" {
+		t.Fatalf("expected redirect to the French canonical slug, got %d %q", rr.Code, rr.Header().Get("Location"))
+	}
+}
+
+func TestCountryLandingPage(t *testing.T) {
+	srv := landingTestServer(t)
+
+	rr := serveLanding(srv, "/countries/gb")
+	body := rr.Body.String()
+	if rr.Code != http.StatusOK || !strings.Contains(body, "Streamers from United Kingdom") {
+		t.Fatalf("expected United Kingdom page, got %d", rr.Code)
+	}
+	if !strings.Contains(body, "Charlie") || !strings.Contains(body, "Bravo") || strings.Contains(body, "alpha") {
+		t.Fatalf("expected codes, names and aliases to resolve to GB")
+	}
+	if rr := serveLanding(srv, "/countries/FR"); rr.Code != http.StatusMovedPermanently || rr.Header().Get("Location") != "/countries/fr" {
+		t.Fatalf("expected redirect to lower-case code, got %d", rr.Code)
+	}
+	if rr := serveLanding(srv, "/countries/us"); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a country without streamers, got %d", rr.Code)
+	}
+}
+
+func TestSitemapListsLandingPages(t *testing.T) {
+	srv := landingTestServer(t)
+	rr := httptest.NewRecorder()
+	srv.handleSitemap(rr, httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil))
+	body := rr.Body.String()
+	for _, want := range []string{"/languages/english<", "/languages/french<", "/languages/scottish-gaelic<", "/countries/gb<", "/countries/fr<"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %s in sitemap, got %s", want, body)
+		}
+	}
+	if strings.Count(body, "/languages/english<") != 1 || strings.Contains(body, "/countries/us") {
+		t.Fatalf("expected one entry per non-empty page")
+	}
+}
@@ -0,0 +1,237 @@
+package server
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Its-donkey/Sharpen-live/internal/ui/model"
+)
+
+// rosterPageSize is the number of streamers listed per home page.
+const rosterPageSize = 25
+
+// rosterSorts lists the home page sort orders; the first is the default.
+var rosterSorts = []rosterOption{
+	{Value: "live", Label: "Live first"},
+	{Value: "name", Label: "Alphabetical"},
+	{Value: "newest", Label: "Newest"},
+}
+
+// rosterPlatforms lists the platforms the roster can be filtered by.
+var rosterPlatforms = []rosterOption{
+	{Value: "youtube", Label: "YouTube"},
+	{Value: "twitch", Label: "Twitch"},
+	{Value: "facebook", Label: "Facebook"},
+}
+
+type rosterOption struct {
+	Value string
+	Label string
+}
+
+// rosterQuery is the home page's search, filter, sort and page state. It is
+// carried entirely in query parameters so the roster works without
+// JavaScript and every result page has a stable URL.
+type rosterQuery struct {
+	Search   string
+	Language string
+	Platform string
+	Live     bool
+	Sort     string
+	Page     int
+}
+
+func parseRosterQuery(values url.Values) rosterQuery {
+	q := rosterQuery{
+		Search:   strings.Join(strings.Fields(values.Get("q")), " "),
+		Language: strings.TrimSpace(values.Get("language")),
+		Platform: strings.ToLower(strings.TrimSpace(values.Get("platform"))),
+		Sort:     strings.ToLower(strings.TrimSpace(values.Get("sort"))),
+		Page:     1,
+	}
+	if !validRosterOption(rosterPlatforms, q.Platform) {
+		q.Platform = ""
+	}
+	if !validRosterOption(rosterSorts, q.Sort) {
+		q.Sort = rosterSorts[0].Value
+	}
+	switch values.Get("live") {
+	case "1", "true", "on":
+		q.Live = true
+	}
+	if page, err := strconv.Atoi(values.Get("page")); err == nil && page > 1 {
+		q.Page = page
+	}
+	return q
+}
+
+func validRosterOption(options []rosterOption, value string) bool {
+	for _, opt := range options {
+		if opt.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+// filtered reports whether the query narrows the roster. Filtered result
+// pages are not indexed; only the plain roster pages appear in sitemap.xml.
+func (q rosterQuery) filtered() bool {
+	return q.Search != "" || q.Language != "" || q.Platform != "" || q.Live || q.Sort != rosterSorts[0].Value
+}
+
+// url returns the canonical URL path for the query with page replaced,
+// omitting defaults and keeping parameters in a fixed order.
+func (q rosterQuery) url(page int) string {
+	values := url.Values{}
+	if q.Search != "" {
+		values.Set("q", q.Search)
+	}
+	if q.Language != "" {
+		values.Set("language", q.Language)
+	}
+	if q.Platform != "" {
+		values.Set("platform", q.Platform)
+	}
+	if q.Live {
+		values.Set("live", "1")
+	}
+	if q.Sort != rosterSorts[0].Value {
+		values.Set("sort", q.Sort)
+	}
+	if page > 1 {
+		values.Set("page", strconv.Itoa(page))
+	}
+	if len(values) == 0 {
+		return "/"
+	}
+	// url.Values.Encode sorts keys, which keeps the URL canonical.
+	return "/?" + values.Encode()
+}
+
+func (q rosterQuery) matches(streamer model.Streamer) bool {
+	if q.Live && streamer.Status != "online" {
+		return false
+	}
+	if q.Language != "" && !containsFold(streamer.Languages, q.Language) {
+		return false
+	}
+	if q.Platform != "" {
+		found := false
+		for _, p := range streamer.Platforms {
+			if strings.EqualFold(p.Name, q.Platform) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if q.Search != "" {
+		haystack := strings.ToLower(streamer.Name + "\n" + streamer.Description)
+		for _, term := range strings.Fields(strings.ToLower(q.Search)) {
+			if !strings.Contains(haystack, term) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// apply filters and sorts roster (already featured-first from fetchRoster)
+// and returns the requested page along with the total number of matches.
+func (q rosterQuery) apply(roster []model.Streamer) ([]model.Streamer, int) {
+	var matched []model.Streamer
+	for _, streamer := range roster {
+		if q.matches(streamer) {
+			matched = append(matched, streamer)
+		}
+	}
+	switch q.Sort {
+	case "name":
+		sort.SliceStable(matched, func(i, j int) bool {
+			return strings.ToLower(matched[i].Name) < strings.ToLower(matched[j].Name)
+		})
+	case "newest":
+		sort.SliceStable(matched, func(i, j int) bool {
+			return matched[i].CreatedAt.After(matched[j].CreatedAt)
+		})
+	default:
+		sort.SliceStable(matched, func(i, j int) bool {
+			return matched[i].Status == "online" && matched[j].Status != "online"
+		})
+	}
+	total := len(matched)
+	start := (q.Page - 1) * rosterPageSize
+	if start >= total {
+		return nil, total
+	}
+	end := min(start+rosterPageSize, total)
+	return matched[start:end], total
+}
+
+// rosterPageCount returns how many pages total matches span (at least one).
+func rosterPageCount(total int) int {
+	if total <= rosterPageSize {
+		return 1
+	}
+	return (total + rosterPageSize - 1) / rosterPageSize
+}
+
+// rosterView is the template data for the home page's roster controls.
+type rosterView struct {
+	Query     rosterQuery
+	Languages []string
+	Platforms []rosterOption
+	Sorts     []rosterOption
+	Total     int
+	Page      int
+	Pages     int
+	PrevURL   string
+	NextURL   string
+	ClearURL  string
+	Filtered  bool
+}
+
+func newRosterView(q rosterQuery, roster []model.Streamer, total int) rosterView {
+	view := rosterView{
+		Query:     q,
+		Languages: rosterLanguages(roster),
+		Platforms: rosterPlatforms,
+		Sorts:     rosterSorts,
+		Total:     total,
+		Page:      q.Page,
+		Pages:     rosterPageCount(total),
+		ClearURL:  "/",
+		Filtered:  q.filtered(),
+	}
+	if q.Page > 1 {
+		view.PrevURL = q.url(q.Page - 1)
+	}
+	if q.Page < view.Pages {
+		view.NextURL = q.url(q.Page + 1)
+	}
+	return view
+}
+
+// rosterLanguages returns the languages spoken across the roster, sorted.
+func rosterLanguages(roster []model.Streamer) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, streamer := range roster {
+		for _, lang := range streamer.Languages {
+			lang = strings.TrimSpace(lang)
+			key := strings.ToLower(lang)
+			if lang == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			out = append(out, lang)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
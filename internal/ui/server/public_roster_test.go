@@ -0,0 +1,137 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+	"github.com/Its-donkey/Sharpen-live/internal/ui/model"
+)
+
+func rosterNames(roster []model.Streamer) string {
+	names := make([]string, 0, len(roster))
+	for _, s := range roster {
+		names = append(names, s.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestRosterQueryCanonicalURL(t *testing.T) {
+	tests := []struct {
+		raw      string
+		want     string
+		filtered bool
+	}{
+		{"", "/", false},
+		{"sort=live&page=1", "/", false},
+		{"page=0&sort=bogus&platform=myspace", "/", false},
+		{"page=2", "/?page=2", false},
+		{"q=++knife+++vlog&page=3", "/?page=3&q=knife+vlog", true},
+		{"sort=name&live=on&platform=YouTube&language=English", "/?language=English&live=1&platform=youtube&sort=name", true},
+	}
+	for _, tt := range tests {
+		values, _ := url.ParseQuery(tt.raw)
+		q := parseRosterQuery(values)
+		if got := q.url(q.Page); got != tt.want {
+			t.Fatalf("%q: expected %q, got %q", tt.raw, tt.want, got)
+		}
+		if q.filtered() != tt.filtered {
+			t.Fatalf("%q: expected filtered=%v", tt.raw, tt.filtered)
+		}
+	}
+}
+
+func TestRosterQueryApply(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	roster := []model.Streamer{
+		{Name: "Delta", Description: "Whetstone vlog", Status: "offline", Languages: []string{"English"}, Platforms: []model.Platform{{Name: "Twitch"}}, CreatedAt: base},
+		{Name: "alpha", Description: "Knife sharpening", Status: "online", Languages: []string{"French"}, Platforms: []model.Platform{{Name: "YouTube"}}, CreatedAt: base.Add(2 * time.Hour)},
+		{Name: "Charlie", Description: "Japanese knife forge", Status: "online", Languages: []string{"english"}, Platforms: []model.Platform{{Name: "YouTube"}}, CreatedAt: base.Add(time.Hour)},
+	}
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"", "alpha,Charlie,Delta"},
+		{"sort=name", "alpha,Charlie,Delta"},
+		{"sort=newest", "alpha,Charlie,Delta"},
+		{"q=KNIFE", "alpha,Charlie"},
+		{"q=japanese+knife", "Charlie"},
+		{"q=delta", "Delta"},
+		{"language=English", "Charlie,Delta"},
+		{"platform=twitch", "Delta"},
+		{"live=1&language=english", "Charlie"},
+	}
+	for _, tt := range tests {
+		values, _ := url.ParseQuery(tt.raw)
+		got, total := parseRosterQuery(values).apply(roster)
+		if rosterNames(got) != tt.want || total != len(got) {
+			t.Fatalf("%q: expected %q, got %q (total %d)", tt.raw, tt.want, rosterNames(got), total)
+		}
+	}
+	values, _ := url.ParseQuery("sort=newest")
+	if got, _ := parseRosterQuery(values).apply(roster[:1:1]); rosterNames(got) != "Delta" {
+		t.Fatalf("unexpected single-entry result %q", rosterNames(got))
+	}
+}
+
+func TestHomePaginatesRoster(t *testing.T) {
+	var records []streamers.Record
+	for i := 0; i < rosterPageSize+5; i++ {
+		records = append(records, streamers.Record{Streamer: streamers.Streamer{ID: fmt.Sprintf("s%02d", i), Alias: fmt.Sprintf("Streamer %02d", i)}})
+	}
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: records}
+	sites := filepath.Join("..", "..", "..", "ui", "sites")
+	templates, _, err := loadTemplates(filepath.Join(sites, "sharpen-live", "templates"), filepath.Join(sites, "default-site", "templates"))
+	if err != nil {
+		t.Fatalf("load templates: %v", err)
+	}
+	srv.templates = templates
+
+	get := func(target string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		srv.handleHome(rr, httptest.NewRequest(http.MethodGet, target, nil))
+		return rr
+	}
+
+	body := get("/").Body.String()
+	if !strings.Contains(body, "Streamer 24") || strings.Contains(body, "Streamer 25") {
+		t.Fatalf("expected first page only")
+	}
+	if !strings.Contains(body, `rel="next" href="/?page=2"`) || !strings.Contains(body, `<link rel="canonical" href="http://example.com/">`) {
+		t.Fatalf("expected next link and canonical home URL")
+	}
+
+	body = get("/?page=2&sort=live").Body.String()
+	if !strings.Contains(body, "Streamer 29") || strings.Contains(body, "Streamer 24") || strings.Contains(body, `rel="next"`) {
+		t.Fatalf("expected second page only")
+	}
+	if !strings.Contains(body, `href="http://example.com/?page=2"`) || strings.Contains(body, "noindex") {
+		t.Fatalf("expected indexable canonical second page")
+	}
+
+	body = get("/?q=streamer+03").Body.String()
+	if !strings.Contains(body, "Streamer 03") || strings.Contains(body, "Streamer 04") || !strings.Contains(body, `content="noindex, follow"`) {
+		t.Fatalf("expected noindex search result")
+	}
+	if !strings.Contains(body, `value="streamer 03"`) || !strings.Contains(body, "1 streamer matches") {
+		t.Fatalf("expected form to keep the query")
+	}
+
+	if rr := get("/?page=3"); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 past the last page, got %d", rr.Code)
+	}
+
+	rr := httptest.NewRecorder()
+	srv.handleSitemap(rr, httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil))
+	if !strings.Contains(rr.Body.String(), "<loc>http://example.com/?page=2</loc>") || strings.Contains(rr.Body.String(), "page=3") {
+		t.Fatalf("expected roster pages in sitemap, got %s", rr.Body.String())
+	}
+}
@@ -10,16 +10,25 @@ import (
 )
 
 type urlSet struct {
-	XMLName xml.Name   `xml:"urlset"`
-	Xmlns   string     `xml:"xmlns,attr"`
-	URLs    []urlEntry `xml:"url"`
+	XMLName    xml.Name   `xml:"urlset"`
+	Xmlns      string     `xml:"xmlns,attr"`
+	XmlnsXhtml string     `xml:"xmlns:xhtml,attr,omitempty"`
+	URLs       []urlEntry `xml:"url"`
 }
 
 type urlEntry struct {
-	Loc        string `xml:"loc"`
-	LastMod    string `xml:"lastmod,omitempty"`
-	ChangeFreq string `xml:"changefreq,omitempty"`
-	Priority   string `xml:"priority,omitempty"`
+	Loc        string             `xml:"loc"`
+	LastMod    string             `xml:"lastmod,omitempty"`
+	ChangeFreq string             `xml:"changefreq,omitempty"`
+	Priority   string             `xml:"priority,omitempty"`
+	Alternates []sitemapXHTMLLink `xml:"xhtml:link"`
+}
+
+// sitemapXHTMLLink is an hreflang alternate for one sitemap URL.
+type sitemapXHTMLLink struct {
+	Rel      string `xml:"rel,attr"`
+	Hreflang string `xml:"hreflang,attr"`
+	Href     string `xml:"href,attr"`
 }
 
 func (s *server) handleRobots(w http.ResponseWriter, r *http.Request) {
@@ -43,9 +52,9 @@ func (s *server) handleSitemap(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	homeURL := s.absoluteURL(r, "/")
+	// Entries hold paths until they are expanded per locale below.
 	entries := []urlEntry{{
-		Loc:        homeURL,
+		Loc:        "/",
 		ChangeFreq: "daily",
 		Priority:   "1.0",
 	}}
@@ -59,6 +68,16 @@ func (s *server) handleSitemap(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Later pages of the unfiltered roster are canonical too; search and
+	// filter results are noindex and stay out of the sitemap.
+	for page := 2; page <= rosterPageCount(len(records)); page++ {
+		entries = append(entries, urlEntry{
+			Loc:        rosterQuery{Sort: rosterSorts[0].Value}.url(page),
+			ChangeFreq: "daily",
+			Priority:   "0.6",
+		})
+	}
+
 	for _, rec := range records {
 		alias := strings.TrimSpace(rec.Streamer.Alias)
 		if alias == "" {
@@ -67,16 +86,23 @@ func (s *server) handleSitemap(w http.ResponseWriter, r *http.Request) {
 		path := fmt.Sprintf("/streamers/%s", alias)
 		lastMod := rec.UpdatedAt.Format("2006-01-02")
 		entries = append(entries, urlEntry{
-			Loc:        s.absoluteURL(r, path),
+			Loc:        path,
 			LastMod:    lastMod,
 			ChangeFreq: "weekly",
 			Priority:   "0.8",
 		})
 	}
 
+	entries = append(entries, landingSitemapEntries(mapStreamerRecords(records))...)
+
 	smap := urlSet{
 		Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9",
-		URLs:  entries,
+	}
+	if len(s.locales) > 1 {
+		smap.XmlnsXhtml = "http://www.w3.org/1999/xhtml"
+	}
+	for _, entry := range entries {
+		smap.URLs = append(smap.URLs, s.localizedSitemapEntries(r, entry)...)
 	}
 
 	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
@@ -85,3 +111,26 @@ func (s *server) handleSitemap(w http.ResponseWriter, r *http.Request) {
 	enc.Indent("", "  ")
 	_ = enc.Encode(smap)
 }
+
+// localizedSitemapEntries turns a path entry into absolute URLs: one per
+// locale, each listing every language version as an hreflang alternate.
+func (s *server) localizedSitemapEntries(r *http.Request, entry urlEntry) []urlEntry {
+	path := entry.Loc
+	alternates := s.localeAlternates(r, path)
+	if len(alternates) == 0 {
+		entry.Loc = s.absoluteURL(r, path)
+		return []urlEntry{entry}
+	}
+	links := make([]sitemapXHTMLLink, 0, len(alternates))
+	for _, alt := range alternates {
+		links = append(links, sitemapXHTMLLink{Rel: "alternate", Hreflang: alt.Hreflang, Href: alt.URL})
+	}
+	out := make([]urlEntry, 0, len(s.locales))
+	for _, locale := range s.locales {
+		localized := entry
+		localized.Loc = s.absoluteURL(r, s.localePath(locale, path))
+		localized.Alternates = links
+		out = append(out, localized)
+	}
+	return out
+}
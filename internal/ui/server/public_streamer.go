@@ -59,37 +59,44 @@ func (s *server) handleStreamer(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/", http.StatusFound)
 		return
 	}
+	if badgeAlias, ok := strings.CutSuffix(alias, "/badge.svg"); ok {
+		s.serveStreamerBadge(w, r, badgeAlias)
+		return
+	}
+	if s.rosterNotModified(w, r) {
+		return
+	}
 
-	streamer := model.Streamer{}
-	if s.streamersStore != nil {
-		records, err := s.streamersStore.List()
-		if err != nil {
-			http.Error(w, "failed to load streamer", http.StatusInternalServerError)
-			return
-		}
-		for _, rec := range mapStreamerRecords(records) {
-			if strings.EqualFold(rec.Name, alias) || strings.EqualFold(rec.ID, alias) {
-				streamer = rec
-				break
-			}
-		}
+	streamer, err := s.findStreamer(alias)
+	if err != nil {
+		http.Error(w, "failed to load streamer", http.StatusInternalServerError)
+		return
 	}
 	if streamer.ID == "" && streamer.Name == "" {
 		http.NotFound(w, r)
 		return
 	}
 
-	page := s.buildBasePageData(r, s.streamerPageTitle(streamer.Name), s.siteDescription, r.URL.Path)
+	page := s.buildBasePageData(r, s.streamerPageTitle(streamer.Name), s.t(r, s.siteDescription), r.URL.Path)
 	page.StructuredData = s.streamerStructuredData(s.absoluteURL(r, r.URL.Path), streamer)
+	page.OEmbedURL = s.oembedDiscoveryURL(r, page.CanonicalURL)
 	data := struct {
 		basePageData
 		Streamer model.Streamer
+		// PushScriptPath loads the follow button for live notifications.
+		PushScriptPath string
+		// EmailAlerts shows the email subscription form.
+		EmailAlerts bool
 	}{
 		basePageData: page,
 		Streamer:     streamer,
+		EmailAlerts:  s.emailAlertsEnabled(),
+	}
+	if s.push != nil {
+		data.PushScriptPath = s.assetURL("/push.js")
 	}
 
-	tmpl := s.templates["streamer"]
+	tmpl := s.template(r, "streamer")
 	if tmpl == nil {
 		http.Error(w, "template missing", http.StatusInternalServerError)
 		return
@@ -98,3 +105,21 @@ func (s *server) handleStreamer(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "failed to render page", http.StatusInternalServerError)
 	}
 }
+
+// findStreamer returns the roster entry whose alias or ID matches alias
+// (case-insensitively), or a zero Streamer when none does.
+func (s *server) findStreamer(alias string) (model.Streamer, error) {
+	if s.streamersStore == nil {
+		return model.Streamer{}, nil
+	}
+	records, err := s.streamersStore.List()
+	if err != nil {
+		return model.Streamer{}, err
+	}
+	for _, rec := range mapStreamerRecords(records) {
+		if strings.EqualFold(rec.Name, alias) || strings.EqualFold(rec.ID, alias) {
+			return rec, nil
+		}
+	}
+	return model.Streamer{}, nil
+}
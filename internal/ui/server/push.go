@@ -0,0 +1,148 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/webhooks"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/webpush"
+)
+
+const (
+	// pushBodyLimit bounds subscribe requests; a subscription with the
+	// maximum follow list is well under it.
+	pushBodyLimit = 32 << 10
+	// pushTimeout bounds one live notification across all followers.
+	pushTimeout = time.Minute
+	// pushSubscribeLimit is how many subscribe requests one client may make
+	// per minute; a browser only sends one when its follow list changes.
+	pushSubscribeLimit = 30
+)
+
+// pushSubject is the VAPID contact sent to push services: the admin email
+// when one is configured, otherwise the site's own URL.
+func pushSubject(adminEmail, host string) string {
+	if email := strings.TrimSpace(adminEmail); email != "" {
+		return "mailto:" + email
+	}
+	if host = strings.TrimSpace(host); host != "" {
+		return "https://" + host
+	}
+	return ""
+}
+
+// handlePushKey returns the site's VAPID public key for
+// pushManager.subscribe.
+func (s *server) handlePushKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	store := s.push.Store()
+	if store == nil {
+		http.Error(w, "push notifications are unavailable", http.StatusNotFound)
+		return
+	}
+	key, err := store.Key()
+	if err != nil {
+		s.logger.Warn("push", "Failed to load VAPID key", map[string]any{"error": err.Error()})
+		http.Error(w, "push notifications are unavailable", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	_ = json.NewEncoder(w).Encode(map[string]string{"publicKey": key.PublicKey})
+}
+
+// handlePushSubscribe saves a browser's push subscription with the streamer
+// IDs it follows. The body is PushSubscription.toJSON() plus a "streamers"
+// list; an empty list unsubscribes. IDs not on this site's roster are
+// dropped, so a follow list kept in the browser survives deletions.
+func (s *server) handlePushSubscribe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	store := s.push.Store()
+	if store == nil {
+		http.Error(w, "push notifications are unavailable", http.StatusNotFound)
+		return
+	}
+	if !s.pushLimiter.allow(r) {
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
+		return
+	}
+	var sub webpush.Subscription
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, pushBodyLimit)).Decode(&sub); err != nil {
+		http.Error(w, "invalid subscription", http.StatusBadRequest)
+		return
+	}
+	followed := make([]string, 0, len(sub.Streamers))
+	for _, id := range sub.Streamers {
+		if record, ok := s.streamerRecord(strings.TrimSpace(id)); ok {
+			followed = append(followed, record.Streamer.ID)
+		}
+	}
+	sub.Streamers = followed
+	saved, err := store.Subscribe(sub)
+	if err != nil {
+		if errors.Is(err, webpush.ErrInvalidSubscription) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, webpush.ErrFull) {
+			s.logger.Warn("push", "Push subscription limit reached", map[string]any{"limit": webpush.MaxSubscriptions})
+			http.Error(w, "push notifications are full", http.StatusServiceUnavailable)
+			return
+		}
+		s.logger.Warn("push", "Failed to save push subscription", map[string]any{"error": err.Error()})
+		http.Error(w, "failed to save subscription", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string][]string{"streamers": saved.Streamers})
+}
+
+// pushLive notifies the browsers following a streamer that went live. It
+// runs in the background so slow push services cannot hold up status
+// notifications.
+func (s *server) pushLive(event string, record streamers.Record, platform string) {
+	if s.push == nil || event != webhooks.EventStreamerLive {
+		return
+	}
+	a := s.announcement(record, platform)
+	msg := webpush.Message{
+		Title: s.t(nil, "%s is live on %s", a.Alias, a.PlatformName),
+		Body:  a.Description,
+		URL:   a.PageURL,
+		Icon:  s.absoluteURL(nil, s.socialImagePath),
+		Tag:   "live-" + record.Streamer.ID,
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), pushTimeout)
+		defer cancel()
+		result, err := s.push.Notify(ctx, record.Streamer.ID, msg)
+		if err != nil {
+			s.logger.Warn("push", "Failed to send some push notifications", map[string]any{
+				"streamer": record.Streamer.ID,
+				"sent":     result.Sent,
+				"failed":   result.Failed,
+				"error":    err.Error(),
+			})
+		}
+		if result.Pruned > 0 {
+			s.logger.Info("push", "Pruned expired push subscriptions", map[string]any{
+				"streamer": record.Streamer.ID,
+				"pruned":   result.Pruned,
+			})
+		}
+	}()
+}
@@ -0,0 +1,194 @@
+package server
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/webpush"
+)
+
+// pushTestSubscription returns a browser subscription body for endpoint.
+func pushTestSubscription(t *testing.T, endpoint string, streamers ...string) webpush.Subscription {
+	t.Helper()
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("browser key: %v", err)
+	}
+	return webpush.Subscription{
+		Endpoint: endpoint,
+		Keys: webpush.Keys{
+			P256DH: base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+			Auth:   base64.RawURLEncoding.EncodeToString(make([]byte, 16)),
+		},
+		Streamers: streamers,
+	}
+}
+
+func TestPushKeyAndSubscribe(t *testing.T) {
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: apiTestRecords()}
+	srv.push = webpush.NewSender(webpush.NewStore(filepath.Join(t.TempDir(), "push.json")), webpush.Options{})
+
+	rr := httptest.NewRecorder()
+	srv.handlePushKey(rr, httptest.NewRequest(http.MethodGet, "/push/key", nil))
+	var key struct {
+		PublicKey string `json:"publicKey"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &key); err != nil || len(key.PublicKey) != 87 {
+		t.Fatalf("unexpected key response %d %s (%v)", rr.Code, rr.Body.String(), err)
+	}
+
+	subscribe := func(sub webpush.Subscription) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(sub)
+		rr := httptest.NewRecorder()
+		srv.handlePushSubscribe(rr, httptest.NewRequest(http.MethodPost, "/push/subscribe", strings.NewReader(string(body))))
+		return rr
+	}
+
+	sub := pushTestSubscription(t, "https://push.example.com/abc", "c", "missing", "a")
+	rr = subscribe(sub)
+	if rr.Code != http.StatusOK || strings.TrimSpace(rr.Body.String()) != `{"streamers":["c","a"]}` {
+		t.Fatalf("expected unknown streamers to be dropped, got %d %s", rr.Code, rr.Body.String())
+	}
+	if followers, _ := srv.push.Store().Followers("c"); len(followers) != 1 {
+		t.Fatalf("expected one follower, got %+v", followers)
+	}
+
+	bad := sub
+	bad.Endpoint = "http://push.example.com/abc"
+	if rr := subscribe(bad); rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected an insecure endpoint to be rejected, got %d", rr.Code)
+	}
+
+	internal := sub
+	internal.Endpoint = "https://169.254.169.254/latest/meta-data"
+	if rr := subscribe(internal); rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected a link-local endpoint to be rejected, got %d", rr.Code)
+	}
+
+	sub.Streamers = nil
+	if rr := subscribe(sub); rr.Code != http.StatusOK {
+		t.Fatalf("unsubscribe: %d %s", rr.Code, rr.Body.String())
+	}
+	if subs, _ := srv.push.Store().Subscriptions(); len(subs) != 0 {
+		t.Fatalf("expected the subscription to be removed, got %+v", subs)
+	}
+}
+
+func TestPushSubscribeRateLimit(t *testing.T) {
+	srv := newTestServer()
+	srv.streamersStore = &stubStreamersStore{records: apiTestRecords()}
+	srv.push = webpush.NewSender(webpush.NewStore(filepath.Join(t.TempDir(), "push.json")), webpush.Options{})
+	srv.pushLimiter = newClientLimiter(2, time.Minute)
+	now := time.Now()
+	srv.pushLimiter.now = func() time.Time { return now }
+
+	subscribe := func(remote string) int {
+		body, _ := json.Marshal(pushTestSubscription(t, "https://push.example.com/"+remote, "a"))
+		req := httptest.NewRequest(http.MethodPost, "/push/subscribe", strings.NewReader(string(body)))
+		req.RemoteAddr = remote + ":5000"
+		rr := httptest.NewRecorder()
+		srv.handlePushSubscribe(rr, req)
+		return rr.Code
+	}
+	for i := range 2 {
+		if code := subscribe("203.0.113.1"); code != http.StatusOK {
+			t.Fatalf("request %d: expected OK, got %d", i+1, code)
+		}
+	}
+	if code := subscribe("203.0.113.1"); code != http.StatusTooManyRequests {
+		t.Fatalf("expected the third request to be limited, got %d", code)
+	}
+	if code := subscribe("203.0.113.2"); code != http.StatusOK {
+		t.Fatalf("expected another client to be allowed, got %d", code)
+	}
+	now = now.Add(time.Minute)
+	if code := subscribe("203.0.113.1"); code != http.StatusOK {
+		t.Fatalf("expected the limit to reset after a minute, got %d", code)
+	}
+}
+
+func TestTwitchLivePushesFollowers(t *testing.T) {
+	pushed := make(chan *http.Request, 4)
+	service := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pushed <- r
+		if r.URL.Path == "/expired" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer service.Close()
+
+	path := filepath.Join(t.TempDir(), "synth-wave", "streamers.json")
+	store := streamers.NewStore(path)
+	if _, err := store.Append(streamers.Record{
+		Streamer:  streamers.Streamer{ID: "a", Alias: "alpha", Languages: []string{"English"}},
+		Platforms: streamers.Platforms{Twitch: &streamers.TwitchPlatform{Username: "alpha", BroadcasterID: "42"}},
+		Sites:     []string{"synth-wave"},
+	}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	srv := newTestServer()
+	srv.siteKey = "synth-wave"
+	srv.streamersStore = store
+	srv.storeCache = map[string]*streamers.Store{}
+	// The push service listens on loopback, which Subscribe refuses, so the
+	// followers are written to the push file directly.
+	pushPath := filepath.Join(t.TempDir(), "push.json")
+	var file webpush.File
+	for _, endpoint := range []string{"/expired", "/browser"} {
+		file.Subscriptions = append(file.Subscriptions, pushTestSubscription(t, service.URL+endpoint, "a"))
+	}
+	data, _ := json.Marshal(file)
+	if err := os.WriteFile(pushPath, data, 0o600); err != nil {
+		t.Fatalf("write push file: %v", err)
+	}
+	pushStore := webpush.NewStore(pushPath)
+	srv.push = webpush.NewSender(pushStore, webpush.Options{Client: service.Client()})
+	defer registerSite(srv)()
+
+	notify := func(event any) EventSubNotification {
+		raw, _ := json.Marshal(event)
+		return EventSubNotification{Event: raw}
+	}
+	srv.handleStreamOnline(notify(StreamOnlineEvent{ID: "stream-1", BroadcasterUserID: "42", StartedAt: time.Now()}))
+
+	seen := map[string]bool{}
+	for range 2 {
+		select {
+		case r := <-pushed:
+			if r.Header.Get("Content-Encoding") != "aes128gcm" {
+				t.Fatalf("expected an encrypted push, got %v", r.Header)
+			}
+			seen[r.URL.Path] = true
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for pushes")
+		}
+	}
+	if !seen["/expired"] || !seen["/browser"] {
+		t.Fatalf("expected both followers to be pushed, got %v", seen)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		subs, _ := pushStore.Subscriptions()
+		if len(subs) == 1 && strings.HasSuffix(subs[0].Endpoint, "/browser") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the expired subscription to be pruned, got %+v", subs)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
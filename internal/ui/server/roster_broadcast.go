@@ -0,0 +1,251 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	defaultWatchMaxClients = 1000
+	defaultWatchHeartbeat  = 15 * time.Second
+	// defaultWatchFallbackPoll catches edits to streamers.json made outside
+	// this process, which the store write hook cannot see.
+	defaultWatchFallbackPoll = 30 * time.Second
+	// watchSubscriberBuffer is how many pending frames a subscriber may hold
+	// before it is treated as a slow consumer and disconnected.
+	watchSubscriberBuffer = 64
+	watchWriteTimeout     = 10 * time.Second
+)
+
+var errWatchFull = errors.New("too many watch connections")
+
+// rosterBroadcaster is the single change detector for one store. It wakes on
+// store writes (and a slow fallback poll), diffs the roster once, and fans
+// the resulting frames out to every /streamers/watch subscriber. Subscribers
+// that fall watchSubscriberBuffer frames behind are evicted; they reconnect
+// with Last-Event-ID and replay what they missed.
+type rosterBroadcaster struct {
+	path       string
+	feed       *rosterFeed
+	changed    func() <-chan struct{}
+	fallback   time.Duration
+	heartbeat  time.Duration
+	maxClients int
+
+	mu      sync.Mutex
+	subs    map[*watchSubscriber]struct{}
+	stop    chan struct{}
+	cursor  uint64
+	modTime time.Time
+	evicted uint64
+}
+
+// watchSubscriber is one connected watch client.
+type watchSubscriber struct {
+	updates chan watchUpdate
+	evicted chan struct{}
+}
+
+// watchUpdate is one batch of roster changes delivered to every subscriber.
+// Transports apply their own filters when encoding it.
+type watchUpdate struct {
+	// ID is the newest event ID the update brings the subscriber up to.
+	ID     uint64
+	Events []rosterEvent
+	// Reset is set when events were lost and clients should refetch.
+	Reset bool
+	// ModTime is the roster file's new modification time, or zero when the
+	// file did not change.
+	ModTime time.Time
+}
+
+// watchStats is the connected-clients gauge shown on the admin dashboard.
+type watchStats struct {
+	Clients    int
+	MaxClients int
+	Evicted    uint64
+}
+
+func newRosterBroadcaster(opts streamersWatchOptions) *rosterBroadcaster {
+	b := &rosterBroadcaster{
+		path:       opts.FilePath,
+		feed:       opts.Feed,
+		changed:    opts.Changed,
+		fallback:   opts.PollInterval,
+		heartbeat:  opts.Heartbeat,
+		maxClients: opts.MaxClients,
+		subs:       map[*watchSubscriber]struct{}{},
+	}
+	if b.fallback <= 0 {
+		b.fallback = defaultWatchFallbackPoll
+	}
+	if b.heartbeat <= 0 {
+		b.heartbeat = defaultWatchHeartbeat
+	}
+	if b.maxClients <= 0 {
+		b.maxClients = defaultWatchMaxClients
+	}
+	b.feed.poll()
+	b.cursor = b.feed.latest()
+	b.modTime, _ = fileModTime(b.path)
+	return b
+}
+
+// subscribe registers a client and returns its first update: the events
+// after lastID when resuming, and the current file modification time.
+func (b *rosterBroadcaster) subscribe(lastID uint64, resume bool) (*watchSubscriber, watchUpdate, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.subs) >= b.maxClients {
+		return nil, watchUpdate{}, errWatchFull
+	}
+	changed := b.nextChange()
+	b.publishLocked()
+
+	initial := watchUpdate{ID: b.cursor, ModTime: b.modTime}
+	if resume {
+		initial.Events, initial.Reset = b.replayLocked(lastID)
+	}
+
+	sub := &watchSubscriber{
+		updates: make(chan watchUpdate, watchSubscriberBuffer),
+		evicted: make(chan struct{}),
+	}
+	b.subs[sub] = struct{}{}
+	if b.stop == nil {
+		b.stop = make(chan struct{})
+		go b.run(b.stop, changed)
+	}
+	return sub, initial, nil
+}
+
+// replay returns the published events after lastID and the newest published
+// ID; reset reports that some events are no longer buffered.
+func (b *rosterBroadcaster) replay(lastID uint64) (events []rosterEvent, reset bool, latest uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	events, reset = b.replayLocked(lastID)
+	return events, reset, b.cursor
+}
+
+func (b *rosterBroadcaster) replayLocked(lastID uint64) ([]rosterEvent, bool) {
+	events, ok := b.feed.since(lastID)
+	if !ok {
+		return nil, true
+	}
+	for i, ev := range events {
+		if ev.ID > b.cursor {
+			return events[:i], false
+		}
+	}
+	return events, false
+}
+
+// unsubscribe removes a client, stopping the detector after the last one.
+func (b *rosterBroadcaster) unsubscribe(sub *watchSubscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subs, sub)
+	b.stopIfIdleLocked()
+}
+
+func (b *rosterBroadcaster) stopIfIdleLocked() {
+	if len(b.subs) == 0 && b.stop != nil {
+		close(b.stop)
+		b.stop = nil
+	}
+}
+
+func (b *rosterBroadcaster) stats() watchStats {
+	if b == nil {
+		return watchStats{}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return watchStats{Clients: len(b.subs), MaxClients: b.maxClients, Evicted: b.evicted}
+}
+
+// run waits for store writes until stop is closed. changed must be taken
+// before the roster was last diffed so no write is missed.
+func (b *rosterBroadcaster) run(stop chan struct{}, changed <-chan struct{}) {
+	ticker := time.NewTicker(b.fallback)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-changed:
+		case <-ticker.C:
+		}
+		// Take the next signal before diffing so a write that lands while
+		// publishing wakes the loop again.
+		changed = b.nextChange()
+		b.mu.Lock()
+		b.publishLocked()
+		b.mu.Unlock()
+	}
+}
+
+func (b *rosterBroadcaster) nextChange() <-chan struct{} {
+	if b.changed == nil {
+		return nil
+	}
+	return b.changed()
+}
+
+// publishLocked diffs the roster and queues an update for every subscriber,
+// evicting those whose queue is full.
+func (b *rosterBroadcaster) publishLocked() {
+	b.feed.poll()
+	pending, ok := b.feed.since(b.cursor)
+	update := watchUpdate{Events: pending, Reset: !ok}
+	if update.Reset {
+		b.cursor = b.feed.latest()
+	} else if len(pending) > 0 {
+		b.cursor = pending[len(pending)-1].ID
+	}
+	update.ID = b.cursor
+	if mod, err := fileModTime(b.path); err == nil && mod.After(b.modTime) {
+		b.modTime = mod
+		update.ModTime = mod
+	}
+	if len(update.Events) == 0 && !update.Reset && update.ModTime.IsZero() {
+		return
+	}
+	for sub := range b.subs {
+		select {
+		case sub.updates <- update:
+		default:
+			b.evictLocked(sub)
+		}
+	}
+}
+
+func (b *rosterBroadcaster) evictLocked(sub *watchSubscriber) {
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+	delete(b.subs, sub)
+	close(sub.evicted)
+	b.evicted++
+	b.stopIfIdleLocked()
+}
+
+// writeSSEUpdate encodes update as server-sent events: a roster.reset event
+// when events were lost, the named events matching filter, and the legacy
+// unnamed timestamp message when the roster file changed.
+func writeSSEUpdate(w io.Writer, update watchUpdate, filter rosterEventFilter) {
+	if update.Reset {
+		writeRosterEvent(w, rosterEvent{ID: update.ID, Type: rosterEventReset, Data: []byte("{}")})
+	}
+	for _, ev := range update.Events {
+		if filter.matches(ev) {
+			writeRosterEvent(w, ev)
+		}
+	}
+	if !update.ModTime.IsZero() {
+		writeWatchMessage(w, update.ID, update.ModTime)
+	}
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+)
+
+func TestRosterBroadcasterFansOutStoreWrites(t *testing.T) {
+	store := streamers.NewStore(filepath.Join(t.TempDir(), "streamers.json"))
+	b := newRosterBroadcaster(streamersWatchOptions{
+		FilePath:     store.Path(),
+		Feed:         newRosterFeed(store, "sharpen-live", false),
+		Changed:      store.Changed,
+		PollInterval: time.Hour,
+	})
+	first, _, err := b.subscribe(0, false)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	second, _, err := b.subscribe(0, false)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	defer b.unsubscribe(first)
+	defer b.unsubscribe(second)
+
+	if _, err := store.Append(streamers.Record{Streamer: streamers.Streamer{ID: "new", Alias: "New"}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	for i, sub := range []*watchSubscriber{first, second} {
+		select {
+		case update := <-sub.updates:
+			if len(update.Events) != 1 || update.Events[0].Type != rosterEventAdded || update.ModTime.IsZero() {
+				t.Fatalf("subscriber %d: unexpected update %+v", i, update)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("subscriber %d: expected the write to be broadcast", i)
+		}
+	}
+	if stats := b.stats(); stats.Clients != 2 || stats.MaxClients != defaultWatchMaxClients {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestRosterBroadcasterEvictsSlowConsumers(t *testing.T) {
+	store := &stubStreamersStore{path: filepath.Join(t.TempDir(), "missing.json")}
+	b := newRosterBroadcaster(streamersWatchOptions{
+		FilePath:     store.path,
+		Feed:         newRosterFeed(store, "sharpen-live", false),
+		PollInterval: time.Hour,
+	})
+	sub, _, err := b.subscribe(0, false)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	b.mu.Lock()
+	for i := 0; i <= watchSubscriberBuffer; i++ {
+		store.records = []streamers.Record{feedRecord("a", i%2 == 0)}
+		b.publishLocked()
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-sub.evicted:
+	default:
+		t.Fatalf("expected slow subscriber to be evicted")
+	}
+	if stats := b.stats(); stats.Clients != 0 || stats.Evicted != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	b.unsubscribe(sub)
+}
+
+func TestStreamersWatchEnforcesMaxClients(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "streamers.json")
+	b := newRosterBroadcaster(streamersWatchOptions{FilePath: path, MaxClients: 1})
+	sub, _, err := b.subscribe(0, false)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	defer b.unsubscribe(sub)
+
+	rr := httptest.NewRecorder()
+	streamersWatchHandler(b).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/streamers/watch", nil))
+	if rr.Code != http.StatusServiceUnavailable || rr.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected 503 with Retry-After, got %d", rr.Code)
+	}
+}
+
+func TestStreamersWatchSendsHeartbeats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "streamers.json")
+	b := newRosterBroadcaster(streamersWatchOptions{FilePath: path, Heartbeat: 5 * time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	rr := httptest.NewRecorder()
+	streamersWatchHandler(b).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/streamers/watch", nil).WithContext(ctx))
+
+	if !strings.Contains(rr.Body.String(), ": heartbeat\n\n") {
+		t.Fatalf("expected heartbeat comment, got %q", rr.Body.String())
+	}
+	if stats := b.stats(); stats.Clients != 0 {
+		t.Fatalf("expected client to be released, got %+v", stats)
+	}
+}
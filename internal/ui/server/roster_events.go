@@ -0,0 +1,299 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+)
+
+// Roster event types emitted on /streamers/watch.
+const (
+	rosterEventAdded   = "streamer.added"
+	rosterEventUpdated = "streamer.updated"
+	rosterEventRemoved = "streamer.removed"
+	rosterEventLive    = "streamer.live"
+	rosterEventOffline = "streamer.offline"
+	// rosterEventReset tells a client its Last-Event-ID can no longer be
+	// replayed and it should refetch /api/v1/streamers.
+	rosterEventReset = "roster.reset"
+)
+
+// rosterReplaySize bounds how many events a reconnecting client can catch up on.
+const rosterReplaySize = 512
+
+// rosterEvent is one change to the public roster.
+type rosterEvent struct {
+	ID         uint64
+	Type       string
+	StreamerID string
+	// Sites lists the sites the streamer appears on; empty means every site.
+	Sites []string
+	// Platforms lists the platforms the streamer is configured on.
+	Platforms []string
+	// Data is the JSON public projection of the streamer after the change, or
+	// before it for removals.
+	Data []byte
+}
+
+// rosterFeed turns roster writes into typed events by diffing successive
+// snapshots of a store's public projection. Event IDs increase monotonically
+// and are seeded from the start time, so IDs issued before a restart are
+// never reused for different events.
+type rosterFeed struct {
+	store  StreamersStore
+	site   string
+	shared bool
+
+	mu       sync.Mutex
+	primed   bool
+	modTime  time.Time
+	size     int64
+	snapshot map[string]rosterEntry
+	lastID   uint64
+	events   []rosterEvent
+}
+
+type rosterEntry struct {
+	live      bool
+	sites     []string
+	platforms []string
+	data      []byte
+}
+
+// rosterEventFilter limits which events a subscriber receives. Empty sets
+// match everything.
+type rosterEventFilter struct {
+	site      string
+	streamers map[string]struct{}
+	platforms map[string]struct{}
+}
+
+func newRosterFeed(store StreamersStore, site string, shared bool) *rosterFeed {
+	return &rosterFeed{
+		store:  store,
+		site:   site,
+		shared: shared,
+		lastID: uint64(time.Now().UnixMilli()) * 1000,
+	}
+}
+
+// poll records events for any roster change since the previous poll. The
+// store is only listed when its file changed on disk.
+func (f *rosterFeed) poll() {
+	if f == nil || f.store == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if path := f.store.Path(); path != "" {
+		if info, err := os.Stat(path); err == nil {
+			if f.primed && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+				return
+			}
+			f.modTime, f.size = info.ModTime(), info.Size()
+		}
+	}
+	records, err := f.store.List()
+	if err != nil {
+		return
+	}
+	next := make(map[string]rosterEntry, len(records))
+	var changes []rosterEvent
+	for _, record := range records {
+		id := record.Streamer.ID
+		data, err := json.Marshal(publicStreamerFromRecord(record))
+		if err != nil {
+			continue
+		}
+		entry := rosterEntry{
+			live:      record.Status != nil && record.Status.Live,
+			sites:     f.recordSites(record),
+			platforms: recordPlatforms(record),
+			data:      data,
+		}
+		next[id] = entry
+		prev, existed := f.snapshot[id]
+		eventType := ""
+		switch {
+		case !existed:
+			eventType = rosterEventAdded
+		case entry.live && !prev.live:
+			eventType = rosterEventLive
+		case !entry.live && prev.live:
+			eventType = rosterEventOffline
+		case !bytes.Equal(entry.data, prev.data) || !equalStrings(entry.sites, prev.sites):
+			eventType = rosterEventUpdated
+		}
+		if eventType != "" {
+			changes = append(changes, rosterEvent{Type: eventType, StreamerID: id, Sites: entry.sites, Platforms: entry.platforms, Data: data})
+		}
+	}
+	for id, prev := range f.snapshot {
+		if _, ok := next[id]; !ok {
+			changes = append(changes, rosterEvent{Type: rosterEventRemoved, StreamerID: id, Sites: prev.sites, Platforms: prev.platforms, Data: prev.data})
+		}
+	}
+	f.snapshot = next
+	if !f.primed {
+		f.primed = true
+		return
+	}
+	for _, ev := range changes {
+		f.lastID++
+		ev.ID = f.lastID
+		f.events = append(f.events, ev)
+	}
+	if excess := len(f.events) - rosterReplaySize; excess > 0 {
+		f.events = append([]rosterEvent(nil), f.events[excess:]...)
+	}
+}
+
+// recordSites returns the sites a record is shown on. Records without a site
+// list belong to every site of a shared roster, or to this feed's site.
+func (f *rosterFeed) recordSites(record streamers.Record) []string {
+	if len(record.Sites) > 0 {
+		return append([]string(nil), record.Sites...)
+	}
+	if f.shared || f.site == "" {
+		return nil
+	}
+	return []string{f.site}
+}
+
+func recordPlatforms(record streamers.Record) []string {
+	var platforms []string
+	if record.Platforms.YouTube != nil {
+		platforms = append(platforms, "youtube")
+	}
+	if record.Platforms.Twitch != nil {
+		platforms = append(platforms, "twitch")
+	}
+	if record.Platforms.Facebook != nil {
+		platforms = append(platforms, "facebook")
+	}
+	return platforms
+}
+
+// latest returns the ID of the newest event.
+func (f *rosterFeed) latest() uint64 {
+	if f == nil {
+		return 0
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lastID
+}
+
+// since returns the events after id. ok is false when id is unknown to this
+// feed or older than the replay buffer, meaning events were missed.
+func (f *rosterFeed) since(id uint64) (events []rosterEvent, ok bool) {
+	if f == nil {
+		return nil, true
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if id > f.lastID {
+		return nil, false
+	}
+	if id == f.lastID {
+		return nil, true
+	}
+	if len(f.events) == 0 || id < f.events[0].ID-1 {
+		return nil, false
+	}
+	for i, ev := range f.events {
+		if ev.ID > id {
+			return append([]rosterEvent(nil), f.events[i:]...), true
+		}
+	}
+	return nil, true
+}
+
+func parseRosterEventFilter(site string, ids, platforms []string) rosterEventFilter {
+	filter := rosterEventFilter{site: strings.TrimSpace(site)}
+	filter.streamers = addFilterValues(filter.streamers, ids, false)
+	filter.platforms = addFilterValues(filter.platforms, platforms, true)
+	return filter
+}
+
+// addFilterValues adds the comma-separated values to set, allocating it on
+// first use.
+func addFilterValues(set map[string]struct{}, values []string, lower bool) map[string]struct{} {
+	for _, raw := range values {
+		for _, value := range strings.Split(raw, ",") {
+			value = strings.TrimSpace(value)
+			if lower {
+				value = strings.ToLower(value)
+			}
+			if value == "" {
+				continue
+			}
+			if set == nil {
+				set = map[string]struct{}{}
+			}
+			set[value] = struct{}{}
+		}
+	}
+	return set
+}
+
+func (f rosterEventFilter) matches(ev rosterEvent) bool {
+	if ev.Type == rosterEventReset {
+		return true
+	}
+	if len(f.streamers) > 0 {
+		if _, ok := f.streamers[ev.StreamerID]; !ok {
+			return false
+		}
+	}
+	if len(f.platforms) > 0 {
+		found := false
+		for _, platform := range ev.Platforms {
+			if _, ok := f.platforms[platform]; ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.site != "" && len(ev.Sites) > 0 && !containsString(ev.Sites, f.site) {
+		return false
+	}
+	return true
+}
+
+func parseLastEventID(raw string) (uint64, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(raw, 10, 64)
+	return id, err == nil
+}
+
+func writeRosterEvent(w io.Writer, ev rosterEvent) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, ev.Data)
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
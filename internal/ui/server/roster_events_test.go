@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+)
+
+func feedRecord(id string, live bool, sites ...string) streamers.Record {
+	return streamers.Record{
+		Streamer: streamers.Streamer{ID: id, Alias: strings.ToUpper(id)},
+		Status:   &streamers.Status{Live: live},
+		Sites:    sites,
+	}
+}
+
+func eventTypes(events []rosterEvent) string {
+	parts := make([]string, 0, len(events))
+	for _, ev := range events {
+		parts = append(parts, ev.Type+":"+ev.StreamerID)
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestRosterFeedEmitsTypedEvents(t *testing.T) {
+	store := &stubStreamersStore{path: filepath.Join(t.TempDir(), "missing.json")}
+	feed := newRosterFeed(store, "sharpen-live", false)
+
+	store.records = []streamers.Record{feedRecord("a", false)}
+	feed.poll()
+	start := feed.latest()
+
+	steps := []struct {
+		records []streamers.Record
+		want    string
+	}{
+		{[]streamers.Record{feedRecord("a", true), feedRecord("b", false)}, "streamer.live:a,streamer.added:b"},
+		{[]streamers.Record{feedRecord("a", true), feedRecord("b", false)}, ""},
+		{[]streamers.Record{feedRecord("a", false)}, "streamer.offline:a,streamer.removed:b"},
+		{[]streamers.Record{{Streamer: streamers.Streamer{ID: "a", Alias: "Renamed"}}}, "streamer.updated:a"},
+	}
+	for i, step := range steps {
+		before := feed.latest()
+		store.records = step.records
+		feed.poll()
+		events, ok := feed.since(before)
+		if !ok {
+			t.Fatalf("step %d: expected events to be replayable", i)
+		}
+		if got := eventTypes(events); got != step.want {
+			t.Fatalf("step %d: expected %q, got %q", i, step.want, got)
+		}
+	}
+
+	events, _ := feed.since(start)
+	for i, ev := range events {
+		if ev.ID != start+uint64(i)+1 {
+			t.Fatalf("expected sequential IDs after %d, got %d at %d", start, ev.ID, i)
+		}
+		if strings.Contains(string(ev.Data), "email") || !strings.Contains(string(ev.Data), `"id":"`+ev.StreamerID+`"`) {
+			t.Fatalf("expected public projection, got %s", ev.Data)
+		}
+	}
+}
+
+func TestRosterFeedSinceDetectsGaps(t *testing.T) {
+	store := &stubStreamersStore{path: filepath.Join(t.TempDir(), "missing.json")}
+	feed := newRosterFeed(store, "sharpen-live", false)
+	feed.poll()
+	start := feed.latest()
+
+	for i := 0; i < rosterReplaySize+10; i++ {
+		store.records = []streamers.Record{feedRecord("a", i%2 == 0)}
+		feed.poll()
+	}
+	if _, ok := feed.since(start); ok {
+		t.Fatalf("expected events older than the buffer to be unavailable")
+	}
+	if _, ok := feed.since(feed.latest() + 1); ok {
+		t.Fatalf("expected unknown future ID to be unavailable")
+	}
+	events, ok := feed.since(feed.latest() - 3)
+	if !ok || len(events) != 3 {
+		t.Fatalf("expected last 3 events, got %d (ok=%v)", len(events), ok)
+	}
+}
+
+func TestRosterEventFilterSitesAndStreamers(t *testing.T) {
+	shared := newRosterFeed(nil, "base", true)
+	everywhere := rosterEvent{Type: rosterEventAdded, StreamerID: "a", Sites: shared.recordSites(feedRecord("a", false))}
+	synthOnly := rosterEvent{Type: rosterEventAdded, StreamerID: "b", Sites: shared.recordSites(feedRecord("b", false, "synth-wave"))}
+
+	tests := []struct {
+		name   string
+		filter rosterEventFilter
+		ev     rosterEvent
+		want   bool
+	}{
+		{"no filter", parseRosterEventFilter("", nil, nil), synthOnly, true},
+		{"site match", parseRosterEventFilter("synth-wave", nil, nil), synthOnly, true},
+		{"site miss", parseRosterEventFilter("base", nil, nil), synthOnly, false},
+		{"all sites", parseRosterEventFilter("base", nil, nil), everywhere, true},
+		{"streamer match", parseRosterEventFilter("", []string{"x,b"}, nil), synthOnly, true},
+		{"streamer miss", parseRosterEventFilter("", []string{"x"}, nil), everywhere, false},
+		{"platform match", parseRosterEventFilter("", nil, []string{"YouTube"}), rosterEvent{StreamerID: "c", Platforms: []string{"twitch", "youtube"}}, true},
+		{"platform miss", parseRosterEventFilter("", nil, []string{"facebook"}), rosterEvent{StreamerID: "c", Platforms: []string{"twitch"}}, false},
+		{"reset always", parseRosterEventFilter("base", []string{"x"}, nil), rosterEvent{Type: rosterEventReset}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.matches(tt.ev); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestStreamersWatchReplaysFromLastEventID(t *testing.T) {
+	store := &stubStreamersStore{path: filepath.Join(t.TempDir(), "missing.json")}
+	feed := newRosterFeed(store, "sharpen-live", false)
+	store.records = []streamers.Record{feedRecord("a", false)}
+	feed.poll()
+	lastSeen := feed.latest()
+	store.records = []streamers.Record{feedRecord("a", true), feedRecord("b", false)}
+	feed.poll()
+
+	handler := streamersWatchHandler(newRosterBroadcaster(streamersWatchOptions{
+		FilePath: store.path,
+		Feed:     feed,
+	}))
+	serve := func(target, lastEventID string) string {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		req := httptest.NewRequest(http.MethodGet, target, nil).WithContext(ctx)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Body.String()
+	}
+
+	body := serve("/streamers/watch?streamer=b", strconv.FormatUint(lastSeen, 10))
+	if !strings.Contains(body, "event: streamer.added\ndata: {") || strings.Contains(body, "streamer.live") {
+		t.Fatalf("expected only streamer b's event, got %q", body)
+	}
+	if !strings.Contains(body, "id: "+strconv.FormatUint(feed.latest(), 10)+"\ndata: ") {
+		t.Fatalf("expected legacy message carrying the latest ID, got %q", body)
+	}
+
+	if body := serve("/streamers/watch", "1"); !strings.Contains(body, "event: roster.reset") {
+		t.Fatalf("expected reset for unknown ID, got %q", body)
+	}
+	if body := serve("/streamers/watch", ""); strings.Contains(body, "event:") {
+		t.Fatalf("expected no replay without Last-Event-ID, got %q", body)
+	}
+}
@@ -0,0 +1,258 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Its-donkey/Sharpen-live/internal/websocket"
+)
+
+// wsClientMessage is a message sent by a /streamers/ws client.
+//
+//	{"type":"subscribe","site":"synth-wave","streamers":["id"],"platforms":["twitch"],"lastEventId":42}
+//	{"type":"unsubscribe","streamers":["id"]}
+//	{"type":"ping"}
+type wsClientMessage struct {
+	Type        string   `json:"type"`
+	Site        string   `json:"site,omitempty"`
+	Streamers   []string `json:"streamers,omitempty"`
+	Platforms   []string `json:"platforms,omitempty"`
+	LastEventID uint64   `json:"lastEventId,omitempty"`
+}
+
+// wsServerMessage is a message sent to a /streamers/ws client. Roster
+// changes arrive as {"type":"event","id":N,"event":"streamer.live","streamer":{...}}
+// where streamer is the public projection served by /api/v1/streamers.
+type wsServerMessage struct {
+	Type         string          `json:"type"`
+	ID           uint64          `json:"id,omitempty"`
+	Event        string          `json:"event,omitempty"`
+	Streamer     json.RawMessage `json:"streamer,omitempty"`
+	Subscription *wsSubscription `json:"subscription,omitempty"`
+	Error        string          `json:"error,omitempty"`
+}
+
+// wsSubscription is the client's current subscription, echoed after every
+// subscribe or unsubscribe.
+type wsSubscription struct {
+	Active    bool     `json:"active"`
+	Site      string   `json:"site,omitempty"`
+	Streamers []string `json:"streamers,omitempty"`
+	Platforms []string `json:"platforms,omitempty"`
+}
+
+// wsSession holds one connection's subscription and delivery cursor.
+type wsSession struct {
+	active bool
+	filter rosterEventFilter
+	// cursor is the newest event ID sent, so replayed events are not
+	// repeated when the same update is later broadcast.
+	cursor uint64
+}
+
+func (s *wsSession) apply(msg wsClientMessage) {
+	switch msg.Type {
+	case "subscribe":
+		s.active = true
+		if site := strings.TrimSpace(msg.Site); site != "" {
+			s.filter.site = site
+		}
+		s.filter.streamers = addFilterValues(s.filter.streamers, msg.Streamers, false)
+		s.filter.platforms = addFilterValues(s.filter.platforms, msg.Platforms, true)
+	case "unsubscribe":
+		if len(msg.Streamers) == 0 && len(msg.Platforms) == 0 {
+			*s = wsSession{cursor: s.cursor}
+			return
+		}
+		for _, id := range msg.Streamers {
+			delete(s.filter.streamers, strings.TrimSpace(id))
+		}
+		for _, platform := range msg.Platforms {
+			delete(s.filter.platforms, strings.ToLower(strings.TrimSpace(platform)))
+		}
+		// Dropping the last streamer or platform must not widen the
+		// subscription to everything.
+		if len(s.filter.streamers) == 0 && len(s.filter.platforms) == 0 {
+			s.active = false
+		}
+	}
+}
+
+func (s *wsSession) subscription() *wsSubscription {
+	out := &wsSubscription{Active: s.active, Site: s.filter.site}
+	for id := range s.filter.streamers {
+		out.Streamers = append(out.Streamers, id)
+	}
+	for platform := range s.filter.platforms {
+		out.Platforms = append(out.Platforms, platform)
+	}
+	sort.Strings(out.Streamers)
+	sort.Strings(out.Platforms)
+	return out
+}
+
+// messages converts events to wire messages for this session, skipping
+// anything already sent or outside the subscription.
+func (s *wsSession) messages(events []rosterEvent, reset bool, id uint64) []wsServerMessage {
+	var out []wsServerMessage
+	if reset {
+		out = append(out, wsServerMessage{Type: "reset", ID: id})
+		s.cursor = id
+	}
+	for _, ev := range events {
+		if ev.ID <= s.cursor {
+			continue
+		}
+		s.cursor = ev.ID
+		if s.active && s.filter.matches(ev) {
+			out = append(out, wsServerMessage{Type: "event", ID: ev.ID, Event: ev.Type, Streamer: ev.Data})
+		}
+	}
+	return out
+}
+
+// replay returns the buffered events after lastID that match the
+// subscription, regardless of what was already sent on this connection.
+func (s *wsSession) replay(events []rosterEvent, reset bool, latest, lastID uint64) []wsServerMessage {
+	replayed := wsSession{active: true, filter: s.filter, cursor: lastID}
+	out := replayed.messages(events, reset, latest)
+	if replayed.cursor > s.cursor {
+		s.cursor = replayed.cursor
+	}
+	return out
+}
+
+// handleStreamersWebSocket serves /streamers/ws, a WebSocket alternative to
+// /streamers/watch for clients that cannot rely on SSE. It shares the
+// watch broadcaster and its connection limit.
+func (s *server) handleStreamersWebSocket(w http.ResponseWriter, r *http.Request) {
+	b := s.rosterWatch
+	if b == nil {
+		http.Error(w, "streamers path not configured", http.StatusInternalServerError)
+		return
+	}
+	sub, initial, err := b.subscribe(0, false)
+	if err != nil {
+		w.Header().Set("Retry-After", "30")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer b.unsubscribe(sub)
+
+	conn, err := websocket.Upgrade(w, r, s.allowWebSocketOrigin)
+	if err != nil {
+		if errors.Is(err, websocket.ErrOriginNotAllowed) {
+			s.logger.Warn("http", "websocket origin rejected", map[string]any{
+				"origin": r.Header.Get("Origin"),
+				"host":   r.Host,
+			})
+		}
+		return
+	}
+	defer conn.Close(websocket.CloseGoingAway, "")
+
+	idleTimeout := 2*b.heartbeat + 5*time.Second
+	extend := func() { _ = conn.SetReadDeadline(time.Now().Add(idleTimeout)) }
+	conn.OnPong = extend
+	extend()
+
+	incoming := make(chan wsClientMessage)
+	readErr := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			op, data, err := conn.ReadMessage()
+			if err != nil {
+				readErr <- err
+				return
+			}
+			extend()
+			if op != websocket.OpText {
+				continue
+			}
+			var msg wsClientMessage
+			if err := json.Unmarshal(data, &msg); err != nil {
+				msg = wsClientMessage{Type: "invalid"}
+			}
+			select {
+			case incoming <- msg:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	session := &wsSession{cursor: initial.ID}
+	if err := conn.WriteJSON(wsServerMessage{Type: "hello", ID: initial.ID, Subscription: session.subscription()}); err != nil {
+		return
+	}
+
+	ping := time.NewTicker(b.heartbeat)
+	defer ping.Stop()
+
+	for {
+		var out []wsServerMessage
+		select {
+		case <-readErr:
+			return
+		case <-sub.evicted:
+			_ = conn.Close(websocket.ClosePolicy, "slow consumer")
+			return
+		case update := <-sub.updates:
+			out = session.messages(update.Events, update.Reset, update.ID)
+		case msg := <-incoming:
+			switch msg.Type {
+			case "subscribe", "unsubscribe":
+				session.apply(msg)
+				out = append(out, wsServerMessage{Type: "subscribed", ID: session.cursor, Subscription: session.subscription()})
+				if msg.Type == "subscribe" && msg.LastEventID > 0 {
+					events, reset, latest := b.replay(msg.LastEventID)
+					out = append(out, session.replay(events, reset, latest, msg.LastEventID)...)
+				}
+			case "ping":
+				out = append(out, wsServerMessage{Type: "pong"})
+			default:
+				out = append(out, wsServerMessage{Type: "error", Error: "unknown message type"})
+			}
+		case <-ping.C:
+			if err := conn.Ping(); err != nil {
+				return
+			}
+		}
+		for _, msg := range out {
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// allowWebSocketOrigin accepts requests without an Origin (non-browser
+// clients), same-origin pages, and origins listed in server.allowed_origins
+// ("*" allows any).
+func (s *server) allowWebSocketOrigin(r *http.Request) bool {
+	origin := strings.TrimSpace(r.Header.Get("Origin"))
+	if origin == "" {
+		return true
+	}
+	parsed, err := url.Parse(origin)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+	if strings.EqualFold(parsed.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range s.allowedOrigins {
+		allowed = strings.TrimSuffix(strings.TrimSpace(allowed), "/")
+		if allowed == "*" || strings.EqualFold(allowed, origin) || strings.EqualFold(allowed, parsed.Host) {
+			return true
+		}
+	}
+	return false
+}
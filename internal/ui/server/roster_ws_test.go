@@ -0,0 +1,201 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+	"github.com/Its-donkey/Sharpen-live/internal/websocket"
+)
+
+func newWebSocketTestServer(t *testing.T) (*server, *streamers.Store, *httptest.Server) {
+	t.Helper()
+	store := streamers.NewStore(filepath.Join(t.TempDir(), "streamers.json"))
+	srv := newTestServer()
+	srv.streamersStore = store
+	srv.rosterWatch = newRosterBroadcaster(streamersWatchOptions{
+		FilePath:     store.Path(),
+		Feed:         newRosterFeed(store, "sharpen-live", false),
+		Changed:      store.Changed,
+		PollInterval: time.Hour,
+	})
+	ts := httptest.NewServer(http.HandlerFunc(srv.handleStreamersWebSocket))
+	t.Cleanup(ts.Close)
+	return srv, store, ts
+}
+
+type wsTestClient struct {
+	t    *testing.T
+	conn net.Conn
+	br   *bufio.Reader
+}
+
+func dialWebSocket(t *testing.T, ts *httptest.Server) *wsTestClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(ts.URL, "http://"))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	req := "GET /streamers/ws HTTP/1.1\r\nHost: " + strings.TrimPrefix(ts.URL, "http://") + "\r\n" +
+		"Connection: Upgrade\r\nUpgrade: websocket\r\nSec-WebSocket-Version: 13\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nOrigin: " + ts.URL + "\r\n\r\n"
+	if _, err := io.WriteString(conn, req); err != nil {
+		t.Fatalf("handshake: %v", err)
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil || resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected 101, got %v %v", resp, err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	return &wsTestClient{t: t, conn: conn, br: br}
+}
+
+func (c *wsTestClient) send(msg wsClientMessage) {
+	c.t.Helper()
+	payload, _ := json.Marshal(msg)
+	mask := [4]byte{9, 8, 7, 6}
+	frame := []byte{0x80 | websocket.OpText, 0x80 | byte(len(payload))}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		c.t.Fatalf("send: %v", err)
+	}
+}
+
+// next returns the next JSON message, skipping control frames.
+func (c *wsTestClient) next() wsServerMessage {
+	c.t.Helper()
+	for {
+		var header [2]byte
+		if _, err := io.ReadFull(c.br, header[:]); err != nil {
+			c.t.Fatalf("read: %v", err)
+		}
+		length := int(header[1] & 0x7f)
+		if length == 126 {
+			var ext [2]byte
+			io.ReadFull(c.br, ext[:])
+			length = int(ext[0])<<8 | int(ext[1])
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(c.br, payload); err != nil {
+			c.t.Fatalf("read payload: %v", err)
+		}
+		if header[0]&0x0f != websocket.OpText {
+			continue
+		}
+		var msg wsServerMessage
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			c.t.Fatalf("decode %q: %v", payload, err)
+		}
+		return msg
+	}
+}
+
+func TestStreamersWebSocketDeliversSubscribedEvents(t *testing.T) {
+	_, store, ts := newWebSocketTestServer(t)
+	client := dialWebSocket(t, ts)
+
+	hello := client.next()
+	if hello.Type != "hello" || hello.Subscription == nil || hello.Subscription.Active {
+		t.Fatalf("unexpected hello %+v", hello)
+	}
+	client.send(wsClientMessage{Type: "subscribe", Streamers: []string{"bravo"}})
+	if ack := client.next(); ack.Type != "subscribed" || len(ack.Subscription.Streamers) != 1 {
+		t.Fatalf("unexpected ack %+v", ack)
+	}
+
+	for _, id := range []string{"alpha", "bravo"} {
+		if _, err := store.Append(streamers.Record{Streamer: streamers.Streamer{ID: id, Alias: id}}); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	ev := client.next()
+	if ev.Type != "event" || ev.Event != rosterEventAdded || !strings.Contains(string(ev.Streamer), `"id":"bravo"`) {
+		t.Fatalf("expected bravo added, got %+v", ev)
+	}
+
+	client.send(wsClientMessage{Type: "ping"})
+	if pong := client.next(); pong.Type != "pong" {
+		t.Fatalf("expected pong, got %+v", pong)
+	}
+}
+
+func TestStreamersWebSocketReplaysFromLastEventID(t *testing.T) {
+	srv, store, ts := newWebSocketTestServer(t)
+	client := dialWebSocket(t, ts)
+	hello := client.next()
+
+	if _, err := store.Append(streamers.Record{
+		Streamer:  streamers.Streamer{ID: "yt", Alias: "YT"},
+		Platforms: streamers.Platforms{YouTube: &streamers.YouTubePlatform{ChannelID: "UC1"}},
+	}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if _, err := store.Append(streamers.Record{
+		Streamer:  streamers.Streamer{ID: "tw", Alias: "TW"},
+		Platforms: streamers.Platforms{Twitch: &streamers.TwitchPlatform{Username: "tw"}},
+	}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, reset, latest := srv.rosterWatch.replay(hello.ID); !reset && latest >= hello.ID+2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("events were not published")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	client.send(wsClientMessage{Type: "subscribe", Platforms: []string{"twitch"}, LastEventID: hello.ID})
+	if ack := client.next(); ack.Type != "subscribed" {
+		t.Fatalf("expected ack, got %+v", ack)
+	}
+	ev := client.next()
+	if ev.Type != "event" || !strings.Contains(string(ev.Streamer), `"id":"tw"`) {
+		t.Fatalf("expected replayed twitch streamer, got %+v", ev)
+	}
+	client.send(wsClientMessage{Type: "ping"})
+	if next := client.next(); next.Type != "pong" {
+		t.Fatalf("expected no duplicate events, got %+v", next)
+	}
+}
+
+func TestAllowWebSocketOrigin(t *testing.T) {
+	srv := newTestServer()
+	srv.allowedOrigins = []string{"https://embed.example", "widgets.example"}
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"", true},
+		{"http://example.com", true},
+		{"https://embed.example", true},
+		{"https://widgets.example", true},
+		{"https://evil.example", false},
+		{"null", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/streamers/ws", nil)
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		if got := srv.allowWebSocketOrigin(req); got != tt.want {
+			t.Fatalf("origin %q: expected %v, got %v", tt.origin, tt.want, got)
+		}
+	}
+}
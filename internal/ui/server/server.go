@@ -6,23 +6,32 @@ import (
 	"errors"
 	"fmt"
 	"html/template"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	texttemplate "text/template"
 	"time"
 
 	adminauth "github.com/Its-donkey/Sharpen-live/internal/alert/admin/auth"
 	adminservice "github.com/Its-donkey/Sharpen-live/internal/alert/admin/service"
 	"github.com/Its-donkey/Sharpen-live/internal/alert/config"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/mail"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/notifier"
 	youtubeapi "github.com/Its-donkey/Sharpen-live/internal/alert/platforms/youtube/api"
 	youtubeservice "github.com/Its-donkey/Sharpen-live/internal/alert/platforms/youtube/service"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/platforms/youtube/streamend"
 	"github.com/Its-donkey/Sharpen-live/internal/alert/platforms/youtube/subscriptions"
 	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
 	streamersvc "github.com/Its-donkey/Sharpen-live/internal/alert/streamers/service"
 	"github.com/Its-donkey/Sharpen-live/internal/alert/submissions"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/subscribers"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/webhooks"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/webpush"
 	"github.com/Its-donkey/Sharpen-live/internal/metadata"
+	"github.com/Its-donkey/Sharpen-live/internal/ui/i18n"
 	"github.com/Its-donkey/Sharpen-live/internal/ui/model"
 	youtubeui "github.com/Its-donkey/Sharpen-live/internal/ui/platforms/youtube"
 	"github.com/Its-donkey/Sharpen-live/logging"
@@ -30,6 +39,7 @@ import (
 
 type Options struct {
 	Listen         string
+	Hosts          []string
 	TemplatesDir   string
 	AssetsDir      string
 	DataDir        string
@@ -46,6 +56,9 @@ type Options struct {
 	MetadataFetcher  MetadataFetcher
 	StatusChecker    StatusChecker
 	NewLeaseMonitor  LeaseMonitorFactory
+	// LaunchSite starts sites created from the admin wizard. When nil each
+	// new site gets its own listener.
+	LaunchSite SiteLauncher
 }
 
 // StreamersStore exposes the subset of streamer store behaviour required by the UI.
@@ -93,6 +106,8 @@ type LeaseMonitorFactory func(context.Context, subscriptions.LeaseMonitorConfig)
 
 type server struct {
 	assetsDir        string
+	assetDirs        []string
+	templateSources  []TemplateSource
 	stylesPath       string
 	socialImagePath  string
 	templates        map[string]*template.Template
@@ -118,10 +133,50 @@ type server struct {
 	logger           *logging.Logger
 	logDir           string
 	availableSites   []string
+	launchSite       SiteLauncher
 
 	// Store cache for multi-site WebSub support
 	storeCache   map[string]*streamers.Store
 	storeCacheMu sync.RWMutex
+	// sharedRoster is set when every site reads one streamers.json.
+	sharedRoster bool
+	// rosterWatch fans roster changes out to /streamers/watch and
+	// /streamers/ws clients.
+	rosterWatch *rosterBroadcaster
+	// allowedOrigins lists extra origins allowed to open /streamers/ws.
+	allowedOrigins []string
+	// hosts are the server.hosts names this site answers for.
+	hosts []string
+	// locales are the site's UI languages, default first; localeTemplates
+	// holds a template set per locale translated from catalog.
+	locales         []string
+	localeTemplates map[string]map[string]*template.Template
+	catalog         *i18n.Catalog
+	// startedAt bounds roster pages' Last-Modified, since templates and
+	// translations are loaded at start.
+	startedAt time.Time
+	// pages caches rendered public pages until the roster changes.
+	pages *pageCache
+	// webhooks queues roster events for the site's webhook endpoints.
+	webhooks *webhooks.Dispatcher
+	// announcer posts live announcements to the site's chat channels.
+	announcer *notifier.Notifier
+	// push notifies browsers that follow a streamer when it goes live.
+	push *webpush.Sender
+	// pushLimiter throttles /push/subscribe per client.
+	pushLimiter *clientLimiter
+	// mailer sends viewer email alerts; nil when no SMTP server is configured.
+	mailer mail.Sender
+	// subscribers holds the site's email alert subscriptions.
+	subscribers *subscribers.Store
+	// emailTemplates render the submission workflow emails.
+	emailTemplates map[string]*texttemplate.Template
+	// submissionDigestPath records when admins last got a submission digest.
+	submissionDigestPath string
+
+	// channelIndex routes WebSub/EventSub notifications to their streamers.
+	channelIndex     *streamers.ChannelIndex
+	channelIndexOnce sync.Once
 }
 
 type navAction struct {
@@ -132,6 +187,7 @@ type navAction struct {
 type basePageData struct {
 	PageTitle       string
 	StylesheetPath  string
+	ScriptPath      string
 	SubmitLink      string
 	SecondaryAction *navAction
 	CurrentYear     int
@@ -142,6 +198,10 @@ type basePageData struct {
 	OGType          string
 	Robots          string
 	StructuredData  template.JS
+	OEmbedURL       string
+	Locale          string
+	Alternates      []localeAlternate
+	LocaleLinks     []localeLink
 	FallbackErrors  []string
 }
 
@@ -166,6 +226,18 @@ type streamerPageData struct {
 
 // Run starts the UI HTTP server using the provided context and options.
 func Run(ctx context.Context, opts Options) error {
+	site, err := NewSite(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer site.Close()
+	return serve(ctx, site.Listen, site.Handler)
+}
+
+// NewSite resolves configuration for a single site and wires its stores,
+// services, background workers and handler tree without binding a listener.
+// Callers must call Close once the handler is no longer served.
+func NewSite(ctx context.Context, opts Options) (*Site, error) {
 	if opts.ConfigPath == "" {
 		opts.ConfigPath = "config.json"
 	}
@@ -203,46 +275,50 @@ func Run(ctx context.Context, opts Options) error {
 	}
 	opts = applyDefaults(opts, siteConfig)
 
-	templateRoot, err := filepath.Abs(opts.TemplatesDir)
+	templateDirs, assetDirs, err := themeDirs(opts.TemplatesDir, opts.AssetsDir, config.ThemeChain(siteConfig, appConfig)[1:])
 	if err != nil {
 		if usingAlertserver {
-			return fmt.Errorf("resolve templates dir: %w", err)
+			return nil, err
 		}
-		appendFallback(fmt.Sprintf("template path %q failed: %v", opts.TemplatesDir, err))
+		appendFallback(fmt.Sprintf("theme paths for %q failed: %v", opts.TemplatesDir, err))
 		siteConfig, opts = switchToAlertserver(appConfig, opts)
 		usingAlertserver = true
-		templateRoot, err = filepath.Abs(opts.TemplatesDir)
+		templateDirs, assetDirs, err = themeDirs(opts.TemplatesDir, opts.AssetsDir, nil)
 		if err != nil {
-			return fmt.Errorf("resolve default-site templates dir: %w", err)
+			return nil, fmt.Errorf("resolve default-site theme: %w", err)
 		}
 	}
 
 	tmpl := opts.Templates
+	locales := siteLocales(siteConfig.App)
+	var templateSources []TemplateSource
+	var localeTemplates map[string]map[string]*template.Template
+	var catalog *i18n.Catalog
 	if tmpl == nil {
-		loaded, err := loadTemplates(templateRoot)
+		loaded, sources, loadedCatalog, err := loadSiteTemplates(locales, templateDirs...)
 		if err != nil {
 			if usingAlertserver {
-				return fmt.Errorf("load default-site templates: %w", err)
+				return nil, fmt.Errorf("load default-site templates: %w", err)
 			}
-			appendFallback(fmt.Sprintf("failed to load templates from %s: %v", templateRoot, err))
+			appendFallback(fmt.Sprintf("failed to load templates from %s: %v", templateDirs[0], err))
 			siteConfig, opts = switchToAlertserver(appConfig, opts)
 			usingAlertserver = true
-			templateRoot, err = filepath.Abs(opts.TemplatesDir)
+			locales = siteLocales(siteConfig.App)
+			templateDirs, assetDirs, err = themeDirs(opts.TemplatesDir, opts.AssetsDir, nil)
 			if err != nil {
-				return fmt.Errorf("resolve default-site templates dir: %w", err)
+				return nil, fmt.Errorf("resolve default-site theme: %w", err)
 			}
-			loaded, err = loadTemplates(templateRoot)
+			loaded, sources, loadedCatalog, err = loadSiteTemplates(locales, templateDirs...)
 			if err != nil {
-				return fmt.Errorf("load default-site templates: %w", err)
+				return nil, fmt.Errorf("load default-site templates: %w", err)
 			}
 		}
-		tmpl = loaded
-	}
-
-	assetsPath, err := filepath.Abs(opts.AssetsDir)
-	if err != nil {
-		return fmt.Errorf("resolve assets dir: %w", err)
+		tmpl = loaded[locales[0]]
+		localeTemplates = loaded
+		templateSources = sources
+		catalog = loadedCatalog
 	}
+	assetsPath := assetDirs[0]
 
 	dataDir := opts.DataDir
 	if dataDir == "" {
@@ -250,12 +326,15 @@ func Run(ctx context.Context, opts Options) error {
 	}
 	dataDir, err = filepath.Abs(dataDir)
 	if err != nil {
-		return fmt.Errorf("resolve data dir: %w", err)
+		return nil, fmt.Errorf("resolve data dir: %w", err)
 	}
 
 	streamersStore := opts.StreamersStore
 	if streamersStore == nil {
-		streamersStore = streamers.NewStore(filepath.Join(dataDir, "streamers.json"))
+		streamersStore, err = resolveStreamersStore(appConfig, siteConfig, dataDir, usingAlertserver)
+		if err != nil {
+			return nil, err
+		}
 	}
 	submissionsStore := opts.SubmissionsStore
 	if submissionsStore == nil {
@@ -265,7 +344,7 @@ func Run(ctx context.Context, opts Options) error {
 	if streamerSvc == nil {
 		baseStore, ok := streamersStore.(*streamers.Store)
 		if !ok {
-			return fmt.Errorf("streamer service requires *streamers.Store when not injected")
+			return nil, fmt.Errorf("streamer service requires *streamers.Store when not injected")
 		}
 		streamerSvc = streamersvc.New(streamersvc.Options{
 			Streamers:          baseStore,
@@ -286,11 +365,16 @@ func Run(ctx context.Context, opts Options) error {
 	logDir := filepath.Join(dataDir, "logs")
 	fileWriter, err := logging.NewFileWriter(logDir, "app.log", 50, 10)
 	if err != nil {
-		return fmt.Errorf("create log file writer: %w", err)
+		return nil, fmt.Errorf("create log file writer: %w", err)
+	}
+	site := &Site{
+		Key:     siteConfig.Key,
+		Listen:  opts.Listen,
+		closers: []func(){func() { _ = fileWriter.Close() }},
 	}
-	defer fileWriter.Close()
 
 	logger := logging.New(siteConfig.Key, logging.INFO, fileWriter, os.Stdout)
+	site.logger = logger
 	logger.Info("server", "Starting server", map[string]any{
 		"site":   siteConfig.Name,
 		"listen": opts.Listen,
@@ -339,11 +423,53 @@ func Run(ctx context.Context, opts Options) error {
 	// Resolve Twitch EventSub callback URL from site config
 	twitchEventSubCallback := siteConfig.TwitchCallback
 
+	webhookDispatcher := webhooks.NewDispatcher(webhooks.NewStore(filepath.Join(dataDir, "webhooks.json")), webhooks.Options{
+		Site: siteConfig.Key,
+	})
+	webhookCtx, stopWebhooks := context.WithCancel(ctx)
+	site.closers = append(site.closers, stopWebhooks)
+	go webhookDispatcher.Run(webhookCtx)
+
+	announcer, err := notifier.New(announcementChannels(siteConfig.Announcements), notifier.Options{
+		StatePath: filepath.Join(dataDir, "announcements.json"),
+	})
+	if err != nil {
+		logger.Warn("announce", "Chat announcements disabled", map[string]any{
+			"error": err.Error(),
+		})
+	}
+
+	var mailer mail.Sender
+	if strings.TrimSpace(appConfig.Mail.Addr) != "" {
+		smtpSender, err := mail.NewSMTP(mail.SMTPOptions{
+			Addr:     appConfig.Mail.Addr,
+			Username: appConfig.Mail.Username,
+			Password: appConfig.Mail.Password,
+			From:     appConfig.Mail.From,
+			FromName: siteConfig.Name,
+		})
+		if err != nil {
+			logger.Warn("email", "Email alerts disabled", map[string]any{
+				"error": err.Error(),
+			})
+		} else {
+			mailer = smtpSender
+		}
+	}
+
+	emailTemplates, err := loadEmailTemplates(catalog, locales[0], templateDirs...)
+	if err != nil && mailer != nil {
+		logger.Warn("email", "Submission emails disabled", map[string]any{
+			"error": err.Error(),
+		})
+	}
+
 	adminSubSvc := opts.AdminSubmissions
 	if adminSubSvc == nil {
 		baseStore, ok := streamersStore.(*streamers.Store)
 		if !ok {
-			return fmt.Errorf("admin submissions requires *streamers.Store when not injected")
+			site.Close()
+			return nil, fmt.Errorf("admin submissions requires *streamers.Store when not injected")
 		}
 		adminSubSvc = adminservice.NewSubmissionsService(adminservice.SubmissionsOptions{
 			SubmissionsStore:       submissionsStore,
@@ -355,6 +481,15 @@ func Run(ctx context.Context, opts Options) error {
 			TwitchClientSecret:     appConfig.Twitch.ClientSecret,
 			TwitchEventSubSecret:   appConfig.Twitch.EventSubSecret,
 			TwitchEventSubCallback: twitchEventSubCallback,
+			OnApproved: func(record streamers.Record) {
+				if err := webhookDispatcher.Emit(webhooks.EventStreamerApproved, webhookData(record, "")); err != nil {
+					logger.Warn("webhooks", "Failed to queue webhook", map[string]any{
+						"event":    webhooks.EventStreamerApproved,
+						"streamer": record.Streamer.ID,
+						"error":    err.Error(),
+					})
+				}
+			},
 		})
 	}
 	adminMgr := opts.AdminManager
@@ -370,7 +505,8 @@ func Run(ctx context.Context, opts Options) error {
 	if statusChecker == nil {
 		baseStore, ok := streamersStore.(*streamers.Store)
 		if !ok {
-			return fmt.Errorf("status checker requires *streamers.Store when not injected")
+			site.Close()
+			return nil, fmt.Errorf("status checker requires *streamers.Store when not injected")
 		}
 		statusChecker = adminservice.StatusChecker{
 			Streamers: baseStore,
@@ -385,16 +521,10 @@ func Run(ctx context.Context, opts Options) error {
 
 	primaryHost := canonicalHostFromURL(websubCallbackURL)
 
-	siteDescription := "Sharpen.Live tracks live knife sharpeners and bladesmith streams across YouTube, Twitch, and Facebook - find makers, tutorials, and sharpening resources."
-	switch {
-	case strings.EqualFold(siteConfig.Key, config.AlertserverKey) || strings.EqualFold(siteConfig.Name, config.AlertserverKey) || strings.EqualFold(siteConfig.Name, "default-site"):
-		siteDescription = "Alertserver Admin appears when a requested site cannot be served. Review the errors below to restore the site configuration."
-	case strings.EqualFold(siteConfig.Key, "synth-wave") || strings.EqualFold(siteConfig.Name, "synth.wave"):
-		siteDescription = "synth.wave tracks live synthwave, chillwave, and electronic music streams so you can ride the neon frequencies in real time."
-	}
-
 	srv := &server{
 		assetsDir:        assetsPath,
+		assetDirs:        assetDirs,
+		templateSources:  templateSources,
 		stylesPath:       "/styles.css",
 		socialImagePath:  "/og-image.png",
 		templates:        tmpl,
@@ -411,7 +541,8 @@ func Run(ctx context.Context, opts Options) error {
 		metadataFetcher:  metadataSvc,
 		siteName:         siteConfig.Name,
 		siteKey:          siteConfig.Key,
-		siteDescription:  siteDescription,
+		allowedOrigins:   siteConfig.Server.AllowedOrigins,
+		siteDescription:  siteDescriptionFor(siteConfig),
 		primaryHost:      primaryHost,
 		youtubeConfig:    appConfig.YouTube,
 		twitchConfig:     appConfig.Twitch,
@@ -421,6 +552,37 @@ func Run(ctx context.Context, opts Options) error {
 		logDir:           logDir,
 		availableSites:   configuredSiteKeys(appConfig),
 		storeCache:       make(map[string]*streamers.Store),
+		sharedRoster:     opts.StreamersStore == nil && config.SharedRosterPath(appConfig) != "",
+		launchSite:       opts.LaunchSite,
+		locales:          locales,
+		localeTemplates:  localeTemplates,
+		catalog:          catalog,
+		startedAt:        time.Now(),
+		pages:            newPageCache(),
+		webhooks:         webhookDispatcher,
+		announcer:        announcer,
+		push: webpush.NewSender(webpush.NewStore(filepath.Join(dataDir, "push.json")), webpush.Options{
+			Subject: pushSubject(appConfig.Admin.Email, primaryHost),
+		}),
+		pushLimiter:          newClientLimiter(pushSubscribeLimit, time.Minute),
+		mailer:               mailer,
+		subscribers:          subscribers.NewStore(filepath.Join(dataDir, "subscribers.json")),
+		emailTemplates:       emailTemplates,
+		submissionDigestPath: filepath.Join(dataDir, "submission_digest.json"),
+	}
+	site.closers = append(site.closers, registerSite(srv))
+	if srv.emailAlertsEnabled() {
+		digestCtx, stopDigest := context.WithCancel(ctx)
+		site.closers = append(site.closers, stopDigest)
+		go srv.runEmailDigest(digestCtx)
+	}
+	if srv.submissionDigestEnabled() {
+		submissionDigestCtx, stopSubmissionDigest := context.WithCancel(ctx)
+		site.closers = append(site.closers, stopSubmissionDigest)
+		go srv.runSubmissionDigest(submissionDigestCtx)
+	}
+	if srv.launchSite == nil {
+		srv.launchSite = standaloneLauncher(ctx, opts.ConfigPath)
 	}
 
 	// Check initial live status for all streamers in background
@@ -438,7 +600,10 @@ func Run(ctx context.Context, opts Options) error {
 		monitorFactory = subscriptions.StartLeaseMonitor
 	}
 	var monitor *subscriptions.LeaseMonitor
-	if streamersStore.Path() != "" {
+	// A shared roster is renewed by whichever site claims it first.
+	release, claimed := claimLeaseMonitor(streamersStore.Path())
+	if claimed {
+		site.closers = append(site.closers, release)
 		monitor = monitorFactory(ctx, subscriptions.LeaseMonitorConfig{
 			StreamersPath: streamersStore.Path(),
 			Interval:      time.Minute,
@@ -454,20 +619,53 @@ func Run(ctx context.Context, opts Options) error {
 
 			},
 		})
-		defer monitor.Stop()
+		site.closers = append(site.closers, monitor.Stop)
+	}
+
+	// WebSub does not say when a broadcast ends, so live videos are polled.
+	if store := srv.rosterStore(); store != nil {
+		if release, claimed := claimStreamEndTracker(store.Path()); claimed {
+			tracker := streamend.Start(ctx, streamend.Config{
+				Store: store,
+				OnEnded: func(record streamers.Record) {
+					logger.Info("youtube", "YouTube stream ended", map[string]any{
+						"streamerId": record.Streamer.ID,
+						"alias":      record.Streamer.Alias,
+					})
+					emitRosterEvent(store, webhooks.EventStreamerOffline, record, "youtube")
+				},
+				OnError: func(err error) {
+					logger.Warn("youtube", "Stream end check failed", map[string]any{"error": err.Error()})
+				},
+			})
+			site.closers = append(site.closers, tracker.Stop, release)
+		}
 	}
 
 	alertPaths := youtubeui.CallbackPaths(appConfig.YouTube.CallbackURL)
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("/", srv.handleHome)
-	mux.HandleFunc("/streamers/", srv.handleStreamer)
+	mux.HandleFunc("/", srv.cachePage(srv.handleHome))
+	mux.HandleFunc("/streamers/", srv.cachePage(srv.handleStreamer))
 	mux.HandleFunc("/submit", srv.handleSubmit)
 	mux.Handle("/styles.css", srv.assetHandler("styles.css", "text/css"))
 	mux.Handle("/submit.js", srv.assetHandler("submit.js", "application/javascript"))
 	mux.Handle("/og-image.png", srv.assetHandler("og-image.png", "image/png"))
+	mux.Handle("/push.js", srv.assetHandler("push.js", "application/javascript"))
+	mux.Handle("/push-sw.js", srv.assetHandler("push-sw.js", "application/javascript"))
+	mux.HandleFunc("/push/key", srv.handlePushKey)
+	mux.HandleFunc("/push/subscribe", srv.handlePushSubscribe)
+	mux.HandleFunc("/email/subscribe", srv.handleEmailSubscribe)
+	mux.HandleFunc("/email/confirm", srv.handleEmailConfirm)
+	mux.HandleFunc("/email/unsubscribe", srv.handleEmailUnsubscribe)
+	mux.HandleFunc("/static/", srv.handleStatic)
 	mux.HandleFunc("/robots.txt", srv.handleRobots)
 	mux.HandleFunc("/sitemap.xml", srv.handleSitemap)
+	mux.HandleFunc("/languages/{lang}", srv.handleLanguagePage)
+	mux.HandleFunc("/countries/{code}", srv.handleCountryPage)
+	mux.HandleFunc("/feeds/{feed}", srv.handleFeed)
+	mux.HandleFunc("/widget", srv.handleWidget)
+	mux.HandleFunc("/oembed", srv.handleOEmbed)
 	mux.HandleFunc("/admin", srv.handleAdmin)
 	mux.HandleFunc("/admin/", srv.handleAdmin)
 	mux.HandleFunc("/admin/login", srv.handleAdminLogin)
@@ -475,14 +673,25 @@ func Run(ctx context.Context, opts Options) error {
 	mux.HandleFunc("/admin/submissions", srv.handleAdminSubmission)
 	mux.HandleFunc("/admin/streamers/update", srv.handleAdminStreamerUpdate)
 	mux.HandleFunc("/admin/streamers/delete", srv.handleAdminStreamerDelete)
+	mux.HandleFunc("/admin/streamers/sites", srv.handleAdminStreamerSites)
 	mux.HandleFunc("/admin/status-check", srv.handleAdminStatusCheck)
 	mux.HandleFunc("/admin/youtube/settings", srv.handleAdminYouTubeSettings)
 	mux.HandleFunc("/admin/config", srv.handleAdminConfig)
-	streamersWatch := streamersWatchHandler(streamersWatchOptions{
-		FilePath: srv.streamersStore.Path(),
-	})
+	mux.HandleFunc("/admin/sites/new", srv.handleAdminSiteCreate)
+	mux.HandleFunc("/admin/webhooks", srv.handleAdminWebhooks)
+	watchOpts := streamersWatchOptions{
+		FilePath:   srv.streamersStore.Path(),
+		Feed:       newRosterFeed(srv.streamersStore, config.RosterSiteKey(srv.siteKey), srv.sharedRoster),
+		MaxClients: siteConfig.Server.WatchMaxClients,
+	}
+	if notifier, ok := srv.streamersStore.(interface{ Changed() <-chan struct{} }); ok {
+		watchOpts.Changed = notifier.Changed
+	}
+	srv.rosterWatch = newRosterBroadcaster(watchOpts)
+	streamersWatch := streamersWatchHandler(srv.rosterWatch)
 	mux.Handle("/streamers/watch", streamersWatch)
 	mux.Handle("/api/streamers/watch", streamersWatch)
+	mux.HandleFunc("/streamers/ws", srv.handleStreamersWebSocket)
 	mux.HandleFunc("/api/metadata", srv.handleMetadata)
 	// mux.HandleFunc("/api/youtube/metadata", srv.handleMetadata)
 	websubRegistered := false
@@ -501,6 +710,8 @@ func Run(ctx context.Context, opts Options) error {
 			mux.Handle(path, alertsHandler)
 		}
 	}
+	mux.HandleFunc("/api/v1/streamers", srv.handleAPIStreamers)
+	mux.HandleFunc("/api/v1/streamers/{id}", srv.handleAPIStreamer)
 	mux.HandleFunc("/streamers.json", srv.serveStreamersJSON)
 	mux.HandleFunc("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNoContent)
@@ -526,16 +737,71 @@ func Run(ctx context.Context, opts Options) error {
 
 	// Wrap with logging middleware
 	httpLogger := logging.NewHTTPLogger(logger, 10*1024)
-	handler := httpLogger.Middleware(mux)
+	site.Handler = compressHandler(httpLogger.Middleware(srv.localeHandler(mux)))
+	site.Hosts = normaliseHosts(siteConfig.Server.Hosts)
+	if len(opts.Hosts) > 0 {
+		site.Hosts = normaliseHosts(opts.Hosts)
+	}
+	srv.hosts = site.Hosts
+	return site, nil
+}
+
+// siteDescriptionFor returns the site's default meta description. The
+// configured description wins; the bundled sites fall back to their own copy
+// when config.json leaves it blank.
+func siteDescriptionFor(siteConfig config.SiteConfig) string {
+	description := strings.TrimSpace(siteConfig.Description)
+	switch {
+	case strings.EqualFold(siteConfig.Key, config.AlertserverKey) || strings.EqualFold(siteConfig.Name, config.AlertserverKey) || strings.EqualFold(siteConfig.Name, "default-site"):
+		return "Alertserver Admin appears when a requested site cannot be served. Review the errors below to restore the site configuration."
+	case description != "":
+		return description
+	case strings.EqualFold(siteConfig.Key, "synth-wave") || strings.EqualFold(siteConfig.Name, "synth.wave"):
+		return "synth.wave tracks live synthwave, chillwave, and electronic music streams so you can ride the neon frequencies in real time."
+	}
+	return "Sharpen.Live tracks live knife sharpeners and bladesmith streams across YouTube, Twitch, and Facebook - find makers, tutorials, and sharpening resources."
+}
+
+// resolveStreamersStore returns the site's roster. With a shared roster every
+// site gets a view of the one file scoped to its key, while the alertserver
+// control room sees the whole roster.
+func resolveStreamersStore(appConfig config.Config, siteConfig config.SiteConfig, dataDir string, usingAlertserver bool) (*streamers.Store, error) {
+	sharedPath := config.SharedRosterPath(appConfig)
+	if sharedPath == "" {
+		return streamers.SharedStore(filepath.Join(dataDir, "streamers.json")), nil
+	}
+	sharedPath, err := filepath.Abs(sharedPath)
+	if err != nil {
+		return nil, fmt.Errorf("resolve shared roster path: %w", err)
+	}
+	shared := streamers.SharedStore(sharedPath)
+	if usingAlertserver {
+		return shared, nil
+	}
+	return shared.ForSite(config.RosterSiteKey(siteConfig.Key)), nil
+}
+
+// serve binds the handler to listen and blocks until ctx is cancelled or the
+// listener fails.
+func serve(ctx context.Context, listen string, handler http.Handler) error {
+	ln, err := net.Listen("tcp", listenAddr(listen))
+	if err != nil {
+		return fmt.Errorf("server error: %w", err)
+	}
+	return serveListener(ctx, ln, handler)
+}
 
+// serveListener serves handler on an already bound listener until ctx is
+// cancelled or serving fails.
+func serveListener(ctx context.Context, ln net.Listener, handler http.Handler) error {
 	server := &http.Server{
-		Addr:    opts.Listen,
+		Addr:    ln.Addr().String(),
 		Handler: handler,
 	}
 
 	errCh := make(chan error, 1)
 	go func() {
-		errCh <- server.ListenAndServe()
+		errCh <- server.Serve(ln)
 	}()
 
 	select {
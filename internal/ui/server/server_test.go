@@ -8,12 +8,14 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	adminauth "github.com/Its-donkey/Sharpen-live/internal/alert/admin/auth"
 	adminservice "github.com/Its-donkey/Sharpen-live/internal/alert/admin/service"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/config"
 	youtubeservice "github.com/Its-donkey/Sharpen-live/internal/alert/platforms/youtube/service"
 	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
 	streamersvc "github.com/Its-donkey/Sharpen-live/internal/alert/streamers/service"
@@ -332,9 +334,9 @@ func TestHandleRobots(t *testing.T) {
 
 func TestStreamersWatchAlias(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "streamers.json")
-	handler := streamersWatchHandler(streamersWatchOptions{
+	handler := streamersWatchHandler(newRosterBroadcaster(streamersWatchOptions{
 		FilePath: path,
-	})
+	}))
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
@@ -434,6 +436,8 @@ type stubAdminSubmissions struct {
 	listErr    error
 	processErr error
 	lastReq    adminservice.ActionRequest
+	// submission is returned as the processed submission when set.
+	submission submissions.Submission
 }
 
 func (s *stubAdminSubmissions) List(context.Context) ([]submissions.Submission, error) {
@@ -445,7 +449,13 @@ func (s *stubAdminSubmissions) Process(ctx context.Context, req adminservice.Act
 	if s.processErr != nil {
 		return adminservice.ActionResult{}, s.processErr
 	}
-	return adminservice.ActionResult{Status: req.Action, Submission: submissions.Submission{ID: req.ID}}, nil
+	sub := s.submission
+	sub.ID = req.ID
+	result := adminservice.ActionResult{Status: req.Action, Submission: sub}
+	if req.Action == adminservice.ActionReject {
+		result.Reason = strings.TrimSpace(req.Reason)
+	}
+	return result, nil
 }
 
 type stubAdminManager struct {
@@ -491,3 +501,45 @@ func (s stubMetadataService) Fetch(context.Context, string) (*metadata.Metadata,
 	}
 	return &s.data, nil
 }
+
+func TestSiteDescriptionFor(t *testing.T) {
+	tests := []struct {
+		site config.SiteConfig
+		want string
+	}{
+		{config.SiteConfig{Key: "neon-forge", Name: "Neon Forge", Description: " Glowing blades "}, "Glowing blades"},
+		{config.SiteConfig{Key: "synth-wave", Name: "synth.wave"}, "synth.wave tracks"},
+		{config.SiteConfig{Key: "sharpen-live", Name: "Sharpen.Live"}, "Sharpen.Live tracks"},
+		{config.SiteConfig{Key: config.AlertserverKey, Description: "Fallback"}, "Alertserver Admin appears"},
+	}
+	for _, tt := range tests {
+		if got := siteDescriptionFor(tt.site); !strings.HasPrefix(got, tt.want) {
+			t.Fatalf("%s: expected %q, got %q", tt.site.Key, tt.want, got)
+		}
+	}
+}
+
+func TestCopiedThemeUsesSiteNameAndDescription(t *testing.T) {
+	sites := filepath.Join("..", "..", "..", "ui", "sites")
+	for _, theme := range []string{"sharpen-live", "synth-wave"} {
+		templates, _, err := loadTemplates(filepath.Join(sites, theme, "templates"), filepath.Join(sites, "default-site", "templates"))
+		if err != nil {
+			t.Fatalf("load %s templates: %v", theme, err)
+		}
+		srv := newTestServer()
+		srv.streamersStore = &stubStreamersStore{}
+		srv.templates = templates
+		srv.siteName = "Neon Forge"
+		srv.siteDescription = "Glowing blades"
+
+		rr := httptest.NewRecorder()
+		srv.handleHome(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+		body := rr.Body.String()
+		if rr.Code != http.StatusOK || !strings.Contains(body, "<h1>Neon Forge</h1>") || !strings.Contains(body, `content="Glowing blades"`) {
+			t.Fatalf("%s: expected the new site's name and description, got %d %s", theme, rr.Code, body)
+		}
+		if strings.Contains(body, "tracks live") || strings.Contains(body, "&copy; "+strconv.Itoa(srv.currentYear)+" Sharpen") {
+			t.Fatalf("%s: expected no copy of the source site's name or description", theme)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"sync"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/config"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+	"github.com/Its-donkey/Sharpen-live/logging"
+)
+
+// Site is a fully wired handler tree for one configured site.
+type Site struct {
+	Key     string
+	Listen  string
+	Hosts   []string
+	Handler http.Handler
+
+	logger  *logging.Logger
+	closers []func()
+}
+
+// Close releases the site's log writers and background workers.
+func (s *Site) Close() {
+	if s == nil {
+		return
+	}
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		s.closers[i]()
+	}
+	s.closers = nil
+}
+
+// SiteLauncher starts a site that was added to config while the process runs.
+type SiteLauncher func(key string) error
+
+// standaloneLauncher serves each launched site on its own listener until ctx
+// is cancelled. The listener is bound before returning so address errors are
+// reported to the caller.
+func standaloneLauncher(ctx context.Context, configPath string) SiteLauncher {
+	return func(key string) error {
+		site, err := NewSite(ctx, Options{ConfigPath: configPath, Site: key})
+		if err != nil {
+			return err
+		}
+		ln, err := net.Listen("tcp", listenAddr(site.Listen))
+		if err != nil {
+			site.Close()
+			return fmt.Errorf("listen on %s: %w", site.Listen, err)
+		}
+		go func() {
+			defer site.Close()
+			if err := serveListener(ctx, ln, site.Handler); err != nil && !errors.Is(err, context.Canceled) {
+				fmt.Printf("ERROR: site %s stopped: %v\n", key, err)
+			}
+		}()
+		return nil
+	}
+}
+
+// leaseMonitors records the streamers files that already have a lease monitor,
+// so sites sharing one roster renew each subscription once.
+var leaseMonitors sync.Map
+
+// claimLeaseMonitor reports whether the caller should run the lease monitor
+// for path. The returned release func frees the claim when the site closes.
+func claimLeaseMonitor(path string) (func(), bool) {
+	return claimRoster(&leaseMonitors, path)
+}
+
+// streamEndTrackers records the streamers files that already have a YouTube
+// stream end tracker, so each live video is polled once.
+var streamEndTrackers sync.Map
+
+// claimStreamEndTracker reports whether the caller should run the stream end
+// tracker for path, like claimLeaseMonitor.
+func claimStreamEndTracker(path string) (func(), bool) {
+	return claimRoster(&streamEndTrackers, path)
+}
+
+func claimRoster(claims *sync.Map, path string) (func(), bool) {
+	if path == "" {
+		return nil, false
+	}
+	if _, loaded := claims.LoadOrStore(path, struct{}{}); loaded {
+		return nil, false
+	}
+	return func() { claims.Delete(path) }, true
+}
+
+// siteServers records each running site so a live-status change handled by
+// one site reaches the webhooks, announcements and push followers of every
+// site showing the streamer, including sites that share its roster file.
+var siteServers sync.Map
+
+// registerSite adds srv to siteServers. The returned func removes it when the
+// site closes.
+func registerSite(srv *server) func() {
+	siteServers.Store(srv.siteKey, srv)
+	return func() { siteServers.CompareAndDelete(srv.siteKey, srv) }
+}
+
+// emitRosterEvent sends a live-status event to every running site that reads
+// store and lists the streamer.
+func emitRosterEvent(store *streamers.Store, event string, record streamers.Record, platform string) {
+	if store == nil {
+		return
+	}
+	path := filepath.Clean(store.Path())
+	siteServers.Range(func(_, value any) bool {
+		srv := value.(*server)
+		if srv.streamersStore == nil || filepath.Clean(srv.streamersStore.Path()) != path {
+			return true
+		}
+		roster := config.RosterSiteKey(srv.siteKey)
+		if !record.VisibleOn(roster) {
+			return true
+		}
+		local := record.ForSite(roster)
+		srv.emitWebhook(event, local, platform)
+		srv.announce(event, local, platform)
+		srv.pushLive(event, local, platform)
+		srv.emailLive(event, local, platform)
+		return true
+	})
+}
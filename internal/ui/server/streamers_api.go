@@ -1,7 +1,9 @@
 package server
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -9,26 +11,35 @@ import (
 )
 
 type streamersWatchOptions struct {
-	FilePath     string
+	FilePath string
+	// PollInterval is how often the file is checked for edits made outside
+	// this process.
 	PollInterval time.Duration
 	SiteKey      string
+	// Feed, when set, adds typed roster events with Last-Event-ID replay.
+	Feed *rosterFeed
+	// Changed returns a channel closed on the next in-process store write.
+	Changed func() <-chan struct{}
+	// MaxClients caps concurrent watch connections for the site.
+	MaxClients int
+	Heartbeat  time.Duration
 }
 
-const defaultWatchPollInterval = 2 * time.Second
-
-func streamersWatchHandler(opts streamersWatchOptions) http.HandlerFunc {
-	interval := opts.PollInterval
-	if interval <= 0 {
-		interval = defaultWatchPollInterval
-	}
-
+// streamersWatchHandler streams roster changes from a shared broadcaster as
+// server-sent events. Every connection receives an unnamed
+// "data: <mtime millis>" message whenever the roster file changes, which
+// older clients use to reload. With a feed it also sends named streamer.*
+// events carrying the public projection; clients can narrow them with
+// ?site=, ?streamer=id[,id] and ?platform= and resume with Last-Event-ID (or
+// ?lastEventId=). Idle connections get a heartbeat comment.
+func streamersWatchHandler(b *rosterBroadcaster) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			w.Header().Set("Allow", http.MethodGet)
 			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 			return
 		}
-		if opts.FilePath == "" {
+		if b == nil || b.path == "" {
 			http.Error(w, "streamers path not configured", http.StatusInternalServerError)
 			return
 		}
@@ -38,46 +49,80 @@ func streamersWatchHandler(opts streamersWatchOptions) http.HandlerFunc {
 			return
 		}
 
+		query := r.URL.Query()
+		filter := parseRosterEventFilter(query.Get("site"), query["streamer"], query["platform"])
+		lastID, resume := parseLastEventID(r.Header.Get("Last-Event-ID"))
+		if !resume {
+			lastID, resume = parseLastEventID(query.Get("lastEventId"))
+		}
+		sub, initial, err := b.subscribe(lastID, resume)
+		if err != nil {
+			w.Header().Set("Retry-After", "30")
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		defer b.unsubscribe(sub)
+
 		w.Header().Set("Content-Type", "text/event-stream")
 		w.Header().Set("Cache-Control", "no-cache")
 		w.Header().Set("Connection", "keep-alive")
 		w.WriteHeader(http.StatusOK)
 
-		lastMod, _ := fileModTime(opts.FilePath)
-		writeWatchMessage(w, flusher, lastMod)
+		rc := http.NewResponseController(w)
+		write := func(frame []byte) bool {
+			if len(frame) == 0 {
+				return true
+			}
+			_ = rc.SetWriteDeadline(time.Now().Add(watchWriteTimeout))
+			if _, err := w.Write(frame); err != nil {
+				return false
+			}
+			flusher.Flush()
+			return true
+		}
+		encode := func(update watchUpdate) []byte {
+			var frame bytes.Buffer
+			writeSSEUpdate(&frame, update, filter)
+			return frame.Bytes()
+		}
+		// The first message always carries the timestamp, even before the
+		// roster file exists, so legacy clients have a baseline.
+		first := initial
+		first.ModTime = time.Time{}
+		var opening bytes.Buffer
+		writeSSEUpdate(&opening, first, filter)
+		writeWatchMessage(&opening, initial.ID, initial.ModTime)
+		if !write(opening.Bytes()) {
+			return
+		}
 
-		ticker := time.NewTicker(interval)
-		defer ticker.Stop()
+		heartbeat := time.NewTicker(b.heartbeat)
+		defer heartbeat.Stop()
 
 		for {
 			select {
 			case <-r.Context().Done():
 				return
-			case <-ticker.C:
-				mod, err := fileModTime(opts.FilePath)
-				if err != nil {
-					continue
+			case <-sub.evicted:
+				return
+			case update := <-sub.updates:
+				if !write(encode(update)) {
+					return
 				}
-				if mod.After(lastMod) {
-					lastMod = mod
-					writeWatchMessage(w, flusher, mod)
+			case <-heartbeat.C:
+				if !write([]byte(": heartbeat\n\n")) {
+					return
 				}
 			}
 		}
 	}
 }
 
-func (s *server) serveStreamersJSON(w http.ResponseWriter, r *http.Request) {
-	if s.streamersStore == nil {
-		http.Error(w, "streamers store unavailable", http.StatusInternalServerError)
-		return
+func writeWatchMessage(w io.Writer, id uint64, ts time.Time) {
+	if id > 0 {
+		fmt.Fprintf(w, "id: %d\n", id)
 	}
-	http.ServeFile(w, r, s.streamersStore.Path())
-}
-
-func writeWatchMessage(w http.ResponseWriter, flusher http.Flusher, ts time.Time) {
 	fmt.Fprintf(w, "data: %d\n\n", ts.UnixMilli())
-	flusher.Flush()
 }
 
 func mapStoreStreamerRecords(records []streamers.Record) []streamers.Streamer {
@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	adminservice "github.com/Its-donkey/Sharpen-live/internal/alert/admin/service"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/mail"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/submissions"
+)
+
+const (
+	// submissionDigestEvery is the least time between admin digests.
+	submissionDigestEvery = time.Hour
+	// submissionDigestPoll is how often the digest runner looks for new
+	// submissions.
+	submissionDigestPoll = 5 * time.Minute
+)
+
+// submissionEmailData is passed to the templates under templates/emails/.
+type submissionEmailData struct {
+	Site        string
+	SiteURL     string
+	Submission  submissions.Submission
+	StreamerURL string
+	Reason      string
+	// Submissions and AdminURL are set for the admin digest.
+	Submissions []submissions.Submission
+	AdminURL    string
+}
+
+// submissionDigestState is persisted so restarts do not resend a digest.
+type submissionDigestState struct {
+	LastDigest time.Time `json:"lastDigest"`
+}
+
+func (s *server) submissionEmailData(sub submissions.Submission) submissionEmailData {
+	return submissionEmailData{
+		Site:       s.siteDisplayName(),
+		SiteURL:    s.absoluteURL(nil, "/"),
+		Submission: sub,
+		AdminURL:   s.absoluteURL(nil, "/admin"),
+	}
+}
+
+// renderEmail executes the subject and body of the named email template.
+func (s *server) renderEmail(name string, data any) (subject, body string, err error) {
+	tmpl := s.emailTemplates[name]
+	if tmpl == nil {
+		return "", "", fmt.Errorf("email template %s not loaded", name)
+	}
+	var b strings.Builder
+	if err := tmpl.ExecuteTemplate(&b, "subject", data); err != nil {
+		return "", "", err
+	}
+	subject = strings.TrimSpace(b.String())
+	b.Reset()
+	if err := tmpl.ExecuteTemplate(&b, "body", data); err != nil {
+		return "", "", err
+	}
+	return subject, strings.TrimSpace(b.String()) + "\n", nil
+}
+
+// sendTemplateEmail renders the named template and mails it to to.
+func (s *server) sendTemplateEmail(ctx context.Context, to, name string, data any) error {
+	if s.mailer == nil {
+		return errors.New("mail is not configured")
+	}
+	subject, body, err := s.renderEmail(name, data)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, mail.Message{To: to, Subject: subject, Text: body})
+}
+
+// sendSubmitterEmail mails the submitter in the background, if they left an
+// address.
+func (s *server) sendSubmitterEmail(name string, data submissionEmailData) {
+	if s.mailer == nil || data.Submission.ContactEmail == "" {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), emailTimeout)
+		defer cancel()
+		if err := s.sendTemplateEmail(ctx, data.Submission.ContactEmail, name, data); err != nil {
+			s.logger.Warn("email", "Failed to email submitter", map[string]any{
+				"submission": data.Submission.ID,
+				"template":   name,
+				"error":      err.Error(),
+			})
+		}
+	}()
+}
+
+// notifySubmissionReceived confirms a new submission to its submitter.
+func (s *server) notifySubmissionReceived(r *http.Request, sub submissions.Submission) {
+	data := s.submissionEmailData(sub)
+	data.SiteURL = s.absoluteURL(r, "/")
+	s.sendSubmitterEmail("submission_received", data)
+}
+
+// notifySubmissionDecision tells the submitter whether the submission was
+// approved or rejected.
+func (s *server) notifySubmissionDecision(result adminservice.ActionResult) {
+	data := s.submissionEmailData(result.Submission)
+	switch result.Status {
+	case adminservice.ActionApprove:
+		// A merged approval links to the shared record, not the submitted alias.
+		record := streamers.Record{Streamer: streamers.Streamer{Alias: result.Submission.Alias}}
+		if result.Record != nil {
+			record = *result.Record
+		}
+		data.StreamerURL = s.absoluteURL(nil, feedStreamerPath(record))
+		s.sendSubmitterEmail("submission_approved", data)
+	case adminservice.ActionReject:
+		data.Reason = result.Reason
+		s.sendSubmitterEmail("submission_rejected", data)
+	}
+}
+
+// submissionDigestEnabled reports whether admins get new-submission digests.
+func (s *server) submissionDigestEnabled() bool {
+	return s.mailer != nil && s.submissionsStore != nil && s.submissionDigestPath != "" && strings.TrimSpace(s.adminEmail) != ""
+}
+
+// runSubmissionDigest sends admin digests until ctx is cancelled.
+func (s *server) runSubmissionDigest(ctx context.Context) {
+	ticker := time.NewTicker(submissionDigestPoll)
+	defer ticker.Stop()
+	for {
+		if err := s.sendSubmissionDigest(ctx, time.Now()); err != nil {
+			s.logger.Warn("email", "Failed to send submission digest", map[string]any{"error": err.Error()})
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendSubmissionDigest emails the admin the pending submissions received
+// since the last digest, at most once per submissionDigestEvery. Nothing is
+// sent, and the clock is not reset, while there is nothing new.
+func (s *server) sendSubmissionDigest(ctx context.Context, now time.Time) error {
+	state, err := readSubmissionDigestState(s.submissionDigestPath)
+	if err != nil {
+		return err
+	}
+	if now.Sub(state.LastDigest) < submissionDigestEvery {
+		return nil
+	}
+	pending, err := s.submissionsStore.List()
+	if err != nil {
+		return err
+	}
+	var fresh []submissions.Submission
+	for _, sub := range pending {
+		if sub.SubmittedAt.After(state.LastDigest) {
+			fresh = append(fresh, sub)
+		}
+	}
+	if len(fresh) == 0 {
+		return nil
+	}
+	data := s.submissionEmailData(submissions.Submission{})
+	data.Submissions = fresh
+	sendCtx, cancel := context.WithTimeout(ctx, emailTimeout)
+	defer cancel()
+	if err := s.sendTemplateEmail(sendCtx, strings.TrimSpace(s.adminEmail), "submission_digest", data); err != nil {
+		return err
+	}
+	return writeSubmissionDigestState(s.submissionDigestPath, submissionDigestState{LastDigest: now.UTC()})
+}
+
+func readSubmissionDigestState(path string) (submissionDigestState, error) {
+	var state submissionDigestState
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return state, nil
+		}
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("decode submission digest state: %w", err)
+	}
+	return state, nil
+}
+
+func writeSubmissionDigestState(path string, state submissionDigestState) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create submission digest dir: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	adminauth "github.com/Its-donkey/Sharpen-live/internal/alert/admin/auth"
+	adminservice "github.com/Its-donkey/Sharpen-live/internal/alert/admin/service"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/mail"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/mail/mailtest"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/submissions"
+)
+
+// newSubmissionEmailServer returns a test server mailing through a local
+// SMTP stand-in with the default-site email templates.
+func newSubmissionEmailServer(t *testing.T) (*server, *mailtest.Server) {
+	t.Helper()
+	smtp := mailtest.NewServer(t)
+	mailer, err := mail.NewSMTP(mail.SMTPOptions{Addr: smtp.Addr, From: "alerts@sharpen.live"})
+	if err != nil {
+		t.Fatalf("new smtp: %v", err)
+	}
+	templates, err := loadEmailTemplates(nil, "en", filepath.Join("..", "..", "..", "ui", "sites", "default-site", "templates"))
+	if err != nil {
+		t.Fatalf("load email templates: %v", err)
+	}
+	srv := newTestServer()
+	srv.mailer = mailer
+	srv.emailTemplates = templates
+	return srv, smtp
+}
+
+func TestSubmissionEmailsReachSubmitter(t *testing.T) {
+	srv, smtp := newSubmissionEmailServer(t)
+	sub := submissions.Submission{ID: "sub_1", Alias: "Night Owl", ContactEmail: "owl@example.com"}
+
+	srv.notifySubmissionReceived(httptest.NewRequest(http.MethodPost, "/submit", nil), submissions.Submission{ID: "sub_0", Alias: "Silent"})
+	srv.notifySubmissionReceived(httptest.NewRequest(http.MethodPost, "/submit", nil), sub)
+	messages := smtp.Wait(1, 5*time.Second)
+	if len(messages) != 1 || messages[0].To[0] != "owl@example.com" {
+		t.Fatalf("expected one confirmation to the contact address, got %+v", messages)
+	}
+	if subject := messages[0].Header.Get("Subject"); subject != "We received your submission of Night Owl" {
+		t.Fatalf("unexpected subject %q", subject)
+	}
+
+	srv.adminManager = &stubAdminManager{valid: true, token: adminauth.Token{Value: "tok"}}
+	srv.adminSubmissions = &stubAdminSubmissions{submission: sub}
+	form := url.Values{"id": {"sub_1"}, "action": {"reject"}, "reason": {"Not a streamer"}}
+	req := httptest.NewRequest(http.MethodPost, "/admin/submissions", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(&http.Cookie{Name: adminCookieName, Value: "tok"})
+	srv.handleAdminSubmission(httptest.NewRecorder(), req)
+
+	messages = smtp.Wait(2, 5*time.Second)
+	if len(messages) != 2 {
+		t.Fatalf("expected a decision email, got %+v", messages)
+	}
+	decision := messages[1]
+	if decision.Header.Get("Subject") != "Your submission of Night Owl was not approved" || !strings.Contains(decision.Body, "Reason: Not a streamer") {
+		t.Fatalf("unexpected rejection email %q %q", decision.Header.Get("Subject"), decision.Body)
+	}
+}
+
+func TestApprovalEmailLinksToApprovedRecord(t *testing.T) {
+	srv, smtp := newSubmissionEmailServer(t)
+	srv.notifySubmissionDecision(adminservice.ActionResult{
+		Status:     adminservice.ActionApprove,
+		Submission: submissions.Submission{ID: "sub_1", Alias: "Night Owl Alt", ContactEmail: "owl@example.com"},
+		Record:     &streamers.Record{Streamer: streamers.Streamer{ID: "owl", Alias: "NightOwl"}},
+	})
+	messages := smtp.Wait(1, 5*time.Second)
+	if len(messages) != 1 {
+		t.Fatalf("expected an approval email, got %+v", messages)
+	}
+	if body := messages[0].Body; !strings.Contains(body, "/streamers/NightOwl") || strings.Contains(body, "Night%20Owl%20Alt") {
+		t.Fatalf("expected a link to the shared record, got %q", body)
+	}
+}
+
+func TestSubmissionDigestBatchesNewSubmissions(t *testing.T) {
+	srv, smtp := newSubmissionEmailServer(t)
+	dir := t.TempDir()
+	store := submissions.NewStore(filepath.Join(dir, "submissions.json"))
+	srv.submissionsStore = store
+	srv.submissionDigestPath = filepath.Join(dir, "submission_digest.json")
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, alias := range []string{"Alpha", "Bravo"} {
+		if _, err := store.Append(submissions.Submission{Alias: alias, SubmittedAt: start.Add(-time.Minute)}); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	ctx := context.Background()
+
+	if err := srv.sendSubmissionDigest(ctx, start); err != nil {
+		t.Fatalf("digest: %v", err)
+	}
+	messages := smtp.Wait(1, 5*time.Second)
+	if len(messages) != 1 || messages[0].To[0] != "admin@example.com" {
+		t.Fatalf("expected a digest to the admin, got %+v", messages)
+	}
+	if subject := messages[0].Header.Get("Subject"); subject != "2 new submissions on Sharpen.Live" {
+		t.Fatalf("unexpected subject %q", subject)
+	}
+	if body := messages[0].Body; !strings.Contains(body, "- Alpha") || !strings.Contains(body, "https://example.com/admin") {
+		t.Fatalf("unexpected digest body %q", body)
+	}
+
+	if _, err := store.Append(submissions.Submission{Alias: "Charlie", SubmittedAt: start.Add(time.Minute)}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if err := srv.sendSubmissionDigest(ctx, start.Add(30*time.Minute)); err != nil {
+		t.Fatalf("digest: %v", err)
+	}
+	if got := len(smtp.Messages()); got != 1 {
+		t.Fatalf("digests must be at least %s apart, got %d emails", submissionDigestEvery, got)
+	}
+	if err := srv.sendSubmissionDigest(ctx, start.Add(submissionDigestEvery)); err != nil {
+		t.Fatalf("digest: %v", err)
+	}
+	messages = smtp.Wait(2, 5*time.Second)
+	if len(messages) != 2 || messages[1].Header.Get("Subject") != "1 new submission on Sharpen.Live" || strings.Contains(messages[1].Body, "Alpha") {
+		t.Fatalf("expected only the new submission in the next digest, got %+v", messages)
+	}
+}
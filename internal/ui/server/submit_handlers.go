@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	streamersvc "github.com/Its-donkey/Sharpen-live/internal/alert/streamers/service"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/submissions"
 	"github.com/Its-donkey/Sharpen-live/internal/ui/forms"
 	"github.com/Its-donkey/Sharpen-live/internal/ui/model"
 )
@@ -52,7 +53,7 @@ func ensureSubmitDefaults(state *model.SubmitFormState) {
 }
 
 func hasSubmitErrors(errs model.SubmitFormErrors) bool {
-	return errs.Name || errs.Description || errs.Languages || len(errs.Platforms) > 0
+	return errs.Name || errs.Description || errs.Languages || errs.ContactEmail || len(errs.Platforms) > 0
 }
 
 func parseSubmitForm(r *http.Request) (model.SubmitFormState, []string, error) {
@@ -62,8 +63,9 @@ func parseSubmitForm(r *http.Request) (model.SubmitFormState, []string, error) {
 
 	langs := collectLanguages(r.Form)
 	state := model.SubmitFormState{
-		Name:        strings.TrimSpace(r.Form.Get("name")),
-		Description: strings.TrimSpace(r.Form.Get("description")),
+		Name:         strings.TrimSpace(r.Form.Get("name")),
+		Description:  strings.TrimSpace(r.Form.Get("description")),
+		ContactEmail: strings.TrimSpace(r.Form.Get("contact_email")),
 	}
 	state.Languages = append(state.Languages, langs...)
 
@@ -193,33 +195,34 @@ func removePlatformRow(platforms []model.PlatformFormRow, id string) []model.Pla
 	return out
 }
 
-func submitStreamer(ctx context.Context, streamerSvc StreamerService, form model.SubmitFormState) (string, error) {
+func submitStreamer(ctx context.Context, streamerSvc StreamerService, form model.SubmitFormState) (submissions.Submission, string, error) {
 	if streamerSvc == nil {
-		return "", errors.New("streamer service unavailable")
+		return submissions.Submission{}, "", errors.New("streamer service unavailable")
 	}
 
 	// Build the platforms map from form data
 	platformsMap := forms.BuildPlatformsMap(form.Platforms)
 
 	req := streamersvc.CreateRequest{
-		Alias:       strings.TrimSpace(form.Name),
-		Description: forms.BuildStreamerDescription(form.Description, form.Platforms),
-		Languages:   append([]string(nil), form.Languages...),
-		PlatformURL: forms.FirstPlatformURL(form.Platforms),
-		Platforms:   platformsMap,
+		Alias:        strings.TrimSpace(form.Name),
+		Description:  forms.BuildStreamerDescription(form.Description, form.Platforms),
+		Languages:    append([]string(nil), form.Languages...),
+		PlatformURL:  forms.FirstPlatformURL(form.Platforms),
+		Platforms:    platformsMap,
+		ContactEmail: strings.TrimSpace(form.ContactEmail),
 	}
 	result, err := streamerSvc.Create(ctx, req)
 	if err != nil {
-		return "", err
+		return submissions.Submission{}, "", err
 	}
 	alias := strings.TrimSpace(result.Submission.Alias)
 	id := strings.TrimSpace(result.Submission.ID)
 	switch {
 	case alias != "" && id != "":
-		return fmt.Sprintf("%s queued with submission %s.", alias, id), nil
+		return result.Submission, fmt.Sprintf("%s queued with submission %s.", alias, id), nil
 	case alias != "":
-		return fmt.Sprintf("%s submitted for review.", alias), nil
+		return result.Submission, fmt.Sprintf("%s submitted for review.", alias), nil
 	default:
-		return "Streamer submitted successfully.", nil
+		return result.Submission, "Streamer submitted successfully.", nil
 	}
 }
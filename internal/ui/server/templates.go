@@ -4,52 +4,113 @@ package server
 import (
 	"fmt"
 	"html/template"
-	"os"
 	"path/filepath"
 	"strings"
+	texttemplate "text/template"
 
+	"github.com/Its-donkey/Sharpen-live/internal/alert/config"
 	"github.com/Its-donkey/Sharpen-live/internal/ui/forms"
+	"github.com/Its-donkey/Sharpen-live/internal/ui/i18n"
 )
 
-// loadTemplates loads and wires all HTML templates used by the UI server.
-// It returns a map keyed by logical template name (e.g. "home", "streamer").
-func loadTemplates(dir string) (map[string]*template.Template, error) {
+// TemplateSource records the theme directory a template file resolved from.
+type TemplateSource struct {
+	File      string
+	Path      string
+	Inherited bool
+	Embedded  bool
+}
+
+// loadTemplates loads and wires all HTML templates used by the UI server in
+// the source language. Each file is taken from the first theme directory in
+// dirs that contains it, so a site only needs to carry the templates it
+// overrides. It returns a map keyed by logical template name (e.g. "home",
+// "streamer") along with where each file was found.
+func loadTemplates(dirs ...string) (map[string]*template.Template, []TemplateSource, error) {
+	catalog, err := loadCatalog(dirs...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return loadLocaleTemplates(catalog, i18n.SourceLocale, dirs...)
+}
+
+// loadLocaleTemplates loads the templates with the translation functions
+// bound to locale.
+func loadLocaleTemplates(catalog *i18n.Catalog, locale string, dirs ...string) (map[string]*template.Template, []TemplateSource, error) {
 	funcs := template.FuncMap{
 		"join":            strings.Join,
 		"contains":        forms.ContainsString,
 		"displayLanguage": forms.DisplayLanguage,
 		"statusClass":     statusClass,
-		"statusLabel":     statusLabel,
-		"lower":           strings.ToLower,
-		"formatDays":      formatDays,
+		"statusLabel": func(status string) string {
+			return catalog.Translate(locale, statusLabel(status))
+		},
+		"lower":        strings.ToLower,
+		"formatDays":   formatDays,
+		"languageSlug": languageSlug,
+		"t": func(key string, args ...any) string {
+			return catalog.Translate(locale, key, args...)
+		},
+		"tn": func(one, other string, n int, args ...any) string {
+			return catalog.Plural(locale, one, other, n, args...)
+		},
 	}
 
-	base := filepath.Join(dir, "base.tmpl")
-	home := filepath.Join(dir, "home.tmpl")
-	streamer := filepath.Join(dir, "streamer.tmpl")
-	submit := filepath.Join(dir, "submit_form.tmpl")
-	admin := filepath.Join(dir, "admin.tmpl")
-	logs := filepath.Join(dir, "logs.tmpl")
-	config := filepath.Join(dir, "config.tmpl")
+	var sources []TemplateSource
+	resolve := func(name string) (string, error) {
+		for i, dir := range dirs {
+			path := filepath.Join(dir, name)
+			if themeFiles.exists(path) {
+				sources = append(sources, TemplateSource{File: name, Path: path, Inherited: i > 0, Embedded: themeFiles.isEmbedded(path)})
+				return path, nil
+			}
+		}
+		return "", fmt.Errorf("template %s not found in %s", name, strings.Join(dirs, ", "))
+	}
+	files := map[string]string{}
+	for _, name := range []string{"base.tmpl", "home.tmpl", "submit_form.tmpl", "streamer.tmpl", "admin.tmpl", "logs.tmpl", "widget.tmpl", "listing.tmpl", "email.tmpl"} {
+		path, err := resolve(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		files[name] = path
+	}
+	base := files["base.tmpl"]
 
-	homeTmpl, err := template.New("home").Funcs(funcs).ParseFiles(base, home, submit)
+	homeTmpl, err := parseThemeFiles("home", funcs, base, files["home.tmpl"], files["submit_form.tmpl"])
 	if err != nil {
-		return nil, fmt.Errorf("parse home templates: %w", err)
+		return nil, nil, fmt.Errorf("parse home templates: %w", err)
 	}
 
-	streamerTmpl, err := template.New("streamer").Funcs(funcs).ParseFiles(base, streamer)
+	streamerTmpl, err := parseThemeFiles("streamer", funcs, base, files["streamer.tmpl"])
 	if err != nil {
-		return nil, fmt.Errorf("parse streamer templates: %w", err)
+		return nil, nil, fmt.Errorf("parse streamer templates: %w", err)
 	}
 
-	adminTmpl, err := template.New("admin").Funcs(funcs).ParseFiles(base, admin)
+	adminTmpl, err := parseThemeFiles("admin", funcs, base, files["admin.tmpl"])
 	if err != nil {
-		return nil, fmt.Errorf("parse admin templates: %w", err)
+		return nil, nil, fmt.Errorf("parse admin templates: %w", err)
 	}
 
-	logsTmpl, err := template.New("logs").Funcs(funcs).ParseFiles(base, logs)
+	logsTmpl, err := parseThemeFiles("logs", funcs, base, files["logs.tmpl"])
 	if err != nil {
-		return nil, fmt.Errorf("parse logs templates: %w", err)
+		return nil, nil, fmt.Errorf("parse logs templates: %w", err)
+	}
+
+	listingTmpl, err := parseThemeFiles("listing", funcs, base, files["listing.tmpl"])
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse listing templates: %w", err)
+	}
+
+	emailTmpl, err := parseThemeFiles("email", funcs, base, files["email.tmpl"])
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse email templates: %w", err)
+	}
+
+	// The widget is framed by other sites, so it stands alone without base.
+	widgetTmpl, err := parseThemeFiles("widget", funcs, files["widget.tmpl"])
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse widget templates: %w", err)
 	}
 
 	templates := map[string]*template.Template{
@@ -57,20 +118,125 @@ func loadTemplates(dir string) (map[string]*template.Template, error) {
 		"streamer": streamerTmpl,
 		"admin":    adminTmpl,
 		"logs":     logsTmpl,
+		"listing":  listingTmpl,
+		"widget":   widgetTmpl,
+		"email":    emailTmpl,
 	}
 
-	// Config template is optional - only default-site (parent/control room) has it
-	if _, err := os.Stat(config); err == nil {
-		configTmpl, err := template.New("config").Funcs(funcs).ParseFiles(base, config)
-		if err != nil {
-			return nil, fmt.Errorf("parse config templates: %w", err)
+	// Config template is optional and never inherited - only default-site
+	// (parent/control room) has it
+	if len(dirs) > 0 {
+		configPath := filepath.Join(dirs[0], "config.tmpl")
+		if themeFiles.exists(configPath) {
+			configTmpl, err := parseThemeFiles("config", funcs, base, configPath)
+			if err != nil {
+				return nil, nil, fmt.Errorf("parse config templates: %w", err)
+			}
+			templates["config"] = configTmpl
+			sources = append(sources, TemplateSource{File: "config.tmpl", Path: configPath, Embedded: themeFiles.isEmbedded(configPath)})
 		}
-		templates["config"] = configTmpl
 	}
 
+	return templates, sources, nil
+}
+
+// emailTemplateNames are the plain-text emails under templates/emails/. Each
+// file defines a "subject" and a "body" template.
+var emailTemplateNames = []string{"submission_received", "submission_approved", "submission_rejected", "submission_digest"}
+
+// loadEmailTemplates parses the email templates with t bound to locale. Like
+// the page templates, each file comes from the first theme directory in dirs
+// that has it, so a site can reword a single email.
+func loadEmailTemplates(catalog *i18n.Catalog, locale string, dirs ...string) (map[string]*texttemplate.Template, error) {
+	funcs := texttemplate.FuncMap{
+		"join": strings.Join,
+		"t": func(key string, args ...any) string {
+			return catalog.Translate(locale, key, args...)
+		},
+		"tn": func(one, other string, n int, args ...any) string {
+			return catalog.Plural(locale, one, other, n, args...)
+		},
+	}
+	templates := make(map[string]*texttemplate.Template, len(emailTemplateNames))
+	for _, name := range emailTemplateNames {
+		file := filepath.Join("emails", name+".tmpl")
+		var data []byte
+		for _, dir := range dirs {
+			if path := filepath.Join(dir, file); themeFiles.exists(path) {
+				var err error
+				if data, err = themeFiles.readFile(path); err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
+		if data == nil {
+			return nil, fmt.Errorf("template %s not found in %s", file, strings.Join(dirs, ", "))
+		}
+		tmpl, err := texttemplate.New(name).Funcs(funcs).Parse(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", file, err)
+		}
+		if tmpl.Lookup("subject") == nil || tmpl.Lookup("body") == nil {
+			return nil, fmt.Errorf("%s must define subject and body", file)
+		}
+		templates[name] = tmpl
+	}
 	return templates, nil
 }
 
+// parseThemeFiles mirrors template.ParseFiles, reading each file through the
+// layered theme filesystem.
+func parseThemeFiles(name string, funcs template.FuncMap, files ...string) (*template.Template, error) {
+	tmpl := template.New(name).Funcs(funcs)
+	for _, file := range files {
+		data, err := themeFiles.readFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tmpl.New(filepath.Base(file)).Parse(string(data)); err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
+
+// themeDirs resolves the theme chain for a site into absolute template and
+// asset directories, starting with the site's own (possibly overridden)
+// directories and skipping duplicates.
+func themeDirs(templatesDir, assetsDir string, chain []config.AppConfig) (templates, assets []string, err error) {
+	add := func(list []string, dir string) ([]string, error) {
+		if strings.TrimSpace(dir) == "" {
+			return list, nil
+		}
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return list, err
+		}
+		for _, existing := range list {
+			if existing == abs {
+				return list, nil
+			}
+		}
+		return append(list, abs), nil
+	}
+	if templates, err = add(templates, templatesDir); err != nil {
+		return nil, nil, fmt.Errorf("resolve templates dir: %w", err)
+	}
+	if assets, err = add(assets, assetsDir); err != nil {
+		return nil, nil, fmt.Errorf("resolve assets dir: %w", err)
+	}
+	for _, app := range chain {
+		if templates, err = add(templates, app.Templates); err != nil {
+			return nil, nil, fmt.Errorf("resolve theme templates dir: %w", err)
+		}
+		if assets, err = add(assets, app.Assets); err != nil {
+			return nil, nil, fmt.Errorf("resolve theme assets dir: %w", err)
+		}
+	}
+	return templates, assets, nil
+}
+
 // formatDays converts seconds to days for display
 func formatDays(seconds int) string {
 	if seconds == 0 {
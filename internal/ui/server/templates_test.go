@@ -0,0 +1,120 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/submissions"
+)
+
+func TestLoadTemplatesInheritsMissingFiles(t *testing.T) {
+	sites := filepath.Join("..", "..", "..", "ui", "sites")
+	child := t.TempDir()
+	base, err := os.ReadFile(filepath.Join(sites, "synth-wave", "templates", "base.tmpl"))
+	if err != nil {
+		t.Fatalf("read base template: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(child, "base.tmpl"), base, 0o644); err != nil {
+		t.Fatalf("write base template: %v", err)
+	}
+	parent := filepath.Join(sites, "sharpen-live", "templates")
+	fallback := filepath.Join(sites, "default-site", "templates")
+
+	templates, sources, err := loadTemplates(child, parent, fallback)
+	if err != nil {
+		t.Fatalf("load templates: %v", err)
+	}
+	for _, name := range []string{"home", "streamer", "admin", "logs", "listing", "widget", "email"} {
+		if templates[name] == nil {
+			t.Fatalf("expected %s template", name)
+		}
+	}
+	if _, ok := templates["config"]; ok {
+		t.Fatalf("config template should not be inherited")
+	}
+
+	got := map[string]TemplateSource{}
+	for _, source := range sources {
+		got[source.File] = source
+	}
+	tests := []struct {
+		file      string
+		dir       string
+		inherited bool
+	}{
+		{file: "base.tmpl", dir: child},
+		{file: "home.tmpl", dir: parent, inherited: true},
+		{file: "admin.tmpl", dir: parent, inherited: true},
+		{file: "widget.tmpl", dir: fallback, inherited: true},
+	}
+	for _, tt := range tests {
+		source, ok := got[tt.file]
+		if !ok {
+			t.Fatalf("missing source for %s", tt.file)
+		}
+		if source.Path != filepath.Join(tt.dir, tt.file) || source.Inherited != tt.inherited {
+			t.Fatalf("%s: expected %s (inherited=%v), got %+v", tt.file, tt.dir, tt.inherited, source)
+		}
+	}
+}
+
+func TestLoadTemplatesReportsMissingFile(t *testing.T) {
+	if _, _, err := loadTemplates(t.TempDir()); err == nil {
+		t.Fatalf("expected error when no theme provides the templates")
+	}
+}
+
+func TestLoadEmailTemplatesPrefersSiteOverrides(t *testing.T) {
+	child := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(child, "emails"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	override := `{{define "subject"}}Got {{.Submission.Alias}}{{end}}{{define "body"}}Thanks{{end}}`
+	if err := os.WriteFile(filepath.Join(child, "emails", "submission_received.tmpl"), []byte(override), 0o644); err != nil {
+		t.Fatalf("write override: %v", err)
+	}
+	fallback := filepath.Join("..", "..", "..", "ui", "sites", "default-site", "templates")
+
+	srv := newTestServer()
+	templates, err := loadEmailTemplates(nil, "en", child, fallback)
+	if err != nil {
+		t.Fatalf("load email templates: %v", err)
+	}
+	srv.emailTemplates = templates
+	data := submissionEmailData{Site: "Sharpen.Live", Submission: submissions.Submission{Alias: "Owl"}}
+	if subject, body, err := srv.renderEmail("submission_received", data); err != nil || subject != "Got Owl" || body != "Thanks\n" {
+		t.Fatalf("expected the site override, got %q %q %v", subject, body, err)
+	}
+	if subject, _, err := srv.renderEmail("submission_approved", data); err != nil || subject != "Owl is now listed on Sharpen.Live" {
+		t.Fatalf("expected the default-site email, got %q %v", subject, err)
+	}
+
+	if _, err := loadEmailTemplates(nil, "en", child); err == nil {
+		t.Fatalf("expected an error when a theme chain lacks an email template")
+	}
+}
+
+func TestResolveAssetWalksThemeDirs(t *testing.T) {
+	child := t.TempDir()
+	parent := t.TempDir()
+	for path, body := range map[string]string{
+		filepath.Join(child, "styles.css"):  "child",
+		filepath.Join(parent, "styles.css"): "parent",
+		filepath.Join(parent, "submit.js"):  "parent",
+	} {
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+	srv := newTestServer()
+	srv.assetsDir = child
+	srv.assetDirs = []string{child, parent}
+
+	if got := srv.resolveAsset("styles.css"); got != filepath.Join(child, "styles.css") {
+		t.Fatalf("expected site override, got %s", got)
+	}
+	if got := srv.resolveAsset("submit.js"); got != filepath.Join(parent, "submit.js") {
+		t.Fatalf("expected inherited asset, got %s", got)
+	}
+}
@@ -0,0 +1,332 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Its-donkey/Sharpen-live/ui"
+)
+
+// themeFiles serves theme templates and assets for every site. It starts with
+// the embedded files only; SetThemeDir adds an on-disk override directory.
+var themeFiles = newThemeFS(ui.Files, "")
+
+// errNoThemeDir reports a write to the theme tree without an override
+// directory to hold it.
+var errNoThemeDir = errors.New("no theme override directory configured (start the server with -theme-dir)")
+
+// SetThemeDir layers the theme files in dir over the embedded UI tree. An
+// empty dir serves the embedded files only.
+func SetThemeDir(dir string) error {
+	dir = strings.TrimSpace(dir)
+	if dir != "" {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return fmt.Errorf("theme dir: %w", err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("theme dir %s is not a directory", dir)
+		}
+	}
+	themeFiles = newThemeFS(ui.Files, dir)
+	return nil
+}
+
+// themeFS layers an override directory over the UI tree embedded in the
+// binary. Theme paths are OS paths as configured; those under <workdir>/ui
+// name files in the embedded tree and are looked up in the override
+// directory first, so a deployment only needs to ship the files it
+// customises. Paths outside <workdir>/ui are read from disk as-is.
+type themeFS struct {
+	embedded fs.FS
+	// root is the directory the embedded tree is mounted at.
+	root string
+	// dir holds on-disk overrides for files under root; empty serves the
+	// embedded files only.
+	dir string
+}
+
+func newThemeFS(embedded fs.FS, dir string) themeFS {
+	root, err := filepath.Abs("ui")
+	if err != nil {
+		root = "ui"
+	}
+	if dir != "" {
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+	}
+	return themeFS{embedded: embedded, root: root, dir: dir}
+}
+
+// relName maps an OS path under root onto a slash-separated tree path.
+func (t themeFS) relName(name string) (string, bool) {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(t.root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	rel = filepath.ToSlash(rel)
+	if !fs.ValidPath(rel) {
+		return "", false
+	}
+	return rel, true
+}
+
+// embeddedName maps an OS path onto the embedded tree.
+func (t themeFS) embeddedName(name string) (string, bool) {
+	if t.embedded == nil {
+		return "", false
+	}
+	return t.relName(name)
+}
+
+// diskPath returns where name lives on disk: its override under dir for paths
+// under root, or name itself elsewhere. It reports false when paths under
+// root have no override directory.
+func (t themeFS) diskPath(name string) (string, bool) {
+	rel, ok := t.relName(name)
+	if !ok {
+		return name, true
+	}
+	if t.dir == "" {
+		return "", false
+	}
+	return filepath.Join(t.dir, filepath.FromSlash(rel)), true
+}
+
+// stat returns the on-disk file info for name.
+func (t themeFS) stat(name string) (fs.FileInfo, error) {
+	file, ok := t.diskPath(name)
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return os.Stat(file)
+}
+
+// onDisk reports whether name exists on disk.
+func (t themeFS) onDisk(name string) bool {
+	_, err := t.stat(name)
+	return err == nil
+}
+
+// isEmbedded reports whether name is served from the embedded tree.
+func (t themeFS) isEmbedded(name string) bool {
+	if t.onDisk(name) {
+		return false
+	}
+	embedded, ok := t.embeddedName(name)
+	if !ok {
+		return false
+	}
+	_, err := fs.Stat(t.embedded, embedded)
+	return err == nil
+}
+
+// exists reports whether name is available on disk or embedded.
+func (t themeFS) exists(name string) bool {
+	return t.onDisk(name) || t.isEmbedded(name)
+}
+
+// readFile reads name from disk, falling back to the embedded tree.
+func (t themeFS) readFile(name string) ([]byte, error) {
+	err := fs.ErrNotExist
+	if file, ok := t.diskPath(name); ok {
+		var data []byte
+		data, err = os.ReadFile(file)
+		if err == nil || !errors.Is(err, fs.ErrNotExist) {
+			return data, err
+		}
+	}
+	if embedded, ok := t.embeddedName(name); ok {
+		return fs.ReadFile(t.embedded, embedded)
+	}
+	return nil, err
+}
+
+// serve writes name to the response from disk or the embedded tree.
+func (t themeFS) serve(w http.ResponseWriter, r *http.Request, name string) {
+	if t.onDisk(name) {
+		file, _ := t.diskPath(name)
+		http.ServeFile(w, r, file)
+		return
+	}
+	if embedded, ok := t.embeddedName(name); ok {
+		http.ServeFileFS(w, r, t.embedded, embedded)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// readDir lists dir on disk, or nothing when it has no disk location.
+func (t themeFS) readDir(dir string) []fs.DirEntry {
+	file, ok := t.diskPath(dir)
+	if !ok {
+		return nil
+	}
+	entries, _ := os.ReadDir(file)
+	return entries
+}
+
+// subdirs lists the directories under dir across disk and the embedded tree.
+func (t themeFS) subdirs(dir string) []string {
+	seen := map[string]struct{}{}
+	for _, entry := range t.readDir(dir) {
+		if entry.IsDir() {
+			seen[entry.Name()] = struct{}{}
+		}
+	}
+	if embedded, ok := t.embeddedName(dir); ok {
+		if entries, err := fs.ReadDir(t.embedded, embedded); err == nil {
+			for _, entry := range entries {
+				if entry.IsDir() {
+					seen[entry.Name()] = struct{}{}
+				}
+			}
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// files lists the regular files directly under dir across disk and the
+// embedded tree.
+func (t themeFS) files(dir string) []string {
+	seen := map[string]struct{}{}
+	for _, entry := range t.readDir(dir) {
+		if entry.Type().IsRegular() {
+			seen[entry.Name()] = struct{}{}
+		}
+	}
+	if embedded, ok := t.embeddedName(dir); ok {
+		if entries, err := fs.ReadDir(t.embedded, embedded); err == nil {
+			for _, entry := range entries {
+				if !entry.IsDir() {
+					seen[entry.Name()] = struct{}{}
+				}
+			}
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// removeAll deletes the on-disk copy of dir.
+func (t themeFS) removeAll(dir string) error {
+	file, ok := t.diskPath(dir)
+	if !ok {
+		return nil
+	}
+	return os.RemoveAll(file)
+}
+
+// copyDir copies the theme under src into dst on disk. Files on disk win over
+// embedded copies of the same name.
+func (t themeFS) copyDir(src, dst string) error {
+	dst, ok := t.diskPath(dst)
+	if !ok {
+		return errNoThemeDir
+	}
+	if t.onDisk(src) {
+		src, _ := t.diskPath(src)
+		err := filepath.WalkDir(src, func(name string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(src, name)
+			if err != nil {
+				return err
+			}
+			out := filepath.Join(dst, rel)
+			if entry.IsDir() {
+				return os.MkdirAll(out, 0o755)
+			}
+			if !entry.Type().IsRegular() {
+				return nil
+			}
+			data, err := os.ReadFile(name)
+			if err != nil {
+				return err
+			}
+			return writeNewFile(out, data)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	embedded, ok := t.embeddedName(src)
+	if !ok {
+		return nil
+	}
+	if _, err := fs.Stat(t.embedded, embedded); err != nil {
+		if t.onDisk(src) {
+			return nil
+		}
+		return err
+	}
+	return fs.WalkDir(t.embedded, embedded, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(name, embedded), "/")
+		out := filepath.Join(dst, filepath.FromSlash(rel))
+		if entry.IsDir() {
+			return os.MkdirAll(out, 0o755)
+		}
+		data, err := fs.ReadFile(t.embedded, name)
+		if err != nil {
+			return err
+		}
+		if err := writeNewFile(out, data); err != nil && !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+		return nil
+	})
+}
+
+// writeNewFile creates name with data, failing if it already exists.
+func writeNewFile(name string, data []byte) error {
+	out, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := out.Write(data); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// handleStatic serves the shared ui/static files.
+func (s *server) handleStatic(w http.ResponseWriter, r *http.Request) {
+	name := path.Clean("/" + strings.TrimPrefix(r.URL.Path, "/static/"))
+	if name == "/" {
+		http.NotFound(w, r)
+		return
+	}
+	file := filepath.Join(themeFiles.root, "static", filepath.FromSlash(name))
+	if !themeFiles.exists(file) {
+		http.NotFound(w, r)
+		return
+	}
+	setAssetCacheHeaders(w, r, file)
+	themeFiles.serve(w, r, file)
+}
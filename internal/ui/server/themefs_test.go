@@ -0,0 +1,145 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/Its-donkey/Sharpen-live/ui"
+)
+
+func useThemeFS(t *testing.T, fsys themeFS) {
+	t.Helper()
+	previous := themeFiles
+	themeFiles = fsys
+	t.Cleanup(func() { themeFiles = previous })
+}
+
+func TestThemeFSPrefersDiskOverEmbedded(t *testing.T) {
+	root, overrides := t.TempDir(), t.TempDir()
+	fsys := themeFS{
+		root: root,
+		dir:  overrides,
+		embedded: fstest.MapFS{
+			"sites/neon/styles.css": {Data: []byte("embedded")},
+			"sites/neon/submit.js":  {Data: []byte("embedded js")},
+			"sites/glow/styles.css": {Data: []byte("glow")},
+		},
+	}
+	override := filepath.Join(overrides, "sites", "neon", "styles.css")
+	if err := os.MkdirAll(filepath.Dir(override), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(override, []byte("disk"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		file     string
+		want     string
+		embedded bool
+	}{
+		{name: "disk override", file: filepath.Join(root, "sites", "neon", "styles.css"), want: "disk"},
+		{name: "embedded fallback", file: filepath.Join(root, "sites", "neon", "submit.js"), want: "embedded js", embedded: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := fsys.readFile(tt.file)
+			if err != nil || string(data) != tt.want {
+				t.Fatalf("expected %q, got %q (%v)", tt.want, data, err)
+			}
+			if fsys.isEmbedded(tt.file) != tt.embedded {
+				t.Fatalf("expected embedded=%v", tt.embedded)
+			}
+		})
+	}
+	if fsys.exists(filepath.Join(root, "..", "outside.txt")) {
+		t.Fatalf("paths outside the mount must not resolve")
+	}
+	if got := strings.Join(fsys.subdirs(filepath.Join(root, "sites")), ","); got != "glow,neon" {
+		t.Fatalf("expected merged site dirs, got %q", got)
+	}
+
+	if err := fsys.copyDir(filepath.Join(root, "sites", "neon"), filepath.Join(root, "sites", "copy")); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	for name, want := range map[string]string{"styles.css": "disk", "submit.js": "embedded js"} {
+		if data, _ := os.ReadFile(filepath.Join(overrides, "sites", "copy", name)); string(data) != want {
+			t.Fatalf("copied %s: expected %q, got %q", name, want, data)
+		}
+	}
+}
+
+func TestThemeFSWithoutOverrideDirServesEmbeddedOnly(t *testing.T) {
+	root := t.TempDir()
+	fsys := themeFS{root: root, embedded: fstest.MapFS{"sites/neon/styles.css": {Data: []byte("embedded")}}}
+	local := filepath.Join(root, "sites", "neon", "styles.css")
+	if err := os.MkdirAll(filepath.Dir(local), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(local, []byte("disk"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if data, err := fsys.readFile(local); err != nil || string(data) != "embedded" {
+		t.Fatalf("expected the embedded file, got %q (%v)", data, err)
+	}
+	if !fsys.isEmbedded(local) {
+		t.Fatalf("expected the file to be reported as embedded")
+	}
+	if err := fsys.copyDir(filepath.Join(root, "sites", "neon"), filepath.Join(root, "sites", "copy")); !errors.Is(err, errNoThemeDir) {
+		t.Fatalf("expected errNoThemeDir copying without an override dir, got %v", err)
+	}
+	if err := SetThemeDir(filepath.Join(root, "missing")); err == nil {
+		t.Fatalf("expected a missing theme dir to be rejected")
+	}
+}
+
+func TestLoadTemplatesFromEmbeddedThemes(t *testing.T) {
+	root := t.TempDir()
+	useThemeFS(t, themeFS{root: root, embedded: ui.Files})
+
+	templates, sources, err := loadTemplates(filepath.Join(root, "sites", "synth-wave", "templates"), filepath.Join(root, "sites", "default-site", "templates"))
+	if err != nil {
+		t.Fatalf("load embedded templates: %v", err)
+	}
+	if templates["home"] == nil || templates["admin"] == nil {
+		t.Fatalf("expected embedded templates parsed")
+	}
+	for _, source := range sources {
+		// The roster widget, landing and email pages ship with default-site only.
+		inherited := source.File == "widget.tmpl" || source.File == "listing.tmpl" || source.File == "email.tmpl"
+		if !source.Embedded || source.Inherited != inherited {
+			t.Fatalf("expected %s from the embedded site theme (inherited=%v), got %+v", source.File, inherited, source)
+		}
+	}
+}
+
+func TestHandleStaticServesEmbeddedFiles(t *testing.T) {
+	useThemeFS(t, themeFS{root: t.TempDir(), embedded: ui.Files})
+	srv := newTestServer()
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{path: "/static/synthwave-logo-badge.svg", code: http.StatusOK},
+		{path: "/static/missing.svg", code: http.StatusNotFound},
+		{path: "/static/../sites/default-site/templates/base.tmpl", code: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.URL.Path = tt.path
+		rr := httptest.NewRecorder()
+		srv.handleStatic(rr, req)
+		if rr.Code != tt.code {
+			t.Fatalf("%s: expected %d, got %d", tt.path, tt.code, rr.Code)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
@@ -10,7 +11,9 @@ import (
 	"strings"
 	"time"
 
+	twitch "github.com/Its-donkey/Sharpen-live/internal/alert/platforms/twitch/api"
 	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/webhooks"
 )
 
 // EventSub message types
@@ -26,6 +29,10 @@ const (
 	eventTypeStreamOffline = "stream.offline"
 )
 
+// twitchDetailsTimeout bounds the stream lookup made for each stream.online
+// event, which Twitch expects to be acknowledged within a few seconds.
+const twitchDetailsTimeout = 3 * time.Second
+
 // EventSubHeaders contains the standard Twitch EventSub headers
 type EventSubHeaders struct {
 	MessageID        string
@@ -227,12 +234,19 @@ func (s *server) handleStreamOnline(notification EventSubNotification) {
 		"startedAt":        event.StartedAt,
 	})
 
+	stream := streamers.TwitchStatus{StreamID: event.ID, StartedAt: event.StartedAt, Type: event.Type}
+	s.twitchStreamDetails(event.BroadcasterUserID, &stream)
+
 	// Find and update streamer across all sites
-	stores := s.getAllStreamerStores()
 	found := false
-	for siteKey, store := range stores {
-		_, err := store.SetTwitchLive(event.BroadcasterUserID, event.ID, event.StartedAt)
+	for _, ref := range s.channels().Twitch(event.BroadcasterUserID) {
+		siteKey, store := ref.Site, ref.Store
+		prior, _ := store.GetByTwitchBroadcasterID(event.BroadcasterUserID)
+		updated, err := store.SetTwitchStream(event.BroadcasterUserID, stream)
 		if err == nil {
+			if prior.Status == nil || prior.Status.Twitch == nil || !prior.Status.Twitch.Live || prior.Status.Twitch.StreamID != event.ID {
+				emitRosterEvent(store, webhooks.EventStreamerLive, updated, "twitch")
+			}
 			found = true
 			s.logger.Info("twitch-eventsub", "Updated streamer to live", map[string]any{
 				"broadcasterID":   event.BroadcasterUserID,
@@ -258,6 +272,29 @@ func (s *server) handleStreamOnline(notification EventSubNotification) {
 	}
 }
 
+// twitchStreamDetails adds the title and tags, which stream.online events do
+// not carry, to a stream that just went live. It is best effort: without API
+// credentials, or when Twitch is slow, the stream is left as it is.
+func (s *server) twitchStreamDetails(broadcasterID string, stream *streamers.TwitchStatus) {
+	if s.twitchConfig.ClientID == "" || s.twitchConfig.ClientSecret == "" {
+		return
+	}
+	httpClient := &http.Client{Timeout: twitchDetailsTimeout}
+	auth := twitch.NewAuthenticator(httpClient, s.twitchConfig.ClientID, s.twitchConfig.ClientSecret)
+	ctx, cancel := context.WithTimeout(context.Background(), twitchDetailsTimeout)
+	defer cancel()
+	result, err := twitch.IsLive(ctx, httpClient, auth, broadcasterID)
+	if err != nil {
+		s.logger.Warn("twitch-eventsub", "Failed to fetch stream details", map[string]any{
+			"broadcasterID": broadcasterID,
+			"error":         err.Error(),
+		})
+		return
+	}
+	stream.Title = result.Title
+	stream.Tags = result.Tags
+}
+
 // handleStreamOffline processes stream.offline events
 func (s *server) handleStreamOffline(notification EventSubNotification) {
 	var event StreamOfflineEvent
@@ -273,11 +310,15 @@ func (s *server) handleStreamOffline(notification EventSubNotification) {
 	})
 
 	// Find and update streamer across all sites
-	stores := s.getAllStreamerStores()
 	found := false
-	for siteKey, store := range stores {
-		_, err := store.ClearTwitchLive(event.BroadcasterUserID)
+	for _, ref := range s.channels().Twitch(event.BroadcasterUserID) {
+		siteKey, store := ref.Site, ref.Store
+		prior, _ := store.GetByTwitchBroadcasterID(event.BroadcasterUserID)
+		updated, err := store.ClearTwitchLive(event.BroadcasterUserID)
 		if err == nil {
+			if prior.Status != nil && prior.Status.Twitch != nil && prior.Status.Twitch.Live {
+				emitRosterEvent(store, webhooks.EventStreamerOffline, updated, "twitch")
+			}
 			found = true
 			s.logger.Info("twitch-eventsub", "Updated streamer to offline", map[string]any{
 				"broadcasterID":   event.BroadcasterUserID,
@@ -322,8 +363,8 @@ func (s *server) handleEventSubRevocation(w http.ResponseWriter, headers EventSu
 
 	// Update the streamer's EventSub status if we can identify them
 	if broadcasterID != "" {
-		stores := s.getAllStreamerStores()
-		for siteKey, store := range stores {
+		for _, ref := range s.channels().Twitch(broadcasterID) {
+			siteKey, store := ref.Site, ref.Store
 			record, err := store.GetByTwitchBroadcasterID(broadcasterID)
 			if err == nil && record.Platforms.Twitch != nil {
 				// Clear the subscription IDs to indicate it needs resubscription
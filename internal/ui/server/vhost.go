@@ -0,0 +1,228 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/config"
+)
+
+// VirtualHostOptions configures a single listener that serves several sites.
+type VirtualHostOptions struct {
+	// Listen is the shared listen address. When empty it is resolved from the
+	// config's virtual_hosts block, falling back to the base server block.
+	Listen     string
+	ConfigPath string
+	// Sites lists the per-site options to mount. Each site keeps its own
+	// templates, data root and logger.
+	Sites []Options
+	// Fallback serves requests whose Host matches no site. When nil the
+	// default-site (alertserver) is built from ConfigPath.
+	Fallback *Options
+}
+
+// RunVirtualHosts serves every site in opts.Sites from one listener, routing
+// requests by Host header and falling back to the default-site for unknown
+// hosts.
+func RunVirtualHosts(ctx context.Context, opts VirtualHostOptions) error {
+	if opts.ConfigPath == "" {
+		opts.ConfigPath = "config.json"
+	}
+	listen := strings.TrimSpace(opts.Listen)
+	if listen == "" {
+		cfg, err := config.Load(opts.ConfigPath)
+		if err != nil {
+			cfg = config.DefaultConfig()
+		}
+		listen = virtualHostListenAddr(cfg)
+	}
+
+	var (
+		builtMu sync.Mutex
+		built   []*Site
+	)
+	track := func(site *Site) {
+		builtMu.Lock()
+		built = append(built, site)
+		builtMu.Unlock()
+	}
+	defer func() {
+		builtMu.Lock()
+		defer builtMu.Unlock()
+		for _, site := range built {
+			site.Close()
+		}
+	}()
+
+	router := newHostRouter()
+	// Sites created from the admin wizard join the shared listener by host.
+	var launch SiteLauncher
+	launch = func(key string) error {
+		site, err := NewSite(ctx, Options{ConfigPath: opts.ConfigPath, Site: key, LaunchSite: launch})
+		if err != nil {
+			return err
+		}
+		if len(site.Hosts) == 0 {
+			site.Close()
+			return fmt.Errorf("site %q needs server.hosts in virtual-host mode", key)
+		}
+		if err := router.addAll(site.Hosts, site.Handler); err != nil {
+			site.Close()
+			return fmt.Errorf("site %q: %w", key, err)
+		}
+		track(site)
+		return nil
+	}
+
+	for _, siteOpts := range opts.Sites {
+		if siteOpts.ConfigPath == "" {
+			siteOpts.ConfigPath = opts.ConfigPath
+		}
+		if siteOpts.LaunchSite == nil {
+			siteOpts.LaunchSite = launch
+		}
+		site, err := NewSite(ctx, siteOpts)
+		if err != nil {
+			return fmt.Errorf("build site %q: %w", siteOpts.Site, err)
+		}
+		track(site)
+		if len(site.Hosts) == 0 {
+			// Without hosts the site is unreachable on the shared listener.
+			site.logger.Warn("vhost", "Site has no server.hosts configured; skipping in virtual-host mode", map[string]any{
+				"site": site.Key,
+			})
+			continue
+		}
+		for _, host := range site.Hosts {
+			if err := router.add(host, site.Handler); err != nil {
+				return fmt.Errorf("site %q: %w", site.Key, err)
+			}
+		}
+	}
+
+	fallbackOpts := Options{Site: config.AlertserverKey, ConfigPath: opts.ConfigPath}
+	if opts.Fallback != nil {
+		fallbackOpts = *opts.Fallback
+	}
+	if fallbackOpts.LaunchSite == nil {
+		fallbackOpts.LaunchSite = launch
+	}
+	fallback, err := NewSite(ctx, fallbackOpts)
+	if err != nil {
+		return fmt.Errorf("build fallback site: %w", err)
+	}
+	track(fallback)
+	router.fallback = fallback.Handler
+
+	return serve(ctx, listen, router)
+}
+
+// virtualHostListenAddr resolves the shared listener address from config.
+func virtualHostListenAddr(cfg config.Config) string {
+	addr := strings.TrimSpace(cfg.VirtualHosts.Addr)
+	port := strings.TrimSpace(cfg.VirtualHosts.Port)
+	if addr == "" && port == "" {
+		addr = strings.TrimSpace(cfg.Server.Addr)
+		port = strings.TrimSpace(cfg.Server.Port)
+	}
+	return joinListenAddr(addr, port)
+}
+
+// hostRouter dispatches requests to a site handler keyed by Host header.
+// Hosts may be added while serving when a new site is launched.
+type hostRouter struct {
+	mu       sync.RWMutex
+	hosts    map[string]http.Handler
+	fallback http.Handler
+}
+
+// errDuplicateHost indicates two sites claimed the same host name.
+var errDuplicateHost = errors.New("host already assigned to another site")
+
+func newHostRouter() *hostRouter {
+	return &hostRouter{hosts: make(map[string]http.Handler)}
+}
+
+func (h *hostRouter) add(host string, handler http.Handler) error {
+	key := normaliseHost(host)
+	if key == "" {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, exists := h.hosts[key]; exists {
+		return fmt.Errorf("%w: %s", errDuplicateHost, key)
+	}
+	h.hosts[key] = handler
+	return nil
+}
+
+// addAll registers every host for handler, or none if any is already taken.
+func (h *hostRouter) addAll(hosts []string, handler http.Handler) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, host := range hosts {
+		if key := normaliseHost(host); key != "" {
+			if _, exists := h.hosts[key]; exists {
+				return fmt.Errorf("%w: %s", errDuplicateHost, key)
+			}
+		}
+	}
+	for _, host := range hosts {
+		if key := normaliseHost(host); key != "" {
+			h.hosts[key] = handler
+		}
+	}
+	return nil
+}
+
+// ServeHTTP looks up the handler for the request host in O(1).
+func (h *hostRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.RLock()
+	handler, ok := h.hosts[normaliseHost(r.Host)]
+	h.mu.RUnlock()
+	if ok {
+		handler.ServeHTTP(w, r)
+		return
+	}
+	if h.fallback != nil {
+		h.fallback.ServeHTTP(w, r)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// normaliseHost lowercases a host and strips any port and trailing dot.
+func normaliseHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if host == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimPrefix(strings.TrimSuffix(host, "]"), "[")
+	return strings.TrimSuffix(host, ".")
+}
+
+func normaliseHosts(hosts []string) []string {
+	var out []string
+	seen := make(map[string]struct{}, len(hosts))
+	for _, host := range hosts {
+		key := normaliseHost(host)
+		if key == "" {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, key)
+	}
+	return out
+}
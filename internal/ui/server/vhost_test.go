@@ -0,0 +1,91 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHostRouterDispatchesByHost(t *testing.T) {
+	router := newHostRouter()
+	if err := router.add("Sharpen.Live", namedHandler("sharpen")); err != nil {
+		t.Fatalf("add host: %v", err)
+	}
+	if err := router.add("synth.wave", namedHandler("synth")); err != nil {
+		t.Fatalf("add host: %v", err)
+	}
+	router.fallback = namedHandler("fallback")
+
+	tests := []struct {
+		name string
+		host string
+		want string
+	}{
+		{name: "exact", host: "synth.wave", want: "synth"},
+		{name: "case insensitive", host: "SHARPEN.live", want: "sharpen"},
+		{name: "strips port", host: "sharpen.live:8443", want: "sharpen"},
+		{name: "trailing dot", host: "synth.wave.", want: "synth"},
+		{name: "unknown host", host: "example.com", want: "fallback"},
+		{name: "empty host", host: "", want: "fallback"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Host = tt.host
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			body, _ := io.ReadAll(rr.Body)
+			if string(body) != tt.want {
+				t.Fatalf("host %q routed to %q, want %q", tt.host, body, tt.want)
+			}
+		})
+	}
+}
+
+func TestHostRouterRejectsDuplicateHosts(t *testing.T) {
+	router := newHostRouter()
+	if err := router.add("sharpen.live", namedHandler("one")); err != nil {
+		t.Fatalf("add host: %v", err)
+	}
+	err := router.add("Sharpen.Live:443", namedHandler("two"))
+	if !errors.Is(err, errDuplicateHost) {
+		t.Fatalf("expected duplicate host error, got %v", err)
+	}
+}
+
+func TestHostRouterWithoutFallbackReturnsNotFound(t *testing.T) {
+	router := newHostRouter()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Host = "unknown.test"
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+}
+
+func TestNormaliseHostsDedupes(t *testing.T) {
+	got := normaliseHosts([]string{"Sharpen.Live", " sharpen.live:80 ", "", "www.sharpen.live"})
+	want := []string{"sharpen.live", "www.sharpen.live"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func namedHandler(name string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, name)
+	})
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/webhooks"
+)
+
+// webhookLogSize is how many delivery attempts the admin dashboard lists.
+const webhookLogSize = 20
+
+// webhookData is the payload data for streamer events.
+func webhookData(record streamers.Record, platform string) map[string]any {
+	data := map[string]any{"streamer": publicStreamerFromRecord(record)}
+	if platform != "" {
+		data["platform"] = platform
+	}
+	return data
+}
+
+// streamerRecord returns the site's roster record for id, so deletions can
+// describe the streamer after it is gone.
+func (s *server) streamerRecord(id string) (streamers.Record, bool) {
+	if s.streamersStore == nil {
+		return streamers.Record{}, false
+	}
+	records, err := s.streamersStore.List()
+	if err != nil {
+		return streamers.Record{}, false
+	}
+	for _, record := range records {
+		if strings.EqualFold(record.Streamer.ID, id) {
+			return record, true
+		}
+	}
+	return streamers.Record{}, false
+}
+
+// emitWebhook queues event on this site's endpoints.
+func (s *server) emitWebhook(event string, record streamers.Record, platform string) {
+	if s.webhooks == nil {
+		return
+	}
+	if err := s.webhooks.Emit(event, webhookData(record, platform)); err != nil {
+		s.logger.Warn("webhooks", "Failed to queue webhook", map[string]any{
+			"event":    event,
+			"streamer": record.Streamer.ID,
+			"error":    err.Error(),
+		})
+	}
+}
+
+// handleAdminWebhooks registers and removes the site's webhook endpoints.
+func (s *server) handleAdminWebhooks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/admin", http.StatusSeeOther)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		s.redirectAdmin(w, r, "", s.t(r, "Invalid webhook request."))
+		return
+	}
+	if s.adminTokenFromRequest(r) == "" {
+		s.redirectAdmin(w, r, "", s.t(r, "Log in to manage webhooks."))
+		return
+	}
+	store := s.webhooks.Store()
+	if store == nil {
+		s.redirectAdmin(w, r, "", s.t(r, "Webhooks are unavailable."))
+		return
+	}
+	switch r.FormValue("action") {
+	case "create":
+		endpoint, err := store.AddEndpoint(webhooks.Endpoint{
+			URL:    r.FormValue("url"),
+			Events: r.Form["events"],
+			Secret: r.FormValue("secret"),
+		})
+		if err != nil {
+			s.redirectAdmin(w, r, "", s.t(r, "Failed to add webhook: %v", err))
+			return
+		}
+		s.logger.Info("admin", "webhook endpoint added", map[string]any{
+			"endpoint": endpoint.ID,
+			"url":      endpoint.URL,
+		})
+		s.redirectAdmin(w, r, s.t(r, "Webhook added."), "")
+	case "delete":
+		id := strings.TrimSpace(r.FormValue("id"))
+		if err := store.RemoveEndpoint(id); err != nil {
+			s.redirectAdmin(w, r, "", s.t(r, "Failed to remove webhook: %v", err))
+			return
+		}
+		s.logger.Info("admin", "webhook endpoint removed", map[string]any{
+			"endpoint": id,
+		})
+		s.redirectAdmin(w, r, s.t(r, "Webhook removed."), "")
+	default:
+		s.redirectAdmin(w, r, "", s.t(r, "Unknown webhook action."))
+	}
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	adminauth "github.com/Its-donkey/Sharpen-live/internal/alert/admin/auth"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/webhooks"
+)
+
+// queuedWebhooks returns the deliveries waiting in store.
+func queuedWebhooks(t *testing.T, store *webhooks.Store) []webhooks.Delivery {
+	t.Helper()
+	due, _, _, err := store.Due(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("due: %v", err)
+	}
+	return due
+}
+
+func webhookTestDispatcher(t *testing.T, site string) *webhooks.Dispatcher {
+	t.Helper()
+	store := webhooks.NewStore(filepath.Join(t.TempDir(), "webhooks.json"))
+	if _, err := store.AddEndpoint(webhooks.Endpoint{URL: "https://hooks.example.com/" + site}); err != nil {
+		t.Fatalf("add endpoint: %v", err)
+	}
+	return webhooks.NewDispatcher(store, webhooks.Options{Site: site})
+}
+
+func TestAdminWebhooks(t *testing.T) {
+	srv := newTestServer()
+	srv.adminManager = &stubAdminManager{valid: true, token: adminauth.Token{Value: "tok"}}
+	srv.webhooks = webhooks.NewDispatcher(webhooks.NewStore(filepath.Join(t.TempDir(), "webhooks.json")), webhooks.Options{})
+	post := func(form url.Values, loggedIn bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/admin/webhooks", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if loggedIn {
+			req.AddCookie(&http.Cookie{Name: adminCookieName, Value: "tok"})
+		}
+		rr := httptest.NewRecorder()
+		srv.handleAdminWebhooks(rr, req)
+		return rr
+	}
+
+	if rr := post(url.Values{"action": {"create"}, "url": {"https://example.com/hook"}}, false); !strings.Contains(rr.Header().Get("Location"), "err=") {
+		t.Fatalf("expected logged-out requests to be rejected, got %s", rr.Header().Get("Location"))
+	}
+	if rr := post(url.Values{"action": {"create"}, "url": {"not a url"}}, true); !strings.Contains(rr.Header().Get("Location"), "err=") {
+		t.Fatalf("expected an invalid URL to be rejected, got %s", rr.Header().Get("Location"))
+	}
+	rr := post(url.Values{"action": {"create"}, "url": {"https://example.com/hook"}, "events": {webhooks.EventStreamerLive, webhooks.EventStreamerOffline}}, true)
+	if !strings.Contains(rr.Header().Get("Location"), "msg=") {
+		t.Fatalf("expected the endpoint to be added, got %s", rr.Header().Get("Location"))
+	}
+	endpoints, _ := srv.webhooks.Store().Endpoints()
+	if len(endpoints) != 1 || len(endpoints[0].Events) != 2 || endpoints[0].Secret == "" {
+		t.Fatalf("unexpected endpoints %+v", endpoints)
+	}
+
+	post(url.Values{"action": {"delete"}, "id": {endpoints[0].ID}}, true)
+	if endpoints, _ := srv.webhooks.Store().Endpoints(); len(endpoints) != 0 {
+		t.Fatalf("expected the endpoint to be removed, got %+v", endpoints)
+	}
+}
+
+func TestStreamerDeleteQueuesWebhook(t *testing.T) {
+	srv := newTestServer()
+	srv.adminManager = &stubAdminManager{valid: true, token: adminauth.Token{Value: "tok"}}
+	srv.streamersStore = &stubStreamersStore{records: apiTestRecords()}
+	srv.webhooks = webhookTestDispatcher(t, "synth-wave")
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/streamers/delete", strings.NewReader("id=c"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(&http.Cookie{Name: adminCookieName, Value: "tok"})
+	srv.handleAdminStreamerDelete(httptest.NewRecorder(), req)
+
+	queued := queuedWebhooks(t, srv.webhooks.Store())
+	if len(queued) != 1 || queued[0].Event != webhooks.EventStreamerDeleted {
+		t.Fatalf("expected one deletion delivery, got %+v", queued)
+	}
+	var payload struct {
+		Site string `json:"site"`
+		Data struct {
+			Streamer publicStreamer `json:"streamer"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(queued[0].Payload, &payload); err != nil || payload.Site != "synth-wave" || payload.Data.Streamer.Streamer.Alias != "Charlie" {
+		t.Fatalf("unexpected payload %s (%v)", queued[0].Payload, err)
+	}
+	if strings.Contains(string(queued[0].Payload), "hub-secret") {
+		t.Fatalf("expected the payload to use the public streamer shape")
+	}
+}
+
+func TestTwitchEventsQueueWebhooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "synth-wave", "streamers.json")
+	store := streamers.NewStore(path)
+	if _, err := store.Append(streamers.Record{
+		Streamer:  streamers.Streamer{ID: "a", Alias: "alpha", Languages: []string{"English"}},
+		Platforms: streamers.Platforms{Twitch: &streamers.TwitchPlatform{Username: "alpha", BroadcasterID: "42"}},
+		Sites:     []string{"synth-wave"},
+	}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	srv := newTestServer()
+	srv.siteKey = "synth-wave"
+	srv.streamersStore = store
+	srv.storeCache = map[string]*streamers.Store{}
+	listed := webhookTestDispatcher(t, "synth-wave")
+	srv.webhooks = listed
+	defer registerSite(srv)()
+
+	// Shares the roster file but does not list the streamer.
+	otherSrv := newTestServer()
+	otherSrv.siteKey = "sharpen-live"
+	otherSrv.streamersStore = store
+	other := webhookTestDispatcher(t, "sharpen-live")
+	otherSrv.webhooks = other
+	defer registerSite(otherSrv)()
+
+	notify := func(event any) EventSubNotification {
+		raw, _ := json.Marshal(event)
+		return EventSubNotification{Event: raw}
+	}
+	online := notify(StreamOnlineEvent{ID: "stream-1", BroadcasterUserID: "42", StartedAt: time.Now()})
+	srv.handleStreamOnline(online)
+	srv.handleStreamOnline(online)
+	srv.handleStreamOffline(notify(StreamOfflineEvent{BroadcasterUserID: "42"}))
+	srv.handleStreamOffline(notify(StreamOfflineEvent{BroadcasterUserID: "42"}))
+
+	queued := queuedWebhooks(t, listed.Store())
+	if len(queued) != 2 || queued[0].Event != webhooks.EventStreamerLive || queued[1].Event != webhooks.EventStreamerOffline {
+		t.Fatalf("expected one live and one offline delivery, got %+v", queued)
+	}
+	var payload struct {
+		Data struct {
+			Platform string `json:"platform"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(queued[0].Payload, &payload); err != nil || payload.Data.Platform != "twitch" {
+		t.Fatalf("expected the platform in the payload, got %s (%v)", queued[0].Payload, err)
+	}
+	if queued := queuedWebhooks(t, other.Store()); len(queued) != 0 {
+		t.Fatalf("expected sites that do not list the streamer to get nothing, got %+v", queued)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/Its-donkey/Sharpen-live/internal/alert/platforms/youtube/api"
 	"github.com/Its-donkey/Sharpen-live/internal/alert/platforms/youtube/websub"
 	"github.com/Its-donkey/Sharpen-live/internal/alert/streamers"
+	"github.com/Its-donkey/Sharpen-live/internal/alert/webhooks"
 )
 
 // handleYouTubeWebSub handles WebSub verification and notifications from YouTube
@@ -218,110 +219,97 @@ func (s *server) handleWebSubNotification(w http.ResponseWriter, r *http.Request
 	}
 
 	// Find the streamer across all sites - WebSub notifications are shared across all sites
-	// so we need to check all streamer stores, not just the current site's
-	stores := s.getAllStreamerStores()
-	fmt.Printf("INFO: Searching across %d site(s) for channel ID: %s\n", len(stores), channelID)
+	// so the channel index covers every site's store, not just the current site's
+	refs := s.channels().YouTube(channelID)
+	fmt.Printf("INFO: Channel index matched %d streamer(s) for channel ID: %s\n", len(refs), channelID)
 
 	found := false
-	for siteKey, store := range stores {
-		records, err := store.List()
-		if err != nil {
-			fmt.Printf("WARNING: Failed to list streamers for site %s: %v\n", siteKey, err)
-			s.logger.Warn("websub", "Failed to list streamers for site", map[string]any{
-				"site":      siteKey,
-				"channelId": channelID,
-				"error":     err.Error(),
-			})
+	for _, ref := range refs {
+		siteKey, store := ref.Site, ref.Store
+		record, err := store.Shared().Get(ref.StreamerID)
+		if err != nil || record.Platforms.YouTube == nil {
+			fmt.Printf("WARNING: Indexed streamer %s missing from site %s: %v\n", ref.StreamerID, siteKey, err)
 			continue
 		}
 
-		fmt.Printf("INFO: Checking %d streamer(s) in site '%s'\n", len(records), siteKey)
-		for _, record := range records {
-			if record.Platforms.YouTube != nil && record.Platforms.YouTube.ChannelID == channelID {
-				fmt.Printf("INFO: Found matching streamer: %s (ID: %s) in site '%s'\n", record.Streamer.Alias, record.Streamer.ID, siteKey)
-
-				// Verify signature if we have a secret stored
-				if record.Platforms.YouTube.WebSubSecret != "" {
-					// Secret exists - signature verification is REQUIRED
-					if signature == "" {
-						fmt.Printf("WARNING: Streamer has secret but notification has no signature, rejecting for site '%s'\n", siteKey)
-						s.logger.Warn("websub", "Missing signature for streamer with secret", map[string]any{
-							"streamerId": record.Streamer.ID,
-							"channelId":  channelID,
-							"site":       siteKey,
-						})
-						continue
-					}
-
-					if !websub.VerifySignature(body, signature, record.Platforms.YouTube.WebSubSecret) {
-						fmt.Printf("WARNING: Signature verification failed for site '%s', checking other sites\n", siteKey)
-						s.logger.Warn("websub", "Signature verification failed, continuing search", map[string]any{
-							"streamerId": record.Streamer.ID,
-							"channelId":  channelID,
-							"site":       siteKey,
-						})
-						// Continue to check other sites - the streamer might exist in multiple sites
-						// with different secrets, and we need to find the one with the matching secret
-						continue
-					}
-					fmt.Printf("INFO: Signature verified successfully for site '%s'\n", siteKey)
-				} else {
-					// No secret stored - this is likely a legacy streamer from before WebSubSecret was added
-					// Accept the notification but log a warning
-					fmt.Printf("WARNING: No WebSubSecret stored for streamer '%s' - accepting without verification\n", record.Streamer.Alias)
-					s.logger.Warn("websub", "Processing notification without signature verification - no secret stored", map[string]any{
-						"streamerId": record.Streamer.ID,
-						"alias":      record.Streamer.Alias,
-						"channelId":  channelID,
-						"site":       siteKey,
-						"note":       "Resubscribe this streamer to enable signature verification",
-					})
-				}
+		fmt.Printf("INFO: Found matching streamer: %s (ID: %s) in site '%s'\n", record.Streamer.Alias, record.Streamer.ID, siteKey)
 
-				found = true
-
-				// Check if YouTube is enabled for this specific site
-				if !isYouTubeEnabledForSiteKey(s.configPath, siteKey) {
-					fmt.Printf("INFO: YouTube disabled for site '%s', skipping API calls\n", siteKey)
-					s.logger.Info("websub", "YouTube disabled for site, skipping API calls", map[string]any{
-						"streamerId": record.Streamer.ID,
-						"alias":      record.Streamer.Alias,
-						"channelId":  channelID,
-						"site":       siteKey,
-					})
-					break
-				}
-
-				s.logger.Info("websub", "Processing notification for streamer", map[string]any{
+		// Verify signature if we have a secret stored
+		if record.Platforms.YouTube.WebSubSecret != "" {
+			// Secret exists - signature verification is REQUIRED
+			if signature == "" {
+				fmt.Printf("WARNING: Streamer has secret but notification has no signature, rejecting for site '%s'\n", siteKey)
+				s.logger.Warn("websub", "Missing signature for streamer with secret", map[string]any{
 					"streamerId": record.Streamer.ID,
-					"alias":      record.Streamer.Alias,
 					"channelId":  channelID,
-					"videoId":    feed.VideoID,
 					"site":       siteKey,
 				})
+				continue
+			}
 
-				// Check live status using YouTube API
-				if feed.VideoID != "" {
-					fmt.Printf("\nINFO: Checking live status for video %s\n", feed.VideoID)
-					// Use the store from the site where we found the streamer
-					if err := s.checkAndUpdateLiveStatusWithStore(r.Context(), store, channelID, feed.VideoID, feed.Published); err != nil {
-						fmt.Printf("WARNING: Failed to check/update live status: %v\n", err)
-						s.logger.Warn("websub", "Failed to update live status", map[string]any{
-							"error":     err.Error(),
-							"channelId": channelID,
-							"videoId":   feed.VideoID,
-							"site":      siteKey,
-						})
-					}
-				}
-
-				break
+			if !websub.VerifySignature(body, signature, record.Platforms.YouTube.WebSubSecret) {
+				fmt.Printf("WARNING: Signature verification failed for site '%s', checking other sites\n", siteKey)
+				s.logger.Warn("websub", "Signature verification failed, continuing search", map[string]any{
+					"streamerId": record.Streamer.ID,
+					"channelId":  channelID,
+					"site":       siteKey,
+				})
+				// Continue to check other sites - the streamer might exist in multiple sites
+				// with different secrets, and we need to find the one with the matching secret
+				continue
 			}
+			fmt.Printf("INFO: Signature verified successfully for site '%s'\n", siteKey)
+		} else {
+			// No secret stored - this is likely a legacy streamer from before WebSubSecret was added
+			// Accept the notification but log a warning
+			fmt.Printf("WARNING: No WebSubSecret stored for streamer '%s' - accepting without verification\n", record.Streamer.Alias)
+			s.logger.Warn("websub", "Processing notification without signature verification - no secret stored", map[string]any{
+				"streamerId": record.Streamer.ID,
+				"alias":      record.Streamer.Alias,
+				"channelId":  channelID,
+				"site":       siteKey,
+				"note":       "Resubscribe this streamer to enable signature verification",
+			})
 		}
 
-		if found {
+		found = true
+
+		// Check if YouTube is enabled for this specific site
+		if !isYouTubeEnabledForSiteKey(s.configPath, siteKey) {
+			fmt.Printf("INFO: YouTube disabled for site '%s', skipping API calls\n", siteKey)
+			s.logger.Info("websub", "YouTube disabled for site, skipping API calls", map[string]any{
+				"streamerId": record.Streamer.ID,
+				"alias":      record.Streamer.Alias,
+				"channelId":  channelID,
+				"site":       siteKey,
+			})
 			break
 		}
+
+		s.logger.Info("websub", "Processing notification for streamer", map[string]any{
+			"streamerId": record.Streamer.ID,
+			"alias":      record.Streamer.Alias,
+			"channelId":  channelID,
+			"videoId":    feed.VideoID,
+			"site":       siteKey,
+		})
+
+		// Check live status using YouTube API
+		if feed.VideoID != "" {
+			fmt.Printf("\nINFO: Checking live status for video %s\n", feed.VideoID)
+			// Use the store from the site where we found the streamer
+			if err := s.checkAndUpdateLiveStatusWithStore(r.Context(), store, channelID, feed.VideoID, feed.Published); err != nil {
+				fmt.Printf("WARNING: Failed to check/update live status: %v\n", err)
+				s.logger.Warn("websub", "Failed to update live status", map[string]any{
+					"error":     err.Error(),
+					"channelId": channelID,
+					"videoId":   feed.VideoID,
+					"site":      siteKey,
+				})
+			}
+		}
+
+		break
 	}
 
 	if !found {
@@ -422,7 +410,7 @@ func (s *server) checkAndUpdateLiveStatus(ctx context.Context, channelID, videoI
 		fmt.Printf("SUCCESS: Video %s is LIVE\n", videoID)
 		fmt.Printf("  Started at: %s\n", result.StartedAt.Format("2006-01-02 15:04:05 MST"))
 
-		_, err := store.SetYouTubeLive(channelID, videoID, result.StartedAt)
+		_, err := store.SetYouTubeStream(channelID, streamers.YouTubeStatus{VideoID: videoID, StartedAt: result.StartedAt, Title: result.Title})
 		if err != nil {
 			return fmt.Errorf("set live status: %w", err)
 		}
@@ -431,7 +419,7 @@ func (s *server) checkAndUpdateLiveStatus(ctx context.Context, channelID, videoI
 		// Channel is live but with a different video
 		fmt.Printf("INFO: Channel is live with different video: %s (notification was for %s)\n", result.VideoID, videoID)
 
-		_, err := store.SetYouTubeLive(channelID, result.VideoID, result.StartedAt)
+		_, err := store.SetYouTubeStream(channelID, streamers.YouTubeStatus{VideoID: result.VideoID, StartedAt: result.StartedAt, Title: result.Title})
 		if err != nil {
 			return fmt.Errorf("set live status: %w", err)
 		}
@@ -584,7 +572,7 @@ func (s *server) checkAllStreamersLiveStatus(ctx context.Context) {
 				fmt.Printf("SUCCESS: Streamer %s is LIVE with video %s\n", rec.Streamer.Alias, liveResult.VideoID)
 				fmt.Printf("  Started at: %s\n", liveResult.StartedAt.Format("2006-01-02 15:04:05 MST"))
 
-				_, err := store.SetYouTubeLive(channelID, liveResult.VideoID, liveResult.StartedAt)
+				_, err := store.SetYouTubeStream(channelID, streamers.YouTubeStatus{VideoID: liveResult.VideoID, StartedAt: liveResult.StartedAt, Title: liveResult.Title})
 				if err != nil {
 					fmt.Printf("ERROR: Failed to set live status for %s: %v\n", rec.Streamer.Alias, err)
 					s.logger.Error("startup", "Failed to set initial live status", err, map[string]any{
@@ -726,7 +714,12 @@ func (s *server) checkAllTwitchStreamersLiveStatus(ctx context.Context) {
 				"site":          s.siteKey,
 			})
 
-			_, err := store.SetTwitchLive(broadcasterID, streamResult.StreamID, streamResult.StartedAt)
+			_, err := store.SetTwitchStream(broadcasterID, streamers.TwitchStatus{
+				StreamID:  streamResult.StreamID,
+				StartedAt: streamResult.StartedAt,
+				Title:     streamResult.Title,
+				Tags:      streamResult.Tags,
+			})
 			if err != nil {
 				s.logger.Error("twitch-startup", "Failed to set Twitch live status", err, map[string]any{
 					"streamerId":    record.Streamer.ID,
@@ -764,10 +757,39 @@ func (s *server) checkAllTwitchStreamersLiveStatus(ctx context.Context) {
 	})
 }
 
+// channels returns the index mapping channel and broadcaster IDs to streamers
+// across every site's store.
+func (s *server) channels() *streamers.ChannelIndex {
+	s.channelIndexOnce.Do(func() {
+		if s.channelIndex == nil {
+			s.channelIndex = streamers.NewChannelIndex(s.getAllStreamerStores)
+		}
+	})
+	return s.channelIndex
+}
+
+// rosterStore returns the store for the site's roster file, covering every
+// site's records when the roster is shared.
+func (s *server) rosterStore() *streamers.Store {
+	store, ok := s.streamersStore.(*streamers.Store)
+	if !ok {
+		return nil
+	}
+	if s.sharedRoster {
+		return store.Shared()
+	}
+	return store
+}
+
 // getAllStreamerStores returns all streamer stores across all sites.
 // Uses a cache to ensure concurrent WebSub notifications use the same store instances,
 // preventing race conditions when updating the same site's streamers.json file.
 func (s *server) getAllStreamerStores() map[string]*streamers.Store {
+	// A shared roster already holds every site's records in one file.
+	if store := s.rosterStore(); store != nil && s.sharedRoster {
+		return map[string]*streamers.Store{"shared": store}
+	}
+
 	// First, try to read from cache with read lock
 	s.storeCacheMu.RLock()
 	if len(s.storeCache) > 0 {
@@ -827,7 +849,7 @@ func (s *server) getAllStreamerStores() map[string]*streamers.Store {
 
 		streamersPath := filepath.Join(parentDir, siteKey, "streamers.json")
 		if _, err := os.Stat(streamersPath); err == nil {
-			s.storeCache[siteKey] = streamers.NewStore(streamersPath)
+			s.storeCache[siteKey] = streamers.SharedStore(streamersPath)
 		}
 	}
 
@@ -868,35 +890,50 @@ func (s *server) checkAndUpdateLiveStatusWithStore(ctx context.Context, store *s
 		return fmt.Errorf("check live status: %w", err)
 	}
 
+	// The prior status decides whether this check is a live or offline event.
+	var prior *streamers.YouTubeStatus
+	if record, err := store.FindByChannel(channelID, ""); err == nil && record.Status != nil {
+		prior = record.Status.YouTube
+	}
+
 	// Update status based on whether the channel is live
 	if result.VideoID != "" && result.VideoID == videoID {
 		// The video from the notification is currently live
 		fmt.Printf("SUCCESS: Video %s is LIVE\n", videoID)
 		fmt.Printf("  Started at: %s\n", result.StartedAt.Format("2006-01-02 15:04:05 MST"))
 
-		_, err := store.SetYouTubeLive(channelID, videoID, result.StartedAt)
+		updated, err := store.SetYouTubeStream(channelID, streamers.YouTubeStatus{VideoID: videoID, StartedAt: result.StartedAt, Title: result.Title})
 		if err != nil {
 			return fmt.Errorf("set live status: %w", err)
 		}
 		fmt.Printf("INFO: Updated streamer status to LIVE\n")
+		if prior == nil || !prior.Live || prior.VideoID != videoID {
+			emitRosterEvent(store, webhooks.EventStreamerLive, updated, "youtube")
+		}
 	} else if result.VideoID != "" {
 		// Channel is live but with a different video
 		fmt.Printf("INFO: Channel is live with different video: %s (notification was for %s)\n", result.VideoID, videoID)
 
-		_, err := store.SetYouTubeLive(channelID, result.VideoID, result.StartedAt)
+		updated, err := store.SetYouTubeStream(channelID, streamers.YouTubeStatus{VideoID: result.VideoID, StartedAt: result.StartedAt, Title: result.Title})
 		if err != nil {
 			return fmt.Errorf("set live status: %w", err)
 		}
 		fmt.Printf("INFO: Updated streamer status to LIVE with current video\n")
+		if prior == nil || !prior.Live || prior.VideoID != result.VideoID {
+			emitRosterEvent(store, webhooks.EventStreamerLive, updated, "youtube")
+		}
 	} else {
 		// Channel is not currently live - video may have ended or not started yet
 		fmt.Printf("INFO: Channel is not currently live (video %s may have ended or not started)\n", videoID)
 
-		_, err := store.ClearYouTubeLive(channelID)
+		updated, err := store.ClearYouTubeLive(channelID)
 		if err != nil {
 			return fmt.Errorf("clear live status: %w", err)
 		}
 		fmt.Printf("INFO: Updated streamer status to OFFLINE\n")
+		if prior != nil && prior.Live {
+			emitRosterEvent(store, webhooks.EventStreamerOffline, updated, "youtube")
+		}
 	}
 
 	return nil
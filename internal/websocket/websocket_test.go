@@ -0,0 +1,203 @@
+package websocket
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAcceptKey(t *testing.T) {
+	// Example from RFC 6455 section 1.3.
+	if got := AcceptKey("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("unexpected accept key %q", got)
+	}
+}
+
+func TestUpgradeRejectsBadHandshakes(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{"plain GET", nil, http.StatusBadRequest},
+		{"wrong version", map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "8", "Sec-WebSocket-Key": "dGhlIHNhbXBsZSBub25jZQ=="}, http.StatusUpgradeRequired},
+		{"bad key", map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "13", "Sec-WebSocket-Key": "short"}, http.StatusBadRequest},
+		{"origin", map[string]string{"Connection": "keep-alive, Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "13", "Sec-WebSocket-Key": "dGhlIHNhbXBsZSBub25jZQ==", "Origin": "https://evil.example"}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rr := httptest.NewRecorder()
+			_, err := Upgrade(rr, req, func(r *http.Request) bool { return r.Header.Get("Origin") == "" })
+			if err == nil || rr.Code != tt.want {
+				t.Fatalf("expected %d and an error, got %d (%v)", tt.want, rr.Code, err)
+			}
+		})
+	}
+}
+
+func TestConnEchoPingAndClose(t *testing.T) {
+	var pongs int
+	done := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r, nil)
+		if err != nil {
+			done <- err
+			return
+		}
+		conn.OnPong = func() { pongs++ }
+		if err := conn.Ping(); err != nil {
+			done <- err
+			return
+		}
+		for {
+			op, msg, err := conn.ReadMessage()
+			if err != nil {
+				done <- err
+				return
+			}
+			if err := conn.writeFrame(op, msg); err != nil {
+				done <- err
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	conn, br := testDial(t, srv.URL)
+	defer conn.Close()
+
+	if op, _ := testReadFrame(t, br); op != OpPing {
+		t.Fatalf("expected server ping, got opcode %d", op)
+	}
+	testWriteFrame(t, conn, true, OpPong, nil)
+
+	testWriteFrame(t, conn, true, OpText, []byte("hello"))
+	if op, msg := testReadFrame(t, br); op != OpText || string(msg) != "hello" {
+		t.Fatalf("expected echo, got %d %q", op, msg)
+	}
+
+	testWriteFrame(t, conn, false, OpText, []byte("frag"))
+	testWriteFrame(t, conn, true, OpPing, []byte("p"))
+	testWriteFrame(t, conn, true, OpContinuation, []byte("mented"))
+	if op, msg := testReadFrame(t, br); op != OpPong || string(msg) != "p" {
+		t.Fatalf("expected pong between fragments, got %d %q", op, msg)
+	}
+	if op, msg := testReadFrame(t, br); op != OpText || string(msg) != "fragmented" {
+		t.Fatalf("expected reassembled message, got %d %q", op, msg)
+	}
+
+	closePayload := binary.BigEndian.AppendUint16(nil, CloseGoingAway)
+	testWriteFrame(t, conn, true, OpClose, closePayload)
+	if op, msg := testReadFrame(t, br); op != OpClose || binary.BigEndian.Uint16(msg) != CloseGoingAway {
+		t.Fatalf("expected close echo, got %d %v", op, msg)
+	}
+	select {
+	case err := <-done:
+		var closeErr *CloseError
+		if !errors.As(err, &closeErr) || closeErr.Code != CloseGoingAway {
+			t.Fatalf("expected CloseError, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("server did not finish")
+	}
+	if pongs != 1 {
+		t.Fatalf("expected one pong, got %d", pongs)
+	}
+}
+
+func TestConnRejectsUnmaskedFrames(t *testing.T) {
+	done := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r, nil)
+		if err != nil {
+			done <- err
+			return
+		}
+		_, _, err = conn.ReadMessage()
+		done <- err
+	}))
+	defer srv.Close()
+
+	conn, br := testDial(t, srv.URL)
+	defer conn.Close()
+	if _, err := conn.Write([]byte{0x81, 0x01, 'x'}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if op, msg := testReadFrame(t, br); op != OpClose || binary.BigEndian.Uint16(msg) != CloseProtocolError {
+		t.Fatalf("expected protocol error close, got %d %v", op, msg)
+	}
+	if err := <-done; !errors.Is(err, ErrProtocol) {
+		t.Fatalf("expected protocol error, got %v", err)
+	}
+}
+
+func testDial(t *testing.T, serverURL string) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(serverURL, "http://"))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	req := "GET /ws HTTP/1.1\r\nHost: example\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n" +
+		"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: " + key + "\r\n\r\n"
+	if _, err := io.WriteString(conn, req); err != nil {
+		t.Fatalf("handshake: %v", err)
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatalf("read handshake: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != AcceptKey(key) {
+		t.Fatalf("unexpected handshake response %d %v", resp.StatusCode, resp.Header)
+	}
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	return conn, br
+}
+
+func testWriteFrame(t *testing.T, conn net.Conn, fin bool, op byte, payload []byte) {
+	t.Helper()
+	b0 := op
+	if fin {
+		b0 |= 0x80
+	}
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{b0, 0x80 | byte(len(payload))}
+	frame = append(frame, mask[:]...)
+	for i, c := range payload {
+		frame = append(frame, c^mask[i%4])
+	}
+	if _, err := conn.Write(frame); err != nil {
+		t.Fatalf("write frame: %v", err)
+	}
+}
+
+func testReadFrame(t *testing.T, br *bufio.Reader) (byte, []byte) {
+	t.Helper()
+	var header [2]byte
+	if _, err := io.ReadFull(br, header[:]); err != nil {
+		t.Fatalf("read frame: %v", err)
+	}
+	length := int(header[1] & 0x7f)
+	if length == 126 {
+		var ext [2]byte
+		io.ReadFull(br, ext[:])
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(br, payload); err != nil {
+		t.Fatalf("read payload: %v", err)
+	}
+	return header[0] & 0x0f, payload
+}
@@ -0,0 +1,50 @@
+// Package ui embeds the site themes and shared static files so the server
+// can run from a single binary. Files on disk under the configured theme
+// directories take precedence over the embedded copies.
+package ui
+
+import (
+	"embed"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Files holds the ui/sites and ui/static trees, rooted at "sites" and "static".
+//
+//go:embed sites static
+var Files embed.FS
+
+// Dump writes the embedded tree into dir so a theme can be customised on
+// disk. Existing files are left untouched and reported as skipped.
+func Dump(dir string) (written, skipped int, err error) {
+	err = fs.WalkDir(Files, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(path))
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		data, err := Files.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if errors.Is(err, fs.ErrExist) {
+			skipped++
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := out.Write(data); err != nil {
+			out.Close()
+			return err
+		}
+		written++
+		return out.Close()
+	})
+	return written, skipped, err
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFilesEmbedsThemes(t *testing.T) {
+	for _, path := range []string{
+		"sites/default-site/templates/base.tmpl",
+		"sites/sharpen-live/styles.css",
+		"sites/synth-wave/templates/home.tmpl",
+		"static/synthwave-logo-badge.svg",
+	} {
+		if _, err := fs.Stat(Files, path); err != nil {
+			t.Fatalf("expected %s embedded: %v", path, err)
+		}
+	}
+}
+
+func TestDumpKeepsExistingFiles(t *testing.T) {
+	dir := t.TempDir()
+	custom := filepath.Join(dir, "sites", "sharpen-live", "styles.css")
+	if err := os.MkdirAll(filepath.Dir(custom), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(custom, []byte("custom"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	written, skipped, err := Dump(dir)
+	if err != nil {
+		t.Fatalf("dump: %v", err)
+	}
+	if written == 0 || skipped != 1 {
+		t.Fatalf("expected files written and one skipped, got written=%d skipped=%d", written, skipped)
+	}
+	if data, _ := os.ReadFile(custom); string(data) != "custom" {
+		t.Fatalf("expected existing file untouched, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sites", "default-site", "templates", "admin.tmpl")); err != nil {
+		t.Fatalf("expected embedded template written: %v", err)
+	}
+}
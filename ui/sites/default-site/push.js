@@ -0,0 +1,95 @@
+(function () {
+  const buttons = document.querySelectorAll('[data-push-follow]');
+  if (!buttons.length) return;
+  if (!('serviceWorker' in navigator) || !('PushManager' in window) || !('Notification' in window)) return;
+
+  const storageKey = 'sharpen-push-follows';
+
+  const loadFollows = () => {
+    try {
+      const saved = JSON.parse(window.localStorage.getItem(storageKey) || '[]');
+      return Array.isArray(saved) ? saved : [];
+    } catch (err) {
+      return [];
+    }
+  };
+  const saveFollows = (ids) => {
+    try {
+      window.localStorage.setItem(storageKey, JSON.stringify(ids));
+    } catch (err) {
+      // Private browsing can refuse storage; the server copy still applies.
+    }
+  };
+
+  const urlBase64ToUint8Array = (value) => {
+    const padded = (value + '='.repeat((4 - (value.length % 4)) % 4)).replace(/-/g, '+').replace(/_/g, '/');
+    const raw = window.atob(padded);
+    return Uint8Array.from(raw, (c) => c.charCodeAt(0));
+  };
+
+  const subscription = async (create) => {
+    const registration = await navigator.serviceWorker.register('/push-sw.js');
+    const existing = await registration.pushManager.getSubscription();
+    if (existing || !create) return existing;
+    const res = await fetch('/push/key');
+    if (!res.ok) throw new Error('push key unavailable');
+    const { publicKey } = await res.json();
+    return registration.pushManager.subscribe({
+      userVisibleOnly: true,
+      applicationServerKey: urlBase64ToUint8Array(publicKey),
+    });
+  };
+
+  const sync = async (sub, ids) => {
+    const res = await fetch('/push/subscribe', {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify(Object.assign(sub.toJSON(), { streamers: ids })),
+    });
+    if (!res.ok) throw new Error('subscribe failed: ' + res.status);
+    const data = await res.json();
+    return Array.isArray(data.streamers) ? data.streamers : ids;
+  };
+
+  const render = (button, following) => {
+    button.setAttribute('aria-pressed', following ? 'true' : 'false');
+    button.textContent = following ? button.dataset.unfollowLabel : button.dataset.followLabel;
+  };
+
+  // Push services rotate subscriptions, so refresh the server's copy of the
+  // follow list whenever a page with follow buttons loads.
+  const follows = loadFollows();
+  if (follows.length && Notification.permission === 'granted') {
+    subscription(true)
+      .then((sub) => sync(sub, follows))
+      .then(saveFollows)
+      .catch((err) => {
+        if (window && window.console) console.warn('[push.js]', err);
+      });
+  }
+
+  buttons.forEach((button) => {
+    const id = button.dataset.pushFollow;
+    render(button, loadFollows().includes(id));
+    button.hidden = false;
+
+    button.addEventListener('click', async () => {
+      const follows = loadFollows();
+      const following = follows.includes(id);
+      const next = following ? follows.filter((f) => f !== id) : follows.concat(id);
+      button.disabled = true;
+      try {
+        if (!following && (await Notification.requestPermission()) !== 'granted') return;
+        const sub = await subscription(!following);
+        const saved = sub ? await sync(sub, next) : next;
+        if (sub && saved.length === 0) await sub.unsubscribe();
+        saveFollows(saved);
+        render(button, saved.includes(id));
+      } catch (err) {
+        if (window && window.console) console.warn('[push.js]', err);
+      } finally {
+        button.disabled = false;
+      }
+    });
+  });
+})();